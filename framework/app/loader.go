@@ -45,6 +45,9 @@ func (l *loader) Load() (state *State, err error) {
 	l.imports.AddNamed("log", "github.com/livebud/bud/package/log")
 	l.imports.AddNamed("filter", "github.com/livebud/bud/package/log/filter")
 	l.imports.Add(l.module.Import("bud/internal/app/web"))
+	if l.flag.Embed {
+		l.imports.AddNamed("session", "github.com/livebud/bud/package/session")
+	}
 	state.Provider = l.loadProvider()
 	state.Flag = l.flag
 	state.Imports = l.imports.List()
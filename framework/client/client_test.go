@@ -0,0 +1,122 @@
+package client_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/livebud/bud/internal/cli/testcli"
+	"github.com/livebud/bud/internal/is"
+	"github.com/livebud/bud/internal/testdir"
+)
+
+func TestNoControllers(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	result, err := cli.Run(ctx, "build")
+	is.NoErr(err)
+	is.Equal(result.Stderr(), "")
+	is.NoErr(td.NotExists("bud/client"))
+}
+
+func TestClient(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/users/controller.go"] = `
+		package users
+		import (
+			"context"
+			"io"
+			"time"
+		)
+		type Controller struct {}
+		type User struct {
+			ID        int       ` + "`" + `json:"id"` + "`" + `
+			Name      string    ` + "`" + `json:"name,omitempty"` + "`" + `
+			Friend    *User     ` + "`" + `json:"friend,omitempty"` + "`" + `
+			CreatedAt time.Time ` + "`" + `json:"created_at"` + "`" + `
+			password  string
+		}
+		type Input struct {
+			Name string ` + "`" + `json:"name"` + "`" + `
+		}
+		func (c *Controller) Index(ctx context.Context, page int) ([]*User, error) {
+			return nil, nil
+		}
+		func (c *Controller) Create(in *Input) (*User, error) {
+			return nil, nil
+		}
+		func (c *Controller) Delete(id int) error {
+			return nil
+		}
+		func (c *Controller) Export() io.Reader {
+			return nil
+		}
+	`
+	td.Files["controller/users/posts/controller.go"] = `
+		package posts
+		type Controller struct {}
+		func (c *Controller) Index() (count int, titles []string) {
+			return 0, nil
+		}
+	`
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	result, err := cli.Run(ctx, "build")
+	is.NoErr(err)
+	is.Equal(result.Stderr(), "")
+	is.NoErr(td.Exists("bud/client/client.go"))
+	data, err := os.ReadFile(td.Path("bud/client/client.go"))
+	is.NoErr(err)
+	code := string(data)
+	is.In(code, "func New(baseURL string, options ...clientrt.Option) (*Client, error) {")
+	is.In(code, "func (c *UsersClient) Index(ctx context.Context, in *UsersIndexInput) ([]*User, error) {")
+	is.In(code, "func (c *UsersClient) Create(ctx context.Context, in *Input) (*User, error) {")
+	is.In(code, "func (c *UsersClient) Delete(ctx context.Context, in *UsersDeleteInput) error {")
+	is.In(code, "func (c *UsersPostsClient) Index(ctx context.Context, in *UsersPostsIndexInput) (*UsersPostsIndexOutput, error) {")
+	// Streams aren't supported
+	is.NotIn(code, "Export")
+	// App types are copied into the client
+	is.In(code, "type User struct {\n\tID        int       `json:\"id\"`\n\tName      string    `json:\"name,omitempty\"`\n\tFriend    *User     `json:\"friend,omitempty\"`\n\tCreatedAt time.Time `json:\"created_at\"`\n}")
+	is.In(code, "type UsersPostsIndexInput struct {\n\tUserID string `json:\"user_id\"`\n}")
+}
+
+func TestSizedSlot(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/users/controller.go"] = `
+		package users
+		type Controller struct {}
+		type User struct {
+			ID int8 ` + "`" + `json:"id"` + "`" + `
+		}
+		func (c *Controller) Load(userID int8) *User {
+			return &User{ID: userID}
+		}
+	`
+	td.Files["controller/users/posts/controller.go"] = `
+		package posts
+		import "app.com/controller/users"
+		type Controller struct {}
+		func (c *Controller) Index(user *users.User) []string {
+			return nil
+		}
+	`
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	result, err := cli.Run(ctx, "build")
+	is.NoErr(err)
+	is.Equal(result.Stderr(), "")
+	data, err := os.ReadFile(td.Path("bud/client/client.go"))
+	is.NoErr(err)
+	// Parent slots are typed by the constraint of the route
+	is.In(string(data), "type UsersPostsIndexInput struct {\n\tUserID int8 `json:\"user_id\"`\n}")
+}
@@ -0,0 +1,220 @@
+package clientrt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ajg/form"
+)
+
+// New client that sends requests to the app at baseURL
+// (e.g. http://localhost:3000)
+func New(baseURL string, options ...Option) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("clientrt: invalid base url %q. %w", baseURL, err)
+	}
+	client := &Client{
+		baseURL:    u,
+		httpClient: http.DefaultClient,
+		header:     http.Header{},
+	}
+	for _, option := range options {
+		option(client)
+	}
+	return client, nil
+}
+
+// Option configures the client
+type Option func(c *Client)
+
+// WithHTTPClient sends requests with a custom HTTP client
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithHeader adds a header to every request (e.g. Authorization)
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.header.Add(key, value)
+	}
+}
+
+// Client for an app's JSON API
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	header     http.Header
+}
+
+// Do sends a request to the route and decodes the JSON response into out.
+// Slots in the route (e.g. /users/:id) are filled in by the matching fields
+// of in. The remaining fields are sent in the query string of GET and DELETE
+// requests and in the JSON body of other requests.
+func (c *Client) Do(ctx context.Context, method, route string, in, out interface{}) error {
+	req, err := c.request(ctx, method, route, in)
+	if err != nil {
+		return err
+	}
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode >= 400 {
+		return decodeError(res)
+	}
+	if out == nil || res.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(res.Body).Decode(out); err != nil && err != io.EOF {
+		return fmt.Errorf("clientrt: unable to decode the response from %s %s. %w", method, req.URL.Path, err)
+	}
+	return nil
+}
+
+func (c *Client) request(ctx context.Context, method, route string, in interface{}) (*http.Request, error) {
+	path, query, err := interpolate(route, in)
+	if err != nil {
+		return nil, err
+	}
+	u := c.baseURL.ResolveReference(&url.URL{Path: strings.TrimSuffix(c.baseURL.Path, "/") + path})
+	var body io.Reader
+	switch method {
+	case http.MethodGet, http.MethodDelete:
+		u.RawQuery = query.Encode()
+	default:
+		if !isNil(in) {
+			data, err := json.Marshal(in)
+			if err != nil {
+				return nil, err
+			}
+			body = bytes.NewReader(data)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range c.header {
+		req.Header[key] = append([]string(nil), values...)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// interpolate the slots of the route with the input's values, returning the
+// path and the remaining values
+func interpolate(route string, in interface{}) (string, url.Values, error) {
+	query := url.Values{}
+	slots := url.Values{}
+	if !isNil(in) {
+		values, err := form.EncodeToValues(in)
+		if err != nil {
+			return "", nil, err
+		}
+		// Zero values are encoded as empty strings, so leave them out
+		for key, list := range values {
+			for _, value := range list {
+				if value != "" {
+					query.Add(key, value)
+				}
+			}
+		}
+		// Slots keep their zero values (e.g. /users/0)
+		if slots, err = form.EncodeToValues(in, true); err != nil {
+			return "", nil, err
+		}
+	}
+	segments := strings.Split(route, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		// Trim the slot's constraint (e.g. :id|int)
+		key := strings.SplitN(segment[1:], "|", 2)[0]
+		if !slots.Has(key) {
+			return "", nil, fmt.Errorf("clientrt: missing %q for route %q", key, route)
+		}
+		segments[i] = url.PathEscape(slots.Get(key))
+		query.Del(key)
+	}
+	return strings.Join(segments, "/"), query, nil
+}
+
+func isNil(in interface{}) bool {
+	if in == nil {
+		return true
+	}
+	rv := reflect.ValueOf(in)
+	return rv.Kind() == reflect.Ptr && rv.IsNil()
+}
+
+// Error is returned when the app responds with a 4xx or 5xx status
+type Error struct {
+	Status  int
+	Message string
+	Fields  map[string]string // Invalid fields, if any
+}
+
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	if len(e.Fields) > 0 {
+		return "invalid " + strings.Join(sortedKeys(e.Fields), ", ")
+	}
+	return strings.ToLower(http.StatusText(e.Status))
+}
+
+// StatusCode of the response
+func (e *Error) StatusCode() int {
+	return e.Status
+}
+
+// FieldErrors maps invalid fields to their messages
+func (e *Error) FieldErrors() map[string]string {
+	return e.Fields
+}
+
+func decodeError(res *http.Response) error {
+	var body struct {
+		Error  string            `json:"error"`
+		Errors map[string]string `json:"errors"`
+	}
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &body); err != nil {
+		// Not JSON (e.g. from a proxy)
+		body.Error = strings.TrimSpace(string(data))
+	}
+	return &Error{
+		Status:  res.StatusCode,
+		Message: body.Error,
+		Fields:  body.Errors,
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
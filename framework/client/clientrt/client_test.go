@@ -0,0 +1,146 @@
+package clientrt_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/livebud/bud/framework/client/clientrt"
+	"github.com/livebud/bud/internal/is"
+)
+
+type request struct {
+	Method string
+	Path   string
+	Query  string
+	Accept string
+	Header string
+	Body   string
+}
+
+// echo responds with the request
+func echo(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	json.NewEncoder(w).Encode(&request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.RawQuery,
+		Accept: r.Header.Get("Accept"),
+		Header: r.Header.Get("Authorization"),
+		Body:   string(body),
+	})
+}
+
+type input struct {
+	PostID int    `json:"post_id"`
+	ID     int    `json:"id"`
+	Title  string `json:"title,omitempty"`
+	Page   int    `json:"page,omitempty"`
+}
+
+func TestGet(t *testing.T) {
+	is := is.New(t)
+	server := httptest.NewServer(http.HandlerFunc(echo))
+	defer server.Close()
+	client, err := clientrt.New(server.URL, clientrt.WithHeader("Authorization", "Bearer abc"))
+	is.NoErr(err)
+	var out request
+	err = client.Do(context.Background(), "GET", "/posts/:post_id/comments/:id|int", &input{PostID: 1, ID: 2, Page: 3}, &out)
+	is.NoErr(err)
+	is.Equal(out.Method, "GET")
+	is.Equal(out.Path, "/posts/1/comments/2")
+	is.Equal(out.Query, "page=3")
+	is.Equal(out.Accept, "application/json")
+	is.Equal(out.Header, "Bearer abc")
+	is.Equal(out.Body, "")
+}
+
+func TestZeroSlot(t *testing.T) {
+	is := is.New(t)
+	server := httptest.NewServer(http.HandlerFunc(echo))
+	defer server.Close()
+	client, err := clientrt.New(server.URL + "/api/")
+	is.NoErr(err)
+	var out request
+	err = client.Do(context.Background(), "DELETE", "/posts/:post_id/comments/:id", &input{}, &out)
+	is.NoErr(err)
+	is.Equal(out.Path, "/api/posts/0/comments/0")
+	is.Equal(out.Query, "")
+}
+
+func TestMissingSlot(t *testing.T) {
+	is := is.New(t)
+	client, err := clientrt.New("http://localhost")
+	is.NoErr(err)
+	err = client.Do(context.Background(), "GET", "/users/:user_id", nil, nil)
+	is.True(err != nil)
+	is.Equal(err.Error(), `clientrt: missing "user_id" for route "/users/:user_id"`)
+}
+
+func TestPost(t *testing.T) {
+	is := is.New(t)
+	server := httptest.NewServer(http.HandlerFunc(echo))
+	defer server.Close()
+	client, err := clientrt.New(server.URL)
+	is.NoErr(err)
+	var out request
+	err = client.Do(context.Background(), "POST", "/posts/:post_id/comments", &input{PostID: 1, Title: "hi"}, &out)
+	is.NoErr(err)
+	is.Equal(out.Method, "POST")
+	is.Equal(out.Path, "/posts/1/comments")
+	is.Equal(out.Query, "")
+	is.Equal(out.Body, `{"post_id":1,"id":0,"title":"hi"}`)
+}
+
+func TestNoContent(t *testing.T) {
+	is := is.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(204)
+	}))
+	defer server.Close()
+	client, err := clientrt.New(server.URL)
+	is.NoErr(err)
+	var out *request
+	err = client.Do(context.Background(), "PATCH", "/", nil, &out)
+	is.NoErr(err)
+	is.Equal(out, nil)
+}
+
+func TestError(t *testing.T) {
+	is := is.New(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(404)
+			w.Write([]byte(`{"error":"user not found"}`))
+		case "/invalid":
+			w.WriteHeader(422)
+			w.Write([]byte(`{"errors":{"name":"is required","email":"must be a valid email address"}}`))
+		default:
+			http.Error(w, "bad gateway", 502)
+		}
+	}))
+	defer server.Close()
+	client, err := clientrt.New(server.URL)
+	is.NoErr(err)
+	ctx := context.Background()
+	err = client.Do(ctx, "GET", "/missing", nil, nil)
+	var e *clientrt.Error
+	is.True(errors.As(err, &e))
+	is.Equal(e.StatusCode(), 404)
+	is.Equal(e.Error(), "user not found")
+	err = client.Do(ctx, "POST", "/invalid", nil, nil)
+	is.True(errors.As(err, &e))
+	is.Equal(e.StatusCode(), 422)
+	is.Equal(e.FieldErrors()["name"], "is required")
+	is.Equal(e.Error(), "invalid email, name")
+	err = client.Do(ctx, "GET", "/proxy", nil, nil)
+	is.True(errors.As(err, &e))
+	is.Equal(e.StatusCode(), 502)
+	is.Equal(e.Error(), "bad gateway")
+}
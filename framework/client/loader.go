@@ -0,0 +1,409 @@
+package client
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/livebud/bud/framework/controller"
+	"github.com/livebud/bud/internal/bail"
+	"github.com/livebud/bud/internal/imports"
+	"github.com/livebud/bud/package/di"
+	"github.com/livebud/bud/package/gomod"
+	"github.com/livebud/bud/package/parser"
+	"github.com/matthewmueller/gotext"
+)
+
+func Load(fsys fs.FS, injector *di.Injector, module *gomod.Module, parser *parser.Parser) (*State, error) {
+	// The client calls the controllers
+	controllerState, err := controller.Load(fsys, injector, module, parser)
+	if err != nil {
+		return nil, err
+	}
+	loader := &loader{
+		imports: imports.New(),
+		module:  module,
+		names:   map[string]bool{"New": true, "Option": true, "Error": true},
+		refs:    map[string]string{},
+	}
+	return loader.Load(controllerState.Controller)
+}
+
+type loader struct {
+	bail.Struct
+	imports *imports.Set
+	module  *gomod.Module
+	names   map[string]bool   // Names declared in the client package
+	refs    map[string]string // Maps declarations to their names in the client
+	types   []*Type
+}
+
+// Load the client state
+func (l *loader) Load(controller *controller.Controller) (state *State, err error) {
+	defer l.Recover2(&err, "client: unable to load")
+	state = new(State)
+	l.imports.AddStd("context")
+	l.imports.AddNamed("clientrt", "github.com/livebud/bud/framework/client/clientrt")
+	// Reserve the client names before declaring types
+	l.reserveClients(controller)
+	state.Clients = l.loadClients(controller)
+	state.Types = l.types
+	state.Imports = l.imports.List()
+	return state, nil
+}
+
+func clientName(controller *controller.Controller) string {
+	return controller.Pascal + "Client"
+}
+
+func (l *loader) reserveClients(controller *controller.Controller) {
+	l.names[clientName(controller)] = true
+	for _, child := range controller.Controllers {
+		l.reserveClients(child)
+	}
+}
+
+func (l *loader) loadClients(controller *controller.Controller) (clients []*Client) {
+	client := new(Client)
+	client.Name = clientName(controller)
+	for _, action := range controller.Actions {
+		if !l.isSupported(action) {
+			continue
+		}
+		client.Actions = append(client.Actions, l.loadAction(controller, action))
+	}
+	clients = append(clients, client)
+	for _, child := range controller.Controllers {
+		client.Children = append(client.Children, &Child{
+			Field: child.Last().Pascal(),
+			Type:  clientName(child),
+		})
+		clients = append(clients, l.loadClients(child)...)
+	}
+	return clients
+}
+
+// isSupported returns true for actions that respond with JSON. WebSockets,
+// streams, file uploads and http.Handler actions aren't supported.
+func (l *loader) isSupported(action *controller.Action) bool {
+	if action.Socket || action.HandlerFunc || action.Results.Stream() != "" {
+		return false
+	}
+	for _, param := range action.Function.Params() {
+		isFile, err := parser.IsImportType(param.Type(), "github.com/livebud/bud/framework/controller/controllerrt/request", "File")
+		if err != nil {
+			l.Bail(err)
+		}
+		if isFile {
+			return false
+		}
+	}
+	return true
+}
+
+func (l *loader) loadAction(controller *controller.Controller, action *controller.Action) *Action {
+	out := new(Action)
+	out.Name = action.Pascal
+	out.Method = action.Method
+	out.Route = action.Route
+	out.Input = l.loadInput(controller.Pascal+action.Pascal, action)
+	out.Result = l.loadResult(controller.Pascal+action.Pascal, action)
+	return out
+}
+
+// field of a generated struct
+type field struct {
+	Name string
+	Type string
+	Key  string // JSON key
+}
+
+// loadInput returns the type of the action's input. Actions that take a
+// single struct take that struct, otherwise the params are wrapped in a
+// struct. Slots in the route that aren't covered by the params (e.g.
+// :post_id) are added as fields.
+func (l *loader) loadInput(prefix string, action *controller.Action) string {
+	params := action.Function.Params()
+	var fields []*field
+	var single parser.Type
+	keys := map[string]bool{}
+	for i, param := range action.Params {
+		if param.IsContext() || param.IsSocket || param.Loader != nil {
+			continue
+		}
+		dt := params[i].Type()
+		if param.Variable == "in" {
+			single = dt
+			l.loadKeys(keys, dt)
+			continue
+		}
+		fields = append(fields, &field{param.Pascal, l.goType(dt), param.Snake})
+		keys[param.Snake] = true
+	}
+	var slots []*field
+	for _, slot := range routeSlots(action.Route) {
+		if keys[slot.Name] {
+			continue
+		}
+		slots = append(slots, &field{gotext.Pascal(slot.Name), slot.goType(), slot.Name})
+	}
+	if single != nil {
+		inputType := l.goType(single)
+		if len(slots) == 0 {
+			return inputType
+		}
+		// Embed the input to add the missing slots
+		fields = append(slots, &field{Type: inputType})
+	} else {
+		fields = append(fields, slots...)
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+	name := l.declare(prefix+"Input", structCode(fields))
+	return "*" + name
+}
+
+// loadKeys loads the JSON keys of a struct
+func (l *loader) loadKeys(keys map[string]bool, dt parser.Type) {
+	decl, err := parser.Definition(dt)
+	if err != nil {
+		l.Bail(err)
+	}
+	stct, ok := decl.(*parser.Struct)
+	if !ok {
+		return
+	}
+	for _, field := range stct.Fields() {
+		tags, err := field.Tags()
+		if err != nil {
+			l.Bail(err)
+		}
+		key := tags.Get("json")
+		if field.Embedded() && key == "" {
+			l.loadKeys(keys, field.Type())
+			continue
+		}
+		if key == "" {
+			key = field.Name()
+		}
+		keys[key] = true
+	}
+}
+
+// slot in a route with its constraint, if any (e.g. :id|int)
+type slot struct {
+	Name       string
+	Constraint string
+}
+
+// goType returns the type of the slot's value. The numeric constraints match
+// the values of the Go type with the same name.
+func (s *slot) goType() string {
+	switch s.Constraint {
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64":
+		return s.Constraint
+	default:
+		return "string"
+	}
+}
+
+// routeSlots returns the slots in a route
+func routeSlots(route string) (slots []*slot) {
+	for _, segment := range strings.Split(route, "/") {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimRight(segment[1:], "?*"), "|", 2)
+		slot := &slot{Name: parts[0]}
+		if len(parts) == 2 {
+			slot.Constraint = parts[1]
+		}
+		slots = append(slots, slot)
+	}
+	return slots
+}
+
+// loadResult returns the type of the action's result. Named results are
+// returned as a struct.
+func (l *loader) loadResult(prefix string, action *controller.Action) string {
+	results := action.Function.Results()
+	var fields []*field
+	named := true
+	for i, result := range action.Results {
+		if result.IsError {
+			continue
+		}
+		fields = append(fields, &field{result.Pascal, l.goType(results[i].Type()), result.Snake})
+		named = named && result.Named
+	}
+	switch {
+	case len(fields) == 0:
+		return ""
+	case len(fields) == 1:
+		return fields[0].Type
+	case named:
+		name := l.declare(prefix+"Output", structCode(fields))
+		return "*" + name
+	default:
+		// Unnamed results are returned as an array
+		name := l.imports.Add("encoding/json")
+		return "[]" + name + ".RawMessage"
+	}
+}
+
+func structCode(fields []*field) string {
+	b := new(strings.Builder)
+	b.WriteString("struct {\n")
+	for _, field := range fields {
+		b.WriteString("\t")
+		if field.Name != "" {
+			b.WriteString(field.Name + " ")
+		}
+		b.WriteString(field.Type)
+		if field.Key != "" {
+			b.WriteString(" `json:" + strconv.Quote(field.Key) + "`")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// declare a type in the client package, returning its name
+func (l *loader) declare(name, code string) string {
+	name = l.reserve(name, "")
+	l.types = append(l.types, &Type{name, code})
+	return name
+}
+
+// reserve a unique name for a type, qualifying it with the package name if
+// it's already taken
+func (l *loader) reserve(name, importPath string) string {
+	if !l.names[name] {
+		l.names[name] = true
+		return name
+	}
+	if importPath != "" {
+		qualified := gotext.Pascal(path.Base(importPath)) + name
+		if !l.names[qualified] {
+			l.names[qualified] = true
+			return qualified
+		}
+	}
+	for i := 2; ; i++ {
+		numbered := name + strconv.Itoa(i)
+		if !l.names[numbered] {
+			l.names[numbered] = true
+			return numbered
+		}
+	}
+}
+
+// goType returns the type as it's written in the client package. Types
+// declared in the app are copied into the client, so services can import the
+// client without the app's dependencies.
+func (l *loader) goType(t parser.Type) string {
+	switch t := t.(type) {
+	case *parser.StarType:
+		return "*" + l.goType(t.Inner())
+	case *parser.ArrayType:
+		// Keep the length of arrays (e.g. [3]int)
+		s := t.String()
+		return s[:strings.Index(s, "]")+1] + l.goType(t.Inner())
+	case *parser.MapType:
+		return "map[" + l.goType(t.Key()) + "]" + l.goType(t.Value())
+	case *parser.IdentType:
+		if parser.IsBuiltin(t) {
+			return t.Name()
+		}
+		return l.goTypeOf(t)
+	case *parser.SelectorType:
+		importPath, err := t.ImportPath()
+		if err != nil {
+			l.Bail(err)
+		}
+		if l.inModule(importPath) {
+			return l.goTypeOf(t)
+		}
+		// Import types from the standard library and other modules
+		return l.imports.Add(importPath) + "." + t.Name()
+	default:
+		// Interfaces, anonymous structs, functions and channels
+		return "interface{}"
+	}
+}
+
+func (l *loader) inModule(importPath string) bool {
+	modulePath := l.module.Import()
+	return importPath == modulePath || strings.HasPrefix(importPath, modulePath+"/")
+}
+
+// goTypeOf declares the type's definition in the client
+func (l *loader) goTypeOf(t parser.Type) string {
+	decl, err := parser.Definition(t)
+	if err != nil {
+		l.Bail(err)
+	}
+	if decl.Kind() == parser.KindInterface {
+		return "interface{}"
+	}
+	importPath, err := decl.Package().Import()
+	if err != nil {
+		l.Bail(err)
+	}
+	key := importPath + "." + decl.Name()
+	if name, ok := l.refs[key]; ok {
+		return name
+	}
+	// Reserve the name before loading the definition to support recursive types
+	name := l.reserve(decl.Name(), importPath)
+	l.refs[key] = name
+	typ := &Type{Name: name}
+	switch decl := decl.(type) {
+	case *parser.Struct:
+		typ.Code = l.structCodeOf(decl)
+	case *parser.Alias:
+		typ.Code = l.goType(decl.Type())
+	case *parser.TypeDef:
+		typ.Code = l.goType(decl.Type())
+	default:
+		l.Bail(fmt.Errorf("client: unable to declare %s", key))
+	}
+	l.types = append(l.types, typ)
+	return name
+}
+
+// structCodeOf copies the struct's JSON fields
+func (l *loader) structCodeOf(stct *parser.Struct) string {
+	var fields []*field
+	for _, f := range stct.Fields() {
+		tags, err := f.Tags()
+		if err != nil {
+			l.Bail(err)
+		}
+		key := jsonTag(tags)
+		if key == "-" || (f.Private() && !f.Embedded()) {
+			continue
+		}
+		if f.Embedded() {
+			fields = append(fields, &field{Type: l.goType(f.Type()), Key: key})
+			continue
+		}
+		fields = append(fields, &field{f.Name(), l.goType(f.Type()), key})
+	}
+	return structCode(fields)
+}
+
+// jsonTag returns the json tag with its options (e.g. name,omitempty)
+func jsonTag(tags parser.Tags) string {
+	for _, tag := range tags {
+		if tag.Key == "json" {
+			return strings.Join(append([]string{tag.Value}, tag.Options...), ",")
+		}
+	}
+	return ""
+}
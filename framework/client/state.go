@@ -0,0 +1,39 @@
+package client
+
+import (
+	"github.com/livebud/bud/internal/imports"
+)
+
+type State struct {
+	Imports []*imports.Import
+	Clients []*Client // Clients for each controller, starting with the root
+	Types   []*Type   // Types used by the actions
+}
+
+// Client for a controller
+type Client struct {
+	Name     string
+	Children []*Child
+	Actions  []*Action
+}
+
+// Child is the client for a nested controller
+type Child struct {
+	Field string
+	Type  string
+}
+
+// Action calls a controller action
+type Action struct {
+	Name   string
+	Method string
+	Route  string
+	Input  string // Input type, if any
+	Result string // Result type, if any
+}
+
+// Type is declared in the client package
+type Type struct {
+	Name string
+	Code string
+}
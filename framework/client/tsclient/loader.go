@@ -0,0 +1,477 @@
+package tsclient
+
+import (
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/livebud/bud/framework/controller"
+	"github.com/livebud/bud/internal/bail"
+	"github.com/livebud/bud/package/di"
+	"github.com/livebud/bud/package/gomod"
+	"github.com/livebud/bud/package/parser"
+	"github.com/matthewmueller/gotext"
+	"github.com/matthewmueller/text"
+)
+
+func Load(fsys fs.FS, injector *di.Injector, module *gomod.Module, parser *parser.Parser) (*State, error) {
+	// The client calls the controllers
+	controllerState, err := controller.Load(fsys, injector, module, parser)
+	if err != nil {
+		return nil, err
+	}
+	loader := &loader{
+		names: map[string]bool{
+			"Options":     true,
+			"ClientError": true,
+			"PageProps":   true,
+			"Client":      true,
+		},
+		refs: map[string]string{},
+	}
+	return loader.Load(controllerState.Controller)
+}
+
+type loader struct {
+	bail.Struct
+	names map[string]bool   // Names declared in the module
+	refs  map[string]string // Maps declarations to their names in the module
+	types []*Type
+}
+
+// Load the TypeScript client state
+func (l *loader) Load(controller *controller.Controller) (state *State, err error) {
+	defer l.Recover2(&err, "tsclient: unable to load")
+	state = new(State)
+	state.Clients = l.loadClients(controller)
+	state.Types = l.types
+	return state, nil
+}
+
+func clientName(controller *controller.Controller) string {
+	return controller.Pascal + "Client"
+}
+
+func (l *loader) loadClients(controller *controller.Controller) (clients []*Client) {
+	client := new(Client)
+	client.Name = clientName(controller)
+	for _, action := range controller.Actions {
+		if action.HandlerFunc || action.Socket || action.Results.Stream() != "" {
+			continue
+		}
+		prefix := controller.Pascal + action.Pascal
+		output := l.loadOutput(prefix, action)
+		if action.View != nil {
+			l.loadProps(prefix, action, output)
+		}
+		if !l.isSupported(action) {
+			continue
+		}
+		client.Actions = append(client.Actions, &Action{
+			Key:    text.Camel(action.Name),
+			Method: action.Method,
+			Route:  action.Route,
+			Input:  l.loadInput(prefix, action),
+			Output: output,
+		})
+	}
+	clients = append(clients, client)
+	for _, child := range controller.Controllers {
+		client.Children = append(client.Children, &Child{
+			Key:  text.Camel(string(child.Last())),
+			Name: clientName(child),
+		})
+		clients = append(clients, l.loadClients(child)...)
+	}
+	return clients
+}
+
+// isSupported returns false for file uploads, which aren't sent as JSON
+func (l *loader) isSupported(action *controller.Action) bool {
+	for _, param := range action.Function.Params() {
+		isFile, err := parser.IsImportType(param.Type(), "github.com/livebud/bud/framework/controller/controllerrt/request", "File")
+		if err != nil {
+			l.Bail(err)
+		}
+		if isFile {
+			return false
+		}
+	}
+	return true
+}
+
+// field of a generated object type
+type field struct {
+	Key      string
+	Type     string
+	Optional bool
+}
+
+// loadInput declares the type of the action's input. Actions that take a
+// single struct take that struct, otherwise the params are wrapped in an
+// object. Slots in the route that aren't covered by the params (e.g.
+// :post_id) are added as fields.
+func (l *loader) loadInput(prefix string, action *controller.Action) string {
+	params := action.Function.Params()
+	var fields []*field
+	var single string
+	keys := map[string]bool{}
+	for i, param := range action.Params {
+		if param.IsContext() || param.IsSocket || param.Loader != nil {
+			continue
+		}
+		dt := params[i].Type()
+		if param.Variable == "in" {
+			single = l.tsType(dt)
+			l.loadKeys(keys, dt)
+			continue
+		}
+		fields = append(fields, &field{param.Snake, l.tsType(dt), true})
+		keys[param.Snake] = true
+	}
+	slots := map[string]bool{}
+	for _, slot := range routeSlots(action.Route) {
+		slots[slot.Name] = true
+		if keys[slot.Name] {
+			continue
+		}
+		fields = append(fields, &field{slot.Name, slot.tsType(), false})
+	}
+	// Slots are required
+	for _, field := range fields {
+		if slots[field.Key] {
+			field.Optional = false
+		}
+	}
+	var code string
+	switch {
+	case single != "" && len(fields) > 0:
+		code = single + " & " + objectCode(fields)
+	case single != "":
+		code = single
+	case len(fields) > 0:
+		code = objectCode(fields)
+	default:
+		return ""
+	}
+	return l.declare("", prefix+"Input", code)
+}
+
+// loadKeys loads the JSON keys of a struct
+func (l *loader) loadKeys(keys map[string]bool, dt parser.Type) {
+	decl, err := parser.Definition(dt)
+	if err != nil {
+		l.Bail(err)
+	}
+	stct, ok := decl.(*parser.Struct)
+	if !ok {
+		return
+	}
+	for _, field := range stct.Fields() {
+		tags, err := field.Tags()
+		if err != nil {
+			l.Bail(err)
+		}
+		key := tags.Get("json")
+		if field.Embedded() && key == "" {
+			l.loadKeys(keys, field.Type())
+			continue
+		}
+		if key == "" {
+			key = field.Name()
+		}
+		keys[key] = true
+	}
+}
+
+// slot in a route with its constraint, if any (e.g. :id|int)
+type slot struct {
+	Name       string
+	Constraint string
+}
+
+// tsType returns the type of the slot's value
+func (s *slot) tsType() string {
+	switch s.Constraint {
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64":
+		return "number"
+	case "uuid":
+		return "string"
+	default:
+		return "string | number"
+	}
+}
+
+// routeSlots returns the slots in a route
+func routeSlots(route string) (slots []*slot) {
+	for _, segment := range strings.Split(route, "/") {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimRight(segment[1:], "?*"), "|", 2)
+		slot := &slot{Name: parts[0]}
+		if len(parts) == 2 {
+			slot.Constraint = parts[1]
+		}
+		slots = append(slots, slot)
+	}
+	return slots
+}
+
+// loadOutput declares the type of the action's output. Named results are
+// returned as an object and unnamed results as an array.
+func (l *loader) loadOutput(prefix string, action *controller.Action) string {
+	results := action.Function.Results()
+	var fields []*field
+	named := true
+	for i, result := range action.Results {
+		if result.IsError {
+			continue
+		}
+		fields = append(fields, &field{result.Snake, l.tsType(results[i].Type()), false})
+		named = named && result.Named
+	}
+	var code string
+	switch {
+	case len(fields) == 0:
+		return ""
+	case len(fields) == 1:
+		code = fields[0].Type
+	case named:
+		code = objectCode(fields)
+	default:
+		types := make([]string, len(fields))
+		for i, field := range fields {
+			types[i] = field.Type
+		}
+		code = "[" + strings.Join(types, ", ") + "]"
+	}
+	return l.declare("", prefix+"Output", code)
+}
+
+// loadProps declares the props of the action's view. The results are passed
+// to the view under a single key (e.g. users).
+func (l *loader) loadProps(prefix string, action *controller.Action, output string) {
+	code := "PageProps"
+	if key := action.Results.PropsKey(); key != "" && output != "" {
+		code += " & " + objectCode([]*field{{key, output, false}})
+	}
+	comment := "Props of view" + action.Key + ".svelte"
+	l.declare(comment, prefix+"Props", code)
+}
+
+func objectCode(fields []*field) string {
+	b := new(strings.Builder)
+	b.WriteString("{\n")
+	for _, field := range fields {
+		b.WriteString("  " + propertyKey(field.Key))
+		if field.Optional {
+			b.WriteString("?")
+		}
+		b.WriteString(": " + field.Type + "\n")
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// propertyKey quotes keys that aren't valid identifiers
+func propertyKey(key string) string {
+	for i, r := range key {
+		if r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9') {
+			continue
+		}
+		return strconv.Quote(key)
+	}
+	if key == "" {
+		return `""`
+	}
+	return key
+}
+
+// declare a type in the module, returning its name
+func (l *loader) declare(comment, name, code string) string {
+	name = l.reserve(name, "")
+	l.types = append(l.types, &Type{comment, name, code})
+	return name
+}
+
+// reserve a unique name for a type, qualifying it with the package name if
+// it's already taken
+func (l *loader) reserve(name, importPath string) string {
+	if !l.names[name] {
+		l.names[name] = true
+		return name
+	}
+	if importPath != "" {
+		qualified := gotext.Pascal(path.Base(importPath)) + name
+		if !l.names[qualified] {
+			l.names[qualified] = true
+			return qualified
+		}
+	}
+	for i := 2; ; i++ {
+		numbered := name + strconv.Itoa(i)
+		if !l.names[numbered] {
+			l.names[numbered] = true
+			return numbered
+		}
+	}
+}
+
+// tsType maps a Go type to the TypeScript type of its JSON
+func (l *loader) tsType(t parser.Type) string {
+	switch t := t.(type) {
+	case *parser.StarType:
+		return l.tsType(t.Inner())
+	case *parser.ArrayType:
+		// Byte slices are encoded as base64 strings by encoding/json
+		if t.Inner().String() == "byte" {
+			return "string"
+		}
+		return l.tsType(t.Inner()) + "[]"
+	case *parser.MapType:
+		return "Record<string, " + l.tsType(t.Value()) + ">"
+	case *parser.IdentType:
+		if parser.IsBuiltin(t) {
+			return builtinType(t.Name())
+		}
+		return l.tsTypeOf(t)
+	case *parser.SelectorType:
+		importPath, err := t.ImportPath()
+		if err != nil {
+			l.Bail(err)
+		}
+		if known := knownType(importPath, t.Name()); known != "" {
+			return known
+		}
+		return l.tsTypeOf(t)
+	default:
+		// Interfaces, anonymous structs, functions and channels can hold anything
+		return "unknown"
+	}
+}
+
+func builtinType(name string) string {
+	switch name {
+	case "string", "error":
+		return "string"
+	case "bool":
+		return "boolean"
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64",
+		"byte", "rune", "uintptr", "float32", "float64":
+		return "number"
+	default:
+		return "unknown"
+	}
+}
+
+// knownType maps types that marshal differently than their definition
+func knownType(importPath, name string) string {
+	switch importPath + "." + name {
+	case "time.Time", "net/url.URL":
+		return "string"
+	case "time.Duration":
+		return "number"
+	case "encoding/json.RawMessage":
+		return "unknown"
+	default:
+		return ""
+	}
+}
+
+// tsTypeOf declares the type's definition in the module
+func (l *loader) tsTypeOf(t parser.Type) string {
+	decl, err := parser.Definition(t)
+	if err != nil {
+		l.Bail(err)
+	}
+	importPath, err := decl.Package().Import()
+	if err != nil {
+		l.Bail(err)
+	}
+	key := importPath + "." + decl.Name()
+	if name, ok := l.refs[key]; ok {
+		return name
+	}
+	var code string
+	switch decl := decl.(type) {
+	case *parser.Struct:
+		// Reserve the name before loading the fields to support recursive types
+		name := l.reserve(decl.Name(), importPath)
+		l.refs[key] = name
+		typ := &Type{Name: name}
+		l.types = append(l.types, typ)
+		typ.Code = l.structCode(decl)
+		return name
+	case *parser.Alias:
+		code = l.tsType(decl.Type())
+	case *parser.TypeDef:
+		code = l.tsType(decl.Type())
+	default:
+		return "unknown"
+	}
+	name := l.reserve(decl.Name(), importPath)
+	l.refs[key] = name
+	l.types = append(l.types, &Type{Name: name, Code: code})
+	return name
+}
+
+// structCode maps the struct's fields, following the rules of encoding/json.
+// Untagged embedded structs are intersected with the struct.
+func (l *loader) structCode(stct *parser.Struct) string {
+	var embeds []string
+	var fields []*field
+	for _, f := range stct.Fields() {
+		tags, err := f.Tags()
+		if err != nil {
+			l.Bail(err)
+		}
+		key, options := jsonTag(tags)
+		if key == "-" && len(options) == 0 {
+			continue
+		}
+		if f.Embedded() && key == "" {
+			decl, err := parser.Definition(f.Type())
+			if err != nil {
+				l.Bail(err)
+			}
+			if _, ok := decl.(*parser.Struct); ok {
+				embeds = append(embeds, l.tsType(f.Type()))
+				continue
+			}
+		}
+		if f.Private() {
+			continue
+		}
+		if key == "" {
+			key = f.Name()
+		}
+		field := &field{Key: key, Type: l.tsType(f.Type())}
+		for _, option := range options {
+			switch option {
+			case "omitempty":
+				field.Optional = true
+			case "string":
+				field.Type = "string"
+			}
+		}
+		fields = append(fields, field)
+	}
+	if len(fields) > 0 || len(embeds) == 0 {
+		embeds = append(embeds, objectCode(fields))
+	}
+	return strings.Join(embeds, " & ")
+}
+
+// jsonTag returns the field's name in JSON and the tag's options
+func jsonTag(tags parser.Tags) (string, []string) {
+	for _, tag := range tags {
+		if tag.Key == "json" {
+			return tag.Value, tag.Options
+		}
+	}
+	return "", nil
+}
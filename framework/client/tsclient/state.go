@@ -0,0 +1,35 @@
+package tsclient
+
+type State struct {
+	Clients []*Client // Clients for each controller, starting with the root
+	Types   []*Type   // Types of the actions and the props of the views
+}
+
+// Client for a controller
+type Client struct {
+	Name     string
+	Children []*Child
+	Actions  []*Action
+}
+
+// Child is the client for a nested controller
+type Child struct {
+	Key  string
+	Name string
+}
+
+// Action calls a controller action
+type Action struct {
+	Key    string
+	Method string
+	Route  string
+	Input  string // Input type, if any
+	Output string // Output type, if any
+}
+
+// Type is declared in the TypeScript module
+type Type struct {
+	Comment string
+	Name    string
+	Code    string
+}
@@ -0,0 +1,92 @@
+package tsclient
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	esbuild "github.com/evanw/esbuild/pkg/api"
+	"github.com/livebud/bud/internal/gotemplate"
+	"github.com/livebud/bud/package/di"
+	"github.com/livebud/bud/package/gomod"
+	"github.com/livebud/bud/package/overlay"
+	"github.com/livebud/bud/package/parser"
+)
+
+//go:embed tsclient.gotext
+var template string
+
+var generator = gotemplate.MustParse("framework/client/tsclient/tsclient.gotext", template)
+
+// Generate the TypeScript client from state
+func Generate(state *State) ([]byte, error) {
+	return generator.Generate(state)
+}
+
+// New TypeScript client generator
+func New(injector *di.Injector, module *gomod.Module, parser *parser.Parser) *Generator {
+	return &Generator{injector, module, parser}
+}
+
+// Generator for a typed TypeScript client of the app's JSON API, along with
+// the props of each view
+type Generator struct {
+	injector *di.Injector
+	module   *gomod.Module
+	parser   *parser.Parser
+}
+
+func (g *Generator) GenerateFile(ctx context.Context, fsys overlay.F, file *overlay.File) error {
+	state, err := Load(fsys, g.injector, g.module, g.parser)
+	if err != nil {
+		return err
+	}
+	code, err := Generate(state)
+	if err != nil {
+		return err
+	}
+	file.Data = code
+	return nil
+}
+
+// Path to the generated TypeScript client
+const Path = "bud/client/client.ts"
+
+// Compile the generated TypeScript client into JavaScript for the browser
+func Compile(fsys fs.FS) ([]byte, error) {
+	code, err := fs.ReadFile(fsys, Path)
+	if err != nil {
+		return nil, err
+	}
+	result := esbuild.Transform(string(code), esbuild.TransformOptions{
+		Loader:     esbuild.LoaderTS,
+		Format:     esbuild.FormatESModule,
+		Sourcefile: Path,
+	})
+	if len(result.Errors) > 0 {
+		msgs := esbuild.FormatMessages(result.Errors, esbuild.FormatMessagesOptions{
+			Kind:          esbuild.ErrorMessage,
+			TerminalWidth: 80,
+		})
+		return nil, fmt.Errorf("tsclient: unable to compile %s. %s", Path, strings.Join(msgs, "\n"))
+	}
+	return result.Code, nil
+}
+
+// NewCompiler serves the compiled client in development
+func NewCompiler() *Compiler {
+	return &Compiler{}
+}
+
+type Compiler struct{}
+
+func (c *Compiler) GenerateFile(ctx context.Context, fsys overlay.F, file *overlay.File) error {
+	code, err := Compile(fsys)
+	if err != nil {
+		return err
+	}
+	file.Data = code
+	return nil
+}
@@ -0,0 +1,132 @@
+package tsclient_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/livebud/bud/internal/cli/testcli"
+	"github.com/livebud/bud/internal/is"
+	"github.com/livebud/bud/internal/testdir"
+	"github.com/livebud/bud/internal/versions"
+)
+
+func TestNoControllers(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	result, err := cli.Run(ctx, "build")
+	is.NoErr(err)
+	is.Equal(result.Stderr(), "")
+	is.NoErr(td.NotExists("bud/client/client.ts"))
+}
+
+const controller = `
+	package users
+	import (
+		"context"
+		"io"
+		"time"
+	)
+	type Controller struct {}
+	type Timestamps struct {
+		CreatedAt time.Time ` + "`" + `json:"created_at"` + "`" + `
+	}
+	type User struct {
+		Timestamps
+		ID     int               ` + "`" + `json:"id"` + "`" + `
+		Name   string            ` + "`" + `json:"name,omitempty"` + "`" + `
+		Friend *User             ` + "`" + `json:"friend,omitempty"` + "`" + `
+		Tags   map[string]string ` + "`" + `json:"tags"` + "`" + `
+		secret string
+	}
+	type Input struct {
+		Name string ` + "`" + `json:"name"` + "`" + `
+	}
+	func (c *Controller) Index(ctx context.Context, page int) ([]*User, error) {
+		return nil, nil
+	}
+	func (c *Controller) Show(id int) (*User, error) {
+		return &User{ID: id}, nil
+	}
+	func (c *Controller) Create(in *Input) (*User, error) {
+		return &User{Name: in.Name}, nil
+	}
+	func (c *Controller) Delete(id int) error {
+		return nil
+	}
+	func (c *Controller) Export() io.Reader {
+		return nil
+	}
+`
+
+func TestTypes(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/users/controller.go"] = controller
+	td.Files["controller/users/posts/controller.go"] = `
+		package posts
+		type Controller struct {}
+		func (c *Controller) Index() (count int, titles []string) {
+			return 0, nil
+		}
+	`
+	td.Files["view/users/show.svelte"] = `<script>export let user = {}</script><h1>{user.name}</h1>`
+	td.NodeModules["svelte"] = versions.Svelte
+	td.NodeModules["livebud"] = "*"
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	result, err := cli.Run(ctx, "build")
+	is.NoErr(err)
+	is.Equal(result.Stderr(), "")
+	is.NoErr(td.Exists("bud/client/client.ts"))
+	data, err := os.ReadFile(td.Path("bud/client/client.ts"))
+	is.NoErr(err)
+	code := string(data)
+	is.In(code, "export function createClient(options: Options = {}) {")
+	is.In(code, "    index: (input: UsersIndexInput) =>\n      send<UsersIndexOutput>(\"GET\", \"/users\", input),")
+	is.In(code, "    delete: (input: UsersDeleteInput) =>\n      send<void>(\"DELETE\", \"/users/:id|int\", input),")
+	is.In(code, "    posts: newUsersPostsClient(send),")
+	// Streams aren't supported
+	is.NotIn(code, "export:")
+	// Types follow the JSON of the Go types
+	is.In(code, "export type User = Timestamps & {\n  id: number\n  name?: string\n  friend?: User\n  tags: Record<string, string>\n}")
+	is.In(code, "export type Timestamps = {\n  created_at: string\n}")
+	is.In(code, "export type UsersIndexInput = {\n  page?: number\n}")
+	is.In(code, "export type UsersIndexOutput = User[]")
+	is.In(code, "export type UsersCreateInput = Input")
+	is.In(code, "export type UsersPostsIndexInput = {\n  user_id: string | number\n}")
+	is.In(code, "export type UsersPostsIndexOutput = {\n  count: number\n  titles: string[]\n}")
+	// Views get typed props
+	is.In(code, "// Props of view/users/show.svelte\nexport type UsersShowProps = PageProps & {\n  user: UsersShowOutput\n}")
+	is.NotIn(code, "UsersIndexProps")
+}
+
+func TestServe(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/users/controller.go"] = controller
+	td.Files["view/users/show.svelte"] = `<script>export let user = {}</script><h1>{user.name}</h1>`
+	td.NodeModules["svelte"] = versions.Svelte
+	td.NodeModules["livebud"] = "*"
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	res, err := app.Get("/bud/view/_client.js")
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	body := res.Body().String()
+	is.In(body, "function createClient(options = {})")
+	is.In(body, `index: (input) => send("GET", "/users", input)`)
+	// Types are compiled away
+	is.NotIn(body, "UsersIndexInput")
+}
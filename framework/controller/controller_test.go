@@ -3,8 +3,10 @@ package controller_test
 import (
 	"bytes"
 	"context"
+	"mime/multipart"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/lithammer/dedent"
@@ -13,6 +15,7 @@ import (
 	"github.com/livebud/bud/internal/testdir"
 	"github.com/livebud/bud/internal/versions"
 	"github.com/matthewmueller/diff"
+	"golang.org/x/net/websocket"
 )
 
 func TestNoActions(t *testing.T) {
@@ -821,6 +824,91 @@ func TestJSONMultipleActions(t *testing.T) {
 	is.NoErr(app.Close())
 }
 
+func TestRouteConstraints(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/controller.go"] = `
+		package controller
+		type Controller struct {}
+		func (c *Controller) Show(id int) int {
+			return id
+		}
+	`
+	td.Files["controller/posts/comments/controller.go"] = `
+		package comments
+		type Controller struct {}
+		func (c *Controller) Show(postID uint, id string) string {
+			return id
+		}
+	`
+	td.Files["controller/users/controller.go"] = `
+		package users
+		type Controller struct {}
+		func (c *Controller) Show(id int8) int8 {
+			return id
+		}
+	`
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	res, err := app.GetJSON("/10")
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 200 OK
+		Content-Type: application/json
+
+		10
+	`))
+	res, err = app.GetJSON("/ten")
+	is.NoErr(err)
+	is.NoErr(res.DiffHeaders(`
+		HTTP/1.1 404 Not Found
+		Content-Type: text/plain; charset=utf-8
+		X-Content-Type-Options: nosniff
+	`))
+	res, err = app.GetJSON("/posts/1/comments/first")
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 200 OK
+		Content-Type: application/json
+
+		"first"
+	`))
+	res, err = app.GetJSON("/posts/-1/comments/first")
+	is.NoErr(err)
+	is.NoErr(res.DiffHeaders(`
+		HTTP/1.1 404 Not Found
+		Content-Type: text/plain; charset=utf-8
+		X-Content-Type-Options: nosniff
+	`))
+	// Sized integers don't match values that would overflow
+	res, err = app.GetJSON("/users/127")
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 200 OK
+		Content-Type: application/json
+
+		127
+	`))
+	res, err = app.GetJSON("/users/128")
+	is.NoErr(err)
+	is.NoErr(res.DiffHeaders(`
+		HTTP/1.1 404 Not Found
+		Content-Type: text/plain; charset=utf-8
+		X-Content-Type-Options: nosniff
+	`))
+	// Paths leave out the constraints
+	code, err := os.ReadFile(filepath.Join(dir, "bud/internal/app/controller/controller.go"))
+	is.NoErr(err)
+	is.In(string(code), `return "/users/:id"`)
+	is.True(!strings.Contains(string(code), `return "/users/:id|int8"`))
+	is.NoErr(app.Close())
+}
+
 func TestJSONUpdate500(t *testing.T) {
 	is := is.New(t)
 	ctx := context.Background()
@@ -2228,48 +2316,43 @@ func TestRedirectBack(t *testing.T) {
 	req.Header.Set("Referer", "/new")
 	res, err := app.Do(req)
 	is.NoErr(err)
-	is.NoErr(res.DiffHeaders(`
-		HTTP/1.1 303 See Other
-		Location: /new
-	`))
+	is.Equal(res.Status(), 303)
+	is.Equal(res.Header("Location"), "/new")
+	is.True(strings.HasPrefix(res.Header("Set-Cookie"), "bud_session="))
 	// Post request, no referer
 	req, err = app.PostRequest("/", nil)
 	is.NoErr(err)
 	res, err = app.Do(req)
 	is.NoErr(err)
-	is.NoErr(res.DiffHeaders(`
-		HTTP/1.1 303 See Other
-		Location: /
-	`))
+	is.Equal(res.Status(), 303)
+	is.Equal(res.Header("Location"), "/")
+	is.True(strings.HasPrefix(res.Header("Set-Cookie"), "bud_session="))
 	// Patch request
 	req, err = app.PatchRequest("/10", nil)
 	is.NoErr(err)
 	req.Header.Set("Referer", "/10/edit")
 	res, err = app.Do(req)
 	is.NoErr(err)
-	is.NoErr(res.DiffHeaders(`
-		HTTP/1.1 303 See Other
-		Location: /10/edit
-	`))
+	is.Equal(res.Status(), 303)
+	is.Equal(res.Header("Location"), "/10/edit")
+	is.True(strings.HasPrefix(res.Header("Set-Cookie"), "bud_session="))
 	// Patch request, no referer
 	req, err = app.PatchRequest("/10", nil)
 	is.NoErr(err)
 	res, err = app.Do(req)
 	is.NoErr(err)
-	is.NoErr(res.DiffHeaders(`
-		HTTP/1.1 303 See Other
-		Location: /10
-	`))
+	is.Equal(res.Status(), 303)
+	is.Equal(res.Header("Location"), "/10")
+	is.True(strings.HasPrefix(res.Header("Set-Cookie"), "bud_session="))
 	// Delete request
 	req, err = app.DeleteRequest("/10", nil)
 	is.NoErr(err)
 	req.Header.Set("Referer", "/10")
 	res, err = app.Do(req)
 	is.NoErr(err)
-	is.NoErr(res.DiffHeaders(`
-		HTTP/1.1 303 See Other
-		Location: /10
-	`))
+	is.Equal(res.Status(), 303)
+	is.Equal(res.Header("Location"), "/10")
+	is.True(strings.HasPrefix(res.Header("Set-Cookie"), "bud_session="))
 }
 
 func TestInject(t *testing.T) {
@@ -2792,3 +2875,657 @@ func TestIndexControllerWithRootIndexAction(t *testing.T) {
 	`))
 	is.NoErr(app.Close())
 }
+
+func TestControllerMiddleware(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/controller.go"] = `
+		package controller
+		type Controller struct {}
+		func (c *Controller) Index() string {
+			return "home"
+		}
+	`
+	td.Files["controller/admin/controller.go"] = `
+		package admin
+		import "net/http"
+		type Controller struct {}
+		func (c *Controller) Middleware(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") == "" {
+					http.Error(w, "unauthorized", http.StatusUnauthorized)
+					return
+				}
+				next.ServeHTTP(w, r)
+			})
+		}
+		func (c *Controller) Index() string {
+			return "admin"
+		}
+	`
+	td.Files["controller/admin/users/controller.go"] = `
+		package users
+		type Controller struct {}
+		func (c *Controller) Index() string {
+			return "users"
+		}
+	`
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	// Routes outside of /admin don't run the middleware
+	res, err := app.GetJSON("/")
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 200 OK
+		Content-Type: application/json
+
+		"home"
+	`))
+	// Middleware short-circuits the request
+	res, err = app.GetJSON("/admin")
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 401 Unauthorized
+		Content-Type: text/plain; charset=utf-8
+		X-Content-Type-Options: nosniff
+
+		unauthorized
+	`))
+	res, err = app.GetJSON("/admin/1/users")
+	is.NoErr(err)
+	is.Equal(res.Status(), 401)
+	// Middleware passes the request through to the actions
+	req, err := app.GetRequest("/admin")
+	is.NoErr(err)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	res, err = app.Do(req)
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 200 OK
+		Content-Type: application/json
+
+		"admin"
+	`))
+	req, err = app.GetRequest("/admin/1/users")
+	is.NoErr(err)
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer token")
+	res, err = app.Do(req)
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 200 OK
+		Content-Type: application/json
+
+		"users"
+	`))
+	is.NoErr(app.Close())
+}
+
+func TestFileUpload(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/controller.go"] = `
+		package controller
+		import (
+			"io"
+			"github.com/livebud/bud/framework/controller/controllerrt/request"
+		)
+		type Controller struct {}
+		type Upload struct {
+			Title string
+			Name string
+			Size int64
+			Type string
+			Data string
+		}
+		func (c *Controller) Create(title string, avatar *request.File) (*Upload, error) {
+			file, err := avatar.Open()
+			if err != nil {
+				return nil, err
+			}
+			defer file.Close()
+			data, err := io.ReadAll(file)
+			if err != nil {
+				return nil, err
+			}
+			return &Upload{title, avatar.Name, avatar.Size, avatar.ContentType, string(data)}, nil
+		}
+	`
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	is.NoErr(writer.WriteField("title", "profile"))
+	part, err := writer.CreateFormFile("avatar", "avatar.txt")
+	is.NoErr(err)
+	_, err = part.Write([]byte("some image"))
+	is.NoErr(err)
+	is.NoErr(writer.Close())
+	req, err := app.PostRequest("/", body)
+	is.NoErr(err)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Accept", "application/json")
+	res, err := app.Do(req)
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 200 OK
+		Content-Type: application/json
+
+		{"Title":"profile","Name":"avatar.txt","Size":10,"Type":"application/octet-stream","Data":"some image"}
+	`))
+}
+
+func TestValidation(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/controller.go"] = `
+		package controller
+		type Controller struct {}
+		type Post struct {
+			Title string ` + "`" + `json:"title" validate:"required,max=10"` + "`" + `
+			Status string ` + "`" + `json:"status" validate:"oneof=draft published"` + "`" + `
+		}
+		func (c *Controller) Create(post *Post) *Post {
+			return post
+		}
+	`
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	res, err := app.PostJSON("/", bytes.NewBufferString(`{"status":"archived"}`))
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 422 Unprocessable Entity
+		Content-Type: application/json
+
+		{"errors":{"status":"must be one of draft, published","title":"is required"}}
+	`))
+	res, err = app.PostJSON("/", bytes.NewBufferString(`{"title":"hello","status":"draft"}`))
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 200 OK
+		Content-Type: application/json
+
+		{"title":"hello","status":"draft"}
+	`))
+	// HTML forms redirect back with the errors
+	req, err := app.PostRequest("/", bytes.NewBufferString(`title=hello+world!`))
+	is.NoErr(err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Referer", "/new")
+	res, err = app.Do(req)
+	is.NoErr(err)
+	is.Equal(res.Status(), 303)
+	is.Equal(res.Header("Location"), "/new")
+	is.True(strings.HasPrefix(res.Header("Set-Cookie"), "bud_session="))
+}
+
+func TestFlash(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.NodeModules["svelte"] = versions.Svelte
+	td.Files["view/index.svelte"] = `
+		<script>
+			export let flash = {}
+		</script>
+		<h1>{flash.notice || "no notice"}</h1>
+	`
+	td.Files["controller/controller.go"] = `
+		package controller
+		import "github.com/livebud/bud/package/session"
+		type Controller struct {
+			Flash *session.Flash
+		}
+		func (c *Controller) Index() {}
+		func (c *Controller) Create() error {
+			return c.Flash.Set("notice", "created")
+		}
+	`
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	res, err := app.Post("/", nil)
+	is.NoErr(err)
+	is.Equal(res.Status(), 302)
+	is.Equal(res.Header("Location"), "/")
+	cookie := res.Header("Set-Cookie")
+	is.True(strings.HasPrefix(cookie, "bud_session="))
+	// The flash appears on the next render
+	req, err := app.GetRequest("/")
+	is.NoErr(err)
+	req.Header.Set("Cookie", strings.Split(cookie, ";")[0])
+	res, err = app.Do(req)
+	is.NoErr(err)
+	el, err := res.Query("#bud_target")
+	is.NoErr(err)
+	html, err := el.Html()
+	is.NoErr(err)
+	is.Equal(`<h1>created</h1>`, html)
+	// Then it's cleared
+	res, err = app.Get("/")
+	is.NoErr(err)
+	el, err = res.Query("#bud_target")
+	is.NoErr(err)
+	html, err = el.Html()
+	is.NoErr(err)
+	is.Equal(`<h1>no notice</h1>`, html)
+	is.NoErr(app.Close())
+}
+
+func TestRenderFormats(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/controller.go"] = `
+		package controller
+		type Controller struct {}
+		type User struct {
+			ID int ` + "`" + `json:"id"` + "`" + `
+			Name string ` + "`" + `json:"name"` + "`" + `
+		}
+		type Users []*User
+		func (Users) Formats() []string {
+			return []string{"csv", "ndjson"}
+		}
+		func (c *Controller) Index() Users {
+			return Users{{1, "a"}, {2, "b"}}
+		}
+	`
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	req, err := app.GetRequest("/")
+	is.NoErr(err)
+	req.Header.Set("Accept", "text/csv")
+	res, err := app.Do(req)
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 200 OK
+		Content-Type: text/csv; charset=utf-8
+
+		id,name
+		1,a
+		2,b
+	`))
+	req, err = app.GetRequest("/")
+	is.NoErr(err)
+	req.Header.Set("Accept", "application/x-ndjson")
+	res, err = app.Do(req)
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 200 OK
+		Content-Type: application/x-ndjson
+
+		{"id":1,"name":"a"}
+		{"id":2,"name":"b"}
+	`))
+	req, err = app.GetRequest("/")
+	is.NoErr(err)
+	req.Header.Set("Accept", "application/xml")
+	res, err = app.Do(req)
+	is.NoErr(err)
+	is.Equal(res.Status(), 406)
+}
+
+func TestTypedErrors(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/controller.go"] = `
+		package controller
+		import "github.com/livebud/bud/framework/controller/controllerrt/response"
+		type Controller struct {}
+		type Post struct {
+			ID int ` + "`" + `json:"id"` + "`" + `
+		}
+		type Created struct {
+			response.Meta
+			*Post
+		}
+		func (c *Controller) Show(id int) (*Post, error) {
+			return nil, response.NotFound("post %d not found", id)
+		}
+		func (c *Controller) Create() (*Created, error) {
+			meta := response.Meta{Status: 201, Header: map[string][]string{"Location": {"/1"}}}
+			return &Created{meta, &Post{1}}, nil
+		}
+		func (c *Controller) Update(id int) error {
+			return response.Conflict("post %d was changed", id)
+		}
+	`
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	res, err := app.GetJSON("/1")
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 404 Not Found
+		Content-Type: application/json
+
+		{"error":"post 1 not found"}
+	`))
+	res, err = app.Get("/1")
+	is.NoErr(err)
+	is.Equal(res.Status(), 404)
+	res, err = app.PostJSON("/", nil)
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 201 Created
+		Content-Type: application/json
+		Location: /1
+
+		{"id":1}
+	`))
+	res, err = app.PatchJSON("/1", nil)
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 409 Conflict
+		Content-Type: application/json
+
+		{"error":"post 1 was changed"}
+	`))
+}
+
+func TestStreamResults(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/controller.go"] = `
+		package controller
+		import (
+			"context"
+			"io"
+			"strings"
+		)
+		type Controller struct {}
+		type Tick struct {
+			N int ` + "`" + `json:"n"` + "`" + `
+		}
+		func (c *Controller) Index(ctx context.Context) <-chan *Tick {
+			ch := make(chan *Tick)
+			go func() {
+				defer close(ch)
+				for i := 1; i <= 2; i++ {
+					select {
+					case <-ctx.Done():
+						return
+					case ch <- &Tick{i}:
+					}
+				}
+			}()
+			return ch
+		}
+		func (c *Controller) Show(id int) (func(yield func(int) bool), error) {
+			return func(yield func(int) bool) {
+				for i := 0; i < id; i++ {
+					if !yield(i) {
+						return
+					}
+				}
+			}, nil
+		}
+		func (c *Controller) Edit() io.Reader {
+			return strings.NewReader("hello\nworld\n")
+		}
+	`
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	res, err := app.Get("/")
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	is.Equal(res.Header("Content-Type"), "application/x-ndjson")
+	is.Equal(res.Body().String(), "{\"n\":1}\n{\"n\":2}\n")
+	req, err := app.GetRequest("/")
+	is.NoErr(err)
+	req.Header.Set("Accept", "text/event-stream")
+	res, err = app.Do(req)
+	is.NoErr(err)
+	is.Equal(res.Header("Content-Type"), "text/event-stream")
+	is.Equal(res.Body().String(), "data: {\"n\":1}\n\ndata: {\"n\":2}\n\n")
+	res, err = app.Get("/3")
+	is.NoErr(err)
+	is.Equal(res.Body().String(), "0\n1\n2\n")
+	res, err = app.Get("/1/edit")
+	is.NoErr(err)
+	is.Equal(res.Header("Content-Type"), "text/plain; charset=utf-8")
+	is.Equal(res.Body().String(), "hello\nworld\n")
+}
+
+func TestWebSocket(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/controller.go"] = `
+		package controller
+		import (
+			"context"
+			"github.com/livebud/bud/framework/controller/controllerrt/websocket"
+		)
+		type Controller struct {}
+		type Message struct {
+			Text string ` + "`" + `json:"text"` + "`" + `
+		}
+		func (c *Controller) Chat(ctx context.Context, room string, conn *websocket.Conn) error {
+			for {
+				var msg Message
+				if err := conn.Receive(&msg); err != nil {
+					return err
+				}
+				if err := conn.Send(&Message{room + ": " + msg.Text}); err != nil {
+					return err
+				}
+			}
+		}
+	`
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	conn, err := app.Socket("/chat?room=lobby")
+	is.NoErr(err)
+	defer conn.Close()
+	is.NoErr(websocket.JSON.Send(conn, map[string]string{"text": "hi"}))
+	var msg map[string]string
+	is.NoErr(websocket.JSON.Receive(conn, &msg))
+	is.Equal(msg["text"], "lobby: hi")
+	// Regular requests need to upgrade
+	res, err := app.Get("/chat")
+	is.NoErr(err)
+	is.Equal(res.Status(), 426)
+}
+
+func TestParentLoader(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/users/controller.go"] = `
+		package users
+		import (
+			"context"
+			"errors"
+		)
+		type Controller struct {}
+		type User struct {
+			ID   int    ` + "`" + `json:"id"` + "`" + `
+			Name string ` + "`" + `json:"name"` + "`" + `
+		}
+		func (c *Controller) Load(ctx context.Context, userID int) (*User, error) {
+			if userID > 10 {
+				return nil, errors.New("user not found")
+			}
+			return &User{ID: userID, Name: "Alice"}, nil
+		}
+		func (c *Controller) Show(id int) *User {
+			return &User{ID: id}
+		}
+	`
+	td.Files["controller/users/posts/controller.go"] = `
+		package posts
+		import "app.com/controller/users"
+		type Controller struct {}
+		type Post struct {
+			ID     int    ` + "`" + `json:"id"` + "`" + `
+			Author string ` + "`" + `json:"author"` + "`" + `
+		}
+		func (c *Controller) Index(user *users.User) []*Post {
+			return []*Post{{ID: 1, Author: user.Name}}
+		}
+		func (c *Controller) Show(user *users.User, id int) *Post {
+			return &Post{ID: id, Author: user.Name}
+		}
+	`
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	res, err := app.GetJSON("/users/1/posts")
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 200 OK
+		Content-Type: application/json
+
+		[{"id":1,"author":"Alice"}]
+	`))
+	res, err = app.GetJSON("/users/1/posts/2")
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 200 OK
+		Content-Type: application/json
+
+		{"id":2,"author":"Alice"}
+	`))
+	// Errors from loading the parent stop the request
+	res, err = app.GetJSON("/users/11/posts")
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 500 Internal Server Error
+		Content-Type: application/json
+
+		{"error":"user not found"}
+	`))
+	// Parent slots are typed by the loader
+	res, err = app.GetJSON("/users/alice/posts")
+	is.NoErr(err)
+	is.Equal(res.Status(), 404)
+}
+
+func TestHooks(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/controller.go"] = `
+		package controller
+		import (
+			"context"
+			"net/http"
+			"github.com/livebud/bud/framework/controller/controllerrt/response"
+		)
+		type Controller struct {}
+		var calls []string
+		func (c *Controller) Before(ctx context.Context, token string) http.Handler {
+			calls = []string{"before"}
+			if token == "" {
+				return response.Status(401).JSON(map[string]string{"error": "missing token"})
+			}
+			return nil
+		}
+		func (c *Controller) Around(next func() error) error {
+			calls = append(calls, "around")
+			return next()
+		}
+		func (c *Controller) After(id int) error {
+			calls = append(calls, "after")
+			if id == 13 {
+				return response.Conflict("unlucky")
+			}
+			return nil
+		}
+		func (c *Controller) Index() []string {
+			return append(calls, "index")
+		}
+		func (c *Controller) Show(id int) (int, error) {
+			if id == 0 {
+				return 0, response.NotFound("not found")
+			}
+			return id, nil
+		}
+	`
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	res, err := app.GetJSON("/?token=secret")
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 200 OK
+		Content-Type: application/json
+
+		["before","around","index"]
+	`))
+	// Before responds instead of the action
+	res, err = app.GetJSON("/")
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 401 Unauthorized
+		Content-Type: application/json
+
+		{"error":"missing token"}
+	`))
+	// Errors from After replace the response
+	res, err = app.GetJSON("/13?token=secret")
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 409 Conflict
+		Content-Type: application/json
+
+		{"error":"unlucky"}
+	`))
+	// After doesn't run when the action fails
+	res, err = app.GetJSON("/0?token=secret")
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 404 Not Found
+		Content-Type: application/json
+
+		{"error":"not found"}
+	`))
+	// Hooks aren't routed
+	res, err = app.GetJSON("/before")
+	is.NoErr(err)
+	is.Equal(res.Status(), 404)
+}
@@ -0,0 +1,24 @@
+package request
+
+import (
+	"reflect"
+	"strings"
+)
+
+// FieldName returns the name of the field in the first of the struct tags that
+// names it, falling back to the field's name. Fields that are ignored with "-"
+// return an empty name.
+func FieldName(field reflect.StructField, tags ...string) string {
+	for _, tag := range tags {
+		name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+		switch name {
+		case "":
+			continue
+		case "-":
+			return ""
+		default:
+			return name
+		}
+	}
+	return field.Name
+}
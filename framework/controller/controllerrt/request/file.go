@@ -0,0 +1,123 @@
+package request
+
+import (
+	"io"
+	"mime/multipart"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// File is an uploaded file from a multipart form. Action parameters of type
+// *request.File or []*request.File are bound to the file parts of the form.
+type File struct {
+	Name        string // Original filename
+	Size        int64  // Size in bytes
+	ContentType string // Content type reported by the client
+	header      *multipart.FileHeader
+}
+
+// Open the file for reading. Callers are responsible for closing the file.
+func (f *File) Open() (io.ReadCloser, error) {
+	return f.header.Open()
+}
+
+func newFile(header *multipart.FileHeader) *File {
+	return &File{
+		Name:        header.Filename,
+		Size:        header.Size,
+		ContentType: header.Header.Get("Content-Type"),
+		header:      header,
+	}
+}
+
+var (
+	fileType  = reflect.TypeOf(&File{})
+	filesType = reflect.TypeOf([]*File{})
+)
+
+// bindFiles sets the *File and []*File fields of v from the form's files
+func bindFiles(v interface{}, files map[string][]*multipart.FileHeader) {
+	rv, ok := structValue(v)
+	if !ok {
+		return
+	}
+	for key, headers := range files {
+		if len(headers) == 0 {
+			continue
+		}
+		field, ok := findField(rv, key)
+		if !ok {
+			continue
+		}
+		switch field.Type() {
+		case fileType:
+			field.Set(reflect.ValueOf(newFile(headers[0])))
+		case filesType:
+			list := make([]*File, len(headers))
+			for i, header := range headers {
+				list[i] = newFile(header)
+			}
+			field.Set(reflect.ValueOf(list))
+		}
+	}
+}
+
+// formValues removes the values that belong to file fields. Browsers send an
+// empty value instead of a file when no file was selected.
+func formValues(v interface{}, values map[string][]string) url.Values {
+	rv, ok := structValue(v)
+	if !ok {
+		return values
+	}
+	formValues := url.Values{}
+	for key, value := range values {
+		if field, ok := findField(rv, key); ok && isFile(field.Type()) {
+			continue
+		}
+		formValues[key] = value
+	}
+	return formValues
+}
+
+func isFile(t reflect.Type) bool {
+	return t == fileType || t == filesType
+}
+
+func structValue(v interface{}) (reflect.Value, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return rv, false
+		}
+		rv = rv.Elem()
+	}
+	return rv, rv.Kind() == reflect.Struct
+}
+
+// findField finds the field by its form or json tag, falling back to a
+// case-insensitive match on the field name. This follows how form values are
+// decoded.
+func findField(rv reflect.Value, key string) (reflect.Value, bool) {
+	rt := rv.Type()
+	match := -1
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := FieldName(field, "form", "json")
+		if name == "" {
+			continue
+		} else if name == key {
+			return rv.Field(i), true
+		}
+		if match == -1 && strings.EqualFold(name, key) {
+			match = i
+		}
+	}
+	if match == -1 {
+		return reflect.Value{}, false
+	}
+	return rv.Field(match), true
+}
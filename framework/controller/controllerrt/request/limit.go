@@ -0,0 +1,49 @@
+package request
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/livebud/bud/package/middleware"
+)
+
+// Limits on the size of the request body
+type Limits struct {
+	// Memory is the number of bytes of a multipart form that are kept in
+	// memory. File parts beyond this limit are written to temporary files on
+	// disk.
+	Memory int64
+	// Body is the total number of bytes allowed in the request body, including
+	// the file parts of a multipart form that are written to disk.
+	Body int64
+}
+
+// DefaultLimits keeps up to 32MB of a multipart form in memory and allows
+// request bodies of up to 1GB
+func DefaultLimits() *Limits {
+	return &Limits{
+		Memory: 32 << 20, // 32MB
+		Body:   1 << 30,  // 1GB
+	}
+}
+
+type limitsKey struct{}
+
+// Limit the size of the request bodies that are unmarshaled further down the
+// middleware stack
+func Limit(limits *Limits) middleware.Middleware {
+	return middleware.Function(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), limitsKey{}, limits)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+}
+
+// limitsFrom returns the limits set by Limit or the default limits
+func limitsFrom(ctx context.Context) *Limits {
+	if limits, ok := ctx.Value(limitsKey{}).(*Limits); ok {
+		return limits
+	}
+	return DefaultLimits()
+}
@@ -17,8 +17,8 @@ type Context struct {
 }
 
 // Unmarshal the request body or parameters
-func (c *Context) Unmarshal(r *http.Request, in interface{}) error {
-	return Unmarshal(r, in)
+func (c *Context) Unmarshal(w http.ResponseWriter, r *http.Request, in interface{}) error {
+	return Unmarshal(w, r, in)
 }
 
 // Accepts a type
@@ -33,3 +33,9 @@ type Acceptable accept.AcceptSlice
 func (as Acceptable) Accepts(ctype string) bool {
 	return accept.AcceptSlice(as).Accepts(ctype)
 }
+
+// Negotiate returns the most preferred of the content types or an empty string
+// if none of them are acceptable
+func (as Acceptable) Negotiate(ctypes ...string) (string, error) {
+	return accept.AcceptSlice(as).Negotiate(ctypes...)
+}
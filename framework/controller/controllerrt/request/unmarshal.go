@@ -2,6 +2,7 @@ package request
 
 import (
 	"encoding/json"
+	"errors"
 	"io"
 	"io/ioutil"
 	"mime"
@@ -11,20 +12,68 @@ import (
 	"github.com/ajg/form"
 )
 
-// Unmarshal the request data into v
-func Unmarshal(r *http.Request, v interface{}) error {
-	err := unmarshalBody(r, v)
-	if err != nil {
-		return err
+// Error unmarshaling the request. Request bodies over the limit are 413
+// Request Entity Too Large, other errors are 400 Bad Request.
+type Error struct {
+	err error
+}
+
+var _ error = (*Error)(nil)
+
+func (e *Error) Error() string {
+	return e.err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// StatusCode of the error
+func (e *Error) StatusCode() int {
+	var maxBytes *http.MaxBytesError
+	if errors.As(e.err, &maxBytes) {
+		return http.StatusRequestEntityTooLarge
 	}
-	err = unmarshalURL(r.URL, v)
-	if err != nil {
-		return err
+	return http.StatusBadRequest
+}
+
+// Unmarshal the request data into v, limiting the request body to the limits
+// set by Limit
+func Unmarshal(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	return limitsFrom(r.Context()).Unmarshal(w, r, v)
+}
+
+// Unmarshal the request data into v within the limits
+func (l *Limits) Unmarshal(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	if err := l.unmarshalBody(w, r, v); err != nil {
+		return &Error{err}
+	}
+	if err := unmarshalURL(r.URL, v); err != nil {
+		return &Error{err}
 	}
 	return nil
 }
 
-func unmarshalBody(r *http.Request, v interface{}) error {
+// RemoveFiles removes the temporary files of the multipart form unmarshaled
+// from the request. The server only removes the files of the request it passed
+// into the handler, not of the copies made further down the middleware stack.
+func RemoveFiles(r *http.Request) error {
+	if r.MultipartForm == nil {
+		return nil
+	}
+	return r.MultipartForm.RemoveAll()
+}
+
+// UnmarshalURL unmarshals the route's slots and the query string into v,
+// leaving the body unread
+func UnmarshalURL(r *http.Request, v interface{}) error {
+	if err := unmarshalURL(r.URL, v); err != nil {
+		return &Error{err}
+	}
+	return nil
+}
+
+func (l *Limits) unmarshalBody(w http.ResponseWriter, r *http.Request, v interface{}) error {
 	contentType := r.Header.Get("Content-Type")
 	if contentType == "" {
 		return nil
@@ -35,9 +84,11 @@ func unmarshalBody(r *http.Request, v interface{}) error {
 	}
 	switch mediaType {
 	case "application/json":
-		return unmarshalJSON(r.Body, v)
+		return unmarshalJSON(http.MaxBytesReader(w, r.Body, l.Body), v)
 	case "application/x-www-form-urlencoded":
-		return unmarshalForm(r, v)
+		return l.unmarshalForm(w, r, v)
+	case "multipart/form-data":
+		return l.unmarshalMultipart(w, r, v)
 	}
 	return nil
 }
@@ -49,9 +100,12 @@ func unmarshalURL(u *url.URL, v interface{}) error {
 	return dec.DecodeValues(v, u.Query())
 }
 
-func unmarshalForm(r *http.Request, v interface{}) error {
+func (l *Limits) unmarshalForm(w http.ResponseWriter, r *http.Request, v interface{}) error {
 	if r.PostForm == nil {
-		r.ParseForm()
+		r.Body = http.MaxBytesReader(w, r.Body, l.Body)
+		if err := r.ParseForm(); err != nil {
+			return err
+		}
 	}
 	dec := form.NewDecoder(nil)
 	dec.IgnoreCase(true)
@@ -59,6 +113,23 @@ func unmarshalForm(r *http.Request, v interface{}) error {
 	return dec.DecodeValues(v, r.PostForm)
 }
 
+func (l *Limits) unmarshalMultipart(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	if r.MultipartForm == nil {
+		r.Body = http.MaxBytesReader(w, r.Body, l.Body)
+		if err := r.ParseMultipartForm(l.Memory); err != nil {
+			return err
+		}
+	}
+	dec := form.NewDecoder(nil)
+	dec.IgnoreCase(true)
+	dec.IgnoreUnknownKeys(true)
+	if err := dec.DecodeValues(v, formValues(v, r.MultipartForm.Value)); err != nil {
+		return err
+	}
+	bindFiles(v, r.MultipartForm.File)
+	return nil
+}
+
 func unmarshalJSON(r io.Reader, v interface{}) error {
 	data, err := ioutil.ReadAll(r)
 	if err != nil {
@@ -2,7 +2,12 @@ package request_test
 
 import (
 	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 
 	. "github.com/livebud/bud/framework/controller/controllerrt/request"
@@ -15,7 +20,7 @@ func TestJSONEmpty(t *testing.T) {
 	s := S{}
 	r := httptest.NewRequest("GET", "/", nil)
 	r.Header.Add("Content-Type", "application/json")
-	err := Unmarshal(r, &s)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
 	is.NoErr(err)
 }
 
@@ -25,7 +30,7 @@ func TestFormEmpty(t *testing.T) {
 	s := S{}
 	r := httptest.NewRequest("GET", "/", nil)
 	r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	err := Unmarshal(r, &s)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
 	is.NoErr(err)
 }
 
@@ -38,7 +43,7 @@ func TestStringJSONEmpties(t *testing.T) {
 	s := S{}
 	r := httptest.NewRequest("GET", "/", nil)
 	r.Header.Add("Content-Type", "application/json")
-	err := Unmarshal(r, &s)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
 	is.NoErr(err)
 	is.Equal("", s.A)
 	is.Equal("", s.B)
@@ -53,7 +58,7 @@ func TestStringFormEmpties(t *testing.T) {
 	s := S{}
 	r := httptest.NewRequest("GET", "/", nil)
 	r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	err := Unmarshal(r, &s)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
 	is.NoErr(err)
 	is.Equal("", s.A)
 	is.Equal("", s.B)
@@ -68,7 +73,7 @@ func TestStringJSONEmptyQuery(t *testing.T) {
 	s := S{}
 	r := httptest.NewRequest("GET", "/?a=a&b=b", nil)
 	r.Header.Add("Content-Type", "application/json")
-	err := Unmarshal(r, &s)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
 	is.NoErr(err)
 	is.Equal("a", s.A)
 	is.Equal("b", s.B)
@@ -83,7 +88,7 @@ func TestStringFormEmptyQuery(t *testing.T) {
 	s := S{}
 	r := httptest.NewRequest("GET", "/?a=a&b=b", nil)
 	r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	err := Unmarshal(r, &s)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
 	is.NoErr(err)
 	is.Equal("a", s.A)
 	is.Equal("b", s.B)
@@ -99,7 +104,7 @@ func TestStringJSONQuery(t *testing.T) {
 	s := S{}
 	r := httptest.NewRequest("GET", "/?a=a&b=b", bytes.NewBufferString(`{"c":"c"}`))
 	r.Header.Add("Content-Type", "application/json")
-	err := Unmarshal(r, &s)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
 	is.NoErr(err)
 	is.Equal("a", s.A)
 	is.Equal("b", s.B)
@@ -116,7 +121,7 @@ func TestStringFormQuery(t *testing.T) {
 	s := S{}
 	r := httptest.NewRequest("GET", "/?a=a&b=b", bytes.NewBufferString(`c=c`))
 	r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	err := Unmarshal(r, &s)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
 	is.NoErr(err)
 	is.Equal("a", s.A)
 	is.Equal("b", s.B)
@@ -134,7 +139,7 @@ func TestStringFormQuery(t *testing.T) {
 // 	s := S{}
 // 	r := httptest.NewRequest("GET", "/?a=a&b=b", bytes.NewBufferString(`{"c":"c"}`))
 // 	r.Header.Add("Content-Type", "application/json")
-// 	err := Unmarshal(r, &s)
+// 	err := Unmarshal(httptest.NewRecorder(), r, &s)
 // 	is.NoErr(err)
 // 	is.Equal( "a", s.A)
 // 	is.Equal( "b", s.B)
@@ -153,7 +158,7 @@ func TestStringFormQuery(t *testing.T) {
 // 	s := S{}
 // 	r := httptest.NewRequest("GET", "/?a=a&b=b", bytes.NewBufferString(`c=c`))
 // 	r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-// 	err := Unmarshal(r, &s)
+// 	err := Unmarshal(httptest.NewRecorder(), r, &s)
 // 	is.NoErr(err)
 // 	is.Equal( "a", s.A)
 // 	is.Equal( "b", s.B)
@@ -170,7 +175,7 @@ func TestStringJSONQueryOverride(t *testing.T) {
 	s := S{}
 	r := httptest.NewRequest("GET", "/?a=a&b=b", bytes.NewBufferString(`{"b":"c"}`))
 	r.Header.Add("Content-Type", "application/json")
-	err := Unmarshal(r, &s)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
 	is.NoErr(err)
 	is.Equal("a", s.A)
 	is.Equal("b", s.B)
@@ -185,7 +190,7 @@ func TestStringFormQueryOverride(t *testing.T) {
 	s := S{}
 	r := httptest.NewRequest("GET", "/?a=a&b=b", bytes.NewBufferString(`b=c`))
 	r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	err := Unmarshal(r, &s)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
 	is.NoErr(err)
 	is.Equal("a", s.A)
 	is.Equal("b", s.B)
@@ -200,7 +205,7 @@ func TestNumberJSONEmpties(t *testing.T) {
 	s := S{}
 	r := httptest.NewRequest("GET", "/", nil)
 	r.Header.Add("Content-Type", "application/json")
-	err := Unmarshal(r, &s)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
 	is.NoErr(err)
 	is.Equal(0, s.A)
 	is.Equal(0.0, s.B)
@@ -215,7 +220,7 @@ func TestNumberFormEmpties(t *testing.T) {
 	s := S{}
 	r := httptest.NewRequest("GET", "/", nil)
 	r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	err := Unmarshal(r, &s)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
 	is.NoErr(err)
 	is.Equal(0, s.A)
 	is.Equal(0.0, s.B)
@@ -230,7 +235,7 @@ func TestNumberJSONEmptyQuery(t *testing.T) {
 	s := S{}
 	r := httptest.NewRequest("GET", "/?a=20&b=10.2", nil)
 	r.Header.Add("Content-Type", "application/json")
-	err := Unmarshal(r, &s)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
 	is.NoErr(err)
 	is.Equal(20, s.A)
 	is.Equal(10.2, s.B)
@@ -245,7 +250,7 @@ func TestNumberFormEmptyQuery(t *testing.T) {
 	s := S{}
 	r := httptest.NewRequest("GET", "/?a=1&b=2.2", nil)
 	r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	err := Unmarshal(r, &s)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
 	is.NoErr(err)
 	is.Equal(1, s.A)
 	is.Equal(2.2, s.B)
@@ -262,7 +267,7 @@ func TestNumberJSONQuery(t *testing.T) {
 	s := S{}
 	r := httptest.NewRequest("GET", "/?a=1&b=2.2", bytes.NewBufferString(`{"c":3,"d":4.4}`))
 	r.Header.Add("Content-Type", "application/json")
-	err := Unmarshal(r, &s)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
 	is.NoErr(err)
 	is.Equal(1, s.A)
 	is.Equal(2.2, s.B)
@@ -281,7 +286,7 @@ func TestNumberJSONQuery(t *testing.T) {
 // 	s := S{}
 // 	r := httptest.NewRequest("GET", "/?a=1&b=2.2", bytes.NewBufferString(`c=3&d=4.4`))
 // 	r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-// 	err := Unmarshal(r, &s)
+// 	err := Unmarshal(httptest.NewRecorder(), r, &s)
 // 	is.NoErr(err)
 // 	is.Equal( 1, s.A)
 // 	is.Equal( 2.2, s.B)
@@ -300,7 +305,7 @@ func TestNumberJSONQuery(t *testing.T) {
 // 	s := S{}
 // 	r := httptest.NewRequest("GET", "/?a=1&b=2.2", bytes.NewBufferString(`{"c":3}`))
 // 	r.Header.Add("Content-Type", "application/json")
-// 	err := Unmarshal(r, &s)
+// 	err := Unmarshal(httptest.NewRecorder(), r, &s)
 // 	is.NoErr(err)
 // 	is.Equal( 1, s.A)
 // 	is.Equal( 2.2, s.B)
@@ -319,7 +324,7 @@ func TestNumberJSONQuery(t *testing.T) {
 // 	s := S{}
 // 	r := httptest.NewRequest("GET", "/?a=1&b=2.2", bytes.NewBufferString(`c=3`))
 // 	r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-// 	err := Unmarshal(r, &s)
+// 	err := Unmarshal(httptest.NewRecorder(), r, &s)
 // 	is.NoErr(err)
 // 	is.Equal( 1, s.A)
 // 	is.Equal( 2.2, s.B)
@@ -336,7 +341,7 @@ func TestNumberJSONQueryOverride(t *testing.T) {
 	s := S{}
 	r := httptest.NewRequest("GET", "/?a=1&b=2.2", bytes.NewBufferString(`{"b":3.3}`))
 	r.Header.Add("Content-Type", "application/json")
-	err := Unmarshal(r, &s)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
 	is.NoErr(err)
 	is.Equal(1, s.A)
 	is.Equal(2.2, s.B)
@@ -351,7 +356,7 @@ func TestNumberFormQueryOverride(t *testing.T) {
 	s := S{}
 	r := httptest.NewRequest("GET", "/?a=1&b=2.2", bytes.NewBufferString(`b=3.3`))
 	r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	err := Unmarshal(r, &s)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
 	is.NoErr(err)
 	is.Equal(1, s.A)
 	is.Equal(2.2, s.B)
@@ -364,7 +369,7 @@ func TestJSONKey(t *testing.T) {
 	}
 	s := S{}
 	r := httptest.NewRequest("GET", "/?post_id=1", nil)
-	err := Unmarshal(r, &s)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
 	is.NoErr(err)
 	is.Equal(1, s.PostID)
 }
@@ -376,7 +381,7 @@ func TestFormKey(t *testing.T) {
 	}
 	s := S{}
 	r := httptest.NewRequest("GET", "/?post_id=1", nil)
-	err := Unmarshal(r, &s)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
 	is.NoErr(err)
 	is.Equal(1, s.PostID)
 }
@@ -390,9 +395,247 @@ func TestJSONGet(t *testing.T) {
 	}
 	s := S{}
 	r := httptest.NewRequest("GET", "/?post_id=10&order=asc&author=Alice", nil)
-	err := Unmarshal(r, &s)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
 	is.NoErr(err)
 	is.Equal(10, s.PostID)
 	is.Equal("asc", s.Order)
 	is.Equal("Alice", s.Author)
 }
+
+func multipartRequest(t testing.TB, fields map[string]string, files map[string][]string) *http.Request {
+	t.Helper()
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	for key, value := range fields {
+		if err := writer.WriteField(key, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for key, contents := range files {
+		for i, content := range contents {
+			part, err := writer.CreateFormFile(key, fmt.Sprintf("%s%d.txt", key, i))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := part.Write([]byte(content)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest("POST", "/", body)
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+	return r
+}
+
+func readFile(t testing.TB, file *File) string {
+	t.Helper()
+	rc, err := file.Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestMultipartFields(t *testing.T) {
+	is := is.New(t)
+	type S struct {
+		Title  string `json:"title"`
+		PostID int    `json:"post_id"`
+	}
+	s := S{}
+	r := multipartRequest(t, map[string]string{"title": "hello", "post_id": "10"}, nil)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
+	is.NoErr(err)
+	is.Equal("hello", s.Title)
+	is.Equal(10, s.PostID)
+}
+
+func TestMultipartFile(t *testing.T) {
+	is := is.New(t)
+	type S struct {
+		Title  string `json:"title"`
+		Avatar *File  `json:"avatar"`
+	}
+	s := S{}
+	r := multipartRequest(t, map[string]string{"title": "hello"}, map[string][]string{
+		"avatar": {"some image"},
+	})
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
+	is.NoErr(err)
+	is.Equal("hello", s.Title)
+	is.True(s.Avatar != nil)
+	is.Equal("avatar0.txt", s.Avatar.Name)
+	is.Equal(int64(10), s.Avatar.Size)
+	is.Equal("application/octet-stream", s.Avatar.ContentType)
+	is.Equal("some image", readFile(t, s.Avatar))
+}
+
+func TestMultipartFiles(t *testing.T) {
+	is := is.New(t)
+	type S struct {
+		Photos []*File
+	}
+	s := S{}
+	r := multipartRequest(t, nil, map[string][]string{
+		"photos": {"a", "bb"},
+	})
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
+	is.NoErr(err)
+	is.Equal(2, len(s.Photos))
+	is.Equal("photos0.txt", s.Photos[0].Name)
+	is.Equal("a", readFile(t, s.Photos[0]))
+	is.Equal("photos1.txt", s.Photos[1].Name)
+	is.Equal("bb", readFile(t, s.Photos[1]))
+}
+
+func TestMultipartFileMissing(t *testing.T) {
+	is := is.New(t)
+	type S struct {
+		Title  string
+		Avatar *File
+	}
+	s := S{}
+	r := multipartRequest(t, map[string]string{"title": "hello"}, nil)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
+	is.NoErr(err)
+	is.Equal("hello", s.Title)
+	is.Equal(nil, s.Avatar)
+}
+
+func TestMultipartFileEmpty(t *testing.T) {
+	is := is.New(t)
+	type S struct {
+		Avatar *File
+	}
+	s := S{}
+	// Browsers send an empty part when no file was selected
+	r := multipartRequest(t, map[string]string{"avatar": ""}, nil)
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
+	is.NoErr(err)
+	is.Equal(nil, s.Avatar)
+}
+
+func TestMultipartFileOnDisk(t *testing.T) {
+	is := is.New(t)
+	type S struct {
+		Avatar *File
+	}
+	s := S{}
+	r := multipartRequest(t, nil, map[string][]string{
+		"avatar": {"stored on disk"},
+	})
+	limits := &Limits{Memory: 1, Body: DefaultLimits().Body}
+	err := limits.Unmarshal(httptest.NewRecorder(), r, &s)
+	is.NoErr(err)
+	defer r.MultipartForm.RemoveAll()
+	is.Equal("stored on disk", readFile(t, s.Avatar))
+}
+
+func TestMultipartRemoveFiles(t *testing.T) {
+	is := is.New(t)
+	dir := t.TempDir()
+	t.Setenv("TMPDIR", dir)
+	type S struct {
+		Avatar *File
+	}
+	s := S{}
+	r := multipartRequest(t, nil, map[string][]string{
+		"avatar": {"stored on disk"},
+	})
+	// Unmarshal a copy of the request, like the handlers further down the
+	// middleware stack
+	r = r.WithContext(r.Context())
+	limits := &Limits{Memory: 1, Body: DefaultLimits().Body}
+	is.NoErr(limits.Unmarshal(httptest.NewRecorder(), r, &s))
+	des, err := os.ReadDir(dir)
+	is.NoErr(err)
+	is.Equal(len(des), 1)
+	is.NoErr(RemoveFiles(r))
+	des, err = os.ReadDir(dir)
+	is.NoErr(err)
+	is.Equal(len(des), 0)
+}
+
+func TestMultipartTooLarge(t *testing.T) {
+	is := is.New(t)
+	type S struct {
+		Avatar *File
+	}
+	s := S{}
+	r := multipartRequest(t, nil, map[string][]string{
+		"avatar": {"too large to upload"},
+	})
+	limits := &Limits{Memory: DefaultLimits().Memory, Body: 10}
+	err := limits.Unmarshal(httptest.NewRecorder(), r, &s)
+	is.True(err != nil)
+	is.Equal(http.StatusRequestEntityTooLarge, err.(*Error).StatusCode())
+	is.Equal(nil, s.Avatar)
+}
+
+func TestJSONTooLarge(t *testing.T) {
+	is := is.New(t)
+	type S struct {
+		A string
+	}
+	s := S{}
+	r := httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"a":"too large"}`))
+	r.Header.Add("Content-Type", "application/json")
+	limits := &Limits{Memory: DefaultLimits().Memory, Body: 10}
+	err := limits.Unmarshal(httptest.NewRecorder(), r, &s)
+	is.True(err != nil)
+	is.Equal(http.StatusRequestEntityTooLarge, err.(*Error).StatusCode())
+	is.Equal("", s.A)
+}
+
+func TestLimit(t *testing.T) {
+	is := is.New(t)
+	type S struct {
+		A string
+	}
+	s := S{}
+	var err error
+	handler := Limit(&Limits{Body: 10}).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err = Unmarshal(w, r, &s)
+	}))
+	r := httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"a":"too large"}`))
+	r.Header.Add("Content-Type", "application/json")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	is.True(err != nil)
+	is.Equal(http.StatusRequestEntityTooLarge, err.(*Error).StatusCode())
+}
+
+func TestInvalidBadRequest(t *testing.T) {
+	is := is.New(t)
+	type S struct {
+		A int
+	}
+	s := S{}
+	r := httptest.NewRequest("POST", "/", bytes.NewBufferString(`{"a":"not a number"}`))
+	r.Header.Add("Content-Type", "application/json")
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
+	is.True(err != nil)
+	is.Equal(http.StatusBadRequest, err.(*Error).StatusCode())
+}
+
+func TestMultipartQueryOverride(t *testing.T) {
+	is := is.New(t)
+	type S struct {
+		A int
+		B string
+	}
+	s := S{}
+	r := multipartRequest(t, map[string]string{"a": "2", "b": "b"}, nil)
+	r.URL.RawQuery = "a=1"
+	err := Unmarshal(httptest.NewRecorder(), r, &s)
+	is.NoErr(err)
+	is.Equal(1, s.A)
+	is.Equal("b", s.B)
+}
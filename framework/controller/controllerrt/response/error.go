@@ -0,0 +1,135 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/livebud/bud/package/session"
+)
+
+// Error with an HTTP status code. Actions return these to respond with
+// something other than 500 Internal Server Error.
+type Error struct {
+	Status  int
+	Message string
+}
+
+var _ error = (*Error)(nil)
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// StatusCode of the error
+func (e *Error) StatusCode() int {
+	return e.Status
+}
+
+func newError(status int, format string, args ...interface{}) error {
+	return &Error{status, fmt.Sprintf(format, args...)}
+}
+
+// BadRequest responds with 400 Bad Request
+func BadRequest(format string, args ...interface{}) error {
+	return newError(http.StatusBadRequest, format, args...)
+}
+
+// Unauthorized responds with 401 Unauthorized
+func Unauthorized(format string, args ...interface{}) error {
+	return newError(http.StatusUnauthorized, format, args...)
+}
+
+// Forbidden responds with 403 Forbidden
+func Forbidden(format string, args ...interface{}) error {
+	return newError(http.StatusForbidden, format, args...)
+}
+
+// NotFound responds with 404 Not Found
+func NotFound(format string, args ...interface{}) error {
+	return newError(http.StatusNotFound, format, args...)
+}
+
+// Conflict responds with 409 Conflict
+func Conflict(format string, args ...interface{}) error {
+	return newError(http.StatusConflict, format, args...)
+}
+
+// Unprocessable responds with 422 Unprocessable Entity
+func Unprocessable(format string, args ...interface{}) error {
+	return newError(http.StatusUnprocessableEntity, format, args...)
+}
+
+type statusCoder interface {
+	StatusCode() int
+}
+
+// fieldErrors are errors for individual fields, like validation errors
+type fieldErrors interface {
+	FieldErrors() map[string]string
+}
+
+// StatusCode returns the status code for the error. Errors set their status
+// code by implementing StatusCode() int. Other errors are 500 Internal Server
+// Error.
+func StatusCode(err error) int {
+	var coder statusCoder
+	if errors.As(err, &coder) {
+		if status := coder.StatusCode(); status >= 400 && status <= 599 {
+			return status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// ErrorJSON responds with the error's status code and message. Field errors
+// are listed by field.
+func ErrorJSON(err error) http.Handler {
+	res := Status(StatusCode(err))
+	var fields fieldErrors
+	if errors.As(err, &fields) {
+		return res.JSON(map[string]interface{}{"errors": fields.FieldErrors()})
+	}
+	return res.JSON(map[string]string{"error": err.Error()})
+}
+
+// ErrorHTML responds to failed form submissions by redirecting back to the
+// previous page, flashing the error. Field errors are flashed as "errors"
+// along with the submitted form values as "old". If the fallback is empty or
+// the error is a 401, 403, 404 or 413, it responds with the error instead.
+func ErrorHTML(err error, fallback string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := StatusCode(err)
+		switch {
+		case fallback == "",
+			status == http.StatusUnauthorized,
+			status == http.StatusForbidden,
+			status == http.StatusNotFound,
+			status == http.StatusRequestEntityTooLarge:
+			http.Error(w, err.Error(), status)
+			return
+		}
+		if sess, loadErr := session.Load(w, r); loadErr == nil {
+			flash := session.LoadFlash(sess)
+			var fields fieldErrors
+			if errors.As(err, &fields) {
+				flash.Set("errors", fields.FieldErrors())
+				flash.Set("old", oldInput(r))
+			} else {
+				flash.Set("error", err.Error())
+			}
+		}
+		Status(http.StatusSeeOther).RedirectBack(fallback).ServeHTTP(w, r)
+	})
+}
+
+// oldInput returns the submitted form values. Files aren't included.
+func oldInput(r *http.Request) map[string]string {
+	old := map[string]string{}
+	for key, values := range r.PostForm {
+		if len(values) > 0 {
+			old[key] = values[0]
+		}
+	}
+	return old
+}
@@ -0,0 +1,75 @@
+package response_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/livebud/bud/framework/controller/controllerrt/request"
+	"github.com/livebud/bud/framework/controller/controllerrt/response"
+	"github.com/livebud/bud/internal/is"
+)
+
+type fieldError map[string]string
+
+func (e fieldError) Error() string                  { return "invalid fields" }
+func (e fieldError) StatusCode() int                { return http.StatusUnprocessableEntity }
+func (e fieldError) FieldErrors() map[string]string { return e }
+
+func TestStatusCode(t *testing.T) {
+	is := is.New(t)
+	is.Equal(response.StatusCode(response.NotFound("post %d not found", 1)), 404)
+	is.Equal(response.StatusCode(response.Forbidden("no")), 403)
+	is.Equal(response.StatusCode(response.Conflict("taken")), 409)
+	is.Equal(response.StatusCode(fmt.Errorf("wrapped: %w", response.Unauthorized("no"))), 401)
+	is.Equal(response.StatusCode(errors.New("oops")), 500)
+	is.Equal(response.StatusCode(&response.Error{Status: 200}), 500)
+	is.Equal(response.StatusCode(fieldError{}), 422)
+}
+
+func TestErrorJSON(t *testing.T) {
+	is := is.New(t)
+	w := serve(response.ErrorJSON(response.NotFound("post %d not found", 1)), "application/json")
+	is.Equal(w.Code, 404)
+	is.Equal(w.Body.String(), `{"error":"post 1 not found"}`)
+	w = serve(response.ErrorJSON(fieldError{"name": "is required"}), "application/json")
+	is.Equal(w.Code, 422)
+	is.Equal(w.Body.String(), `{"errors":{"name":"is required"}}`)
+}
+
+func TestErrorHTMLNoFallback(t *testing.T) {
+	is := is.New(t)
+	w := serve(response.ErrorHTML(response.Conflict("taken"), ""), "text/html")
+	is.Equal(w.Code, 409)
+	is.Equal(w.Body.String(), "taken\n")
+}
+
+func TestErrorHTMLNotFound(t *testing.T) {
+	is := is.New(t)
+	w := serve(response.ErrorHTML(response.NotFound("missing"), "/posts"), "text/html")
+	is.Equal(w.Code, 404)
+}
+
+func TestErrorHTMLTooLarge(t *testing.T) {
+	is := is.New(t)
+	r := httptest.NewRequest("POST", "/posts", strings.NewReader(`{"title":"too large"}`))
+	r.Header.Set("Content-Type", "application/json")
+	var in struct{ Title string }
+	err := (&request.Limits{Body: 10}).Unmarshal(httptest.NewRecorder(), r, &in)
+	is.True(err != nil)
+	w := serve(response.ErrorHTML(err, "/posts/new"), "text/html")
+	is.Equal(w.Code, 413)
+}
+
+func TestErrorHTMLRedirect(t *testing.T) {
+	is := is.New(t)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/posts", nil)
+	r.Header.Set("Accept", "text/html")
+	response.ErrorHTML(response.Conflict("taken"), "/posts/new").ServeHTTP(w, r)
+	is.Equal(w.Code, 303)
+	is.Equal(w.Header().Get("Location"), "/posts/new")
+}
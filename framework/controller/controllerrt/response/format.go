@@ -0,0 +1,228 @@
+package response
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/livebud/bud/framework/controller/controllerrt/request"
+)
+
+// Encoder encodes values in a format
+type Encoder interface {
+	ContentType() string
+	Encode(w io.Writer, v interface{}) error
+}
+
+// Renderer is implemented by action results that can be rendered in formats
+// beyond HTML and JSON. Formats returns the names of registered encoders
+// (e.g. "csv" or "xml").
+type Renderer interface {
+	Formats() []string
+}
+
+var registry = struct {
+	sync.RWMutex
+	encoders map[string]Encoder
+}{
+	encoders: map[string]Encoder{
+		"csv":    csvEncoder{},
+		"xml":    xmlEncoder{},
+		"ndjson": ndjsonEncoder{},
+		"text":   textEncoder{},
+	},
+}
+
+// Register an encoder under a name. Registering an existing name replaces the
+// encoder.
+func Register(name string, encoder Encoder) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.encoders[name] = encoder
+}
+
+func lookup(name string) (Encoder, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	encoder, ok := registry.encoders[name]
+	return encoder, ok
+}
+
+// Formats returns a handler for each format the value supports, keyed by
+// content type. Values that don't implement Renderer don't support any
+// additional formats. Unregistered format names are ignored.
+func Formats(v interface{}) map[string]http.Handler {
+	renderer, ok := v.(Renderer)
+	if !ok {
+		return nil
+	}
+	handlers := map[string]http.Handler{}
+	for _, name := range renderer.Formats() {
+		encoder, ok := lookup(name)
+		if !ok {
+			continue
+		}
+		handlers[mediaType(encoder.ContentType())] = Encode(encoder, v)
+	}
+	return handlers
+}
+
+// Encode responds with the value encoded by the encoder
+func Encode(encoder Encoder, v interface{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := new(bytes.Buffer)
+		if err := encoder.Encode(buf, v); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", encoder.ContentType())
+		w.WriteHeader(http.StatusOK)
+		w.Write(buf.Bytes())
+	})
+}
+
+// negotiate picks the handler for the most preferred content type
+func (f *Format) negotiate(r *http.Request) (http.Handler, bool) {
+	if len(f.Formats) == 0 {
+		return nil, false
+	}
+	contentTypes := make([]string, 0, len(f.Formats))
+	for contentType := range f.Formats {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Strings(contentTypes)
+	contentType, err := request.Accepts(r).Negotiate(contentTypes...)
+	if err != nil || contentType == "" {
+		return nil, false
+	}
+	return f.Formats[contentType], true
+}
+
+func mediaType(contentType string) string {
+	return strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+}
+
+// csvEncoder encodes [][]string, a struct or a list of structs. Struct fields
+// become the header row.
+type csvEncoder struct{}
+
+func (csvEncoder) ContentType() string { return "text/csv; charset=utf-8" }
+
+func (csvEncoder) Encode(w io.Writer, v interface{}) error {
+	writer := csv.NewWriter(w)
+	if records, ok := v.([][]string); ok {
+		return writer.WriteAll(records)
+	}
+	rv := indirect(reflect.ValueOf(v))
+	var rows []reflect.Value
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			rows = append(rows, indirect(rv.Index(i)))
+		}
+	case reflect.Struct:
+		rows = append(rows, rv)
+	default:
+		return fmt.Errorf("response: unable to encode %T as csv", v)
+	}
+	var header []string
+	var fields []int
+	elem := indirectType(rv.Type())
+	if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+		elem = indirectType(elem.Elem())
+	}
+	if elem.Kind() != reflect.Struct {
+		return fmt.Errorf("response: unable to encode %T as csv", v)
+	}
+	for i := 0; i < elem.NumField(); i++ {
+		field := elem.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		name := request.FieldName(field, "json")
+		if name == "" {
+			continue
+		}
+		header = append(header, name)
+		fields = append(fields, i)
+	}
+	records := [][]string{header}
+	for _, row := range rows {
+		record := make([]string, len(fields))
+		if row.IsValid() {
+			for i, field := range fields {
+				// Nil pointers are empty cells
+				if value := indirect(row.Field(field)); value.IsValid() {
+					record[i] = fmt.Sprint(value)
+				}
+			}
+		}
+		records = append(records, record)
+	}
+	return writer.WriteAll(records)
+}
+
+func indirect(rv reflect.Value) reflect.Value {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return reflect.Value{}
+		}
+		rv = rv.Elem()
+	}
+	return rv
+}
+
+func indirectType(rt reflect.Type) reflect.Type {
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	return rt
+}
+
+type xmlEncoder struct{}
+
+func (xmlEncoder) ContentType() string { return "application/xml; charset=utf-8" }
+
+func (xmlEncoder) Encode(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(v)
+}
+
+// ndjsonEncoder encodes each item of a list as a line of JSON
+type ndjsonEncoder struct{}
+
+func (ndjsonEncoder) ContentType() string { return "application/x-ndjson" }
+
+func (ndjsonEncoder) Encode(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	rv := indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return enc.Encode(v)
+	}
+	for i := 0; i < rv.Len(); i++ {
+		if err := enc.Encode(rv.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// textEncoder writes strings and fmt.Stringers as they are
+type textEncoder struct{}
+
+func (textEncoder) ContentType() string { return "text/plain; charset=utf-8" }
+
+func (textEncoder) Encode(w io.Writer, v interface{}) error {
+	_, err := fmt.Fprint(w, v)
+	return err
+}
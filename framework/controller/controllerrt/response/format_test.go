@@ -0,0 +1,145 @@
+package response_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/livebud/bud/framework/controller/controllerrt/response"
+	"github.com/livebud/bud/internal/is"
+)
+
+type User struct {
+	ID     int    `json:"id" xml:"id"`
+	Name   string `json:"name" xml:"name"`
+	secret string
+}
+
+type Users []*User
+
+func (Users) Formats() []string {
+	return []string{"csv", "xml", "ndjson", "text", "yaml"}
+}
+
+func (users Users) String() string {
+	names := make([]string, len(users))
+	for i, user := range users {
+		names[i] = user.Name
+	}
+	return strings.Join(names, "\n")
+}
+
+var users = Users{{1, "alice", ""}, {2, "bob", ""}}
+
+func serve(handler http.Handler, accept string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Accept", accept)
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func format(v interface{}) *response.Format {
+	return &response.Format{
+		JSON:    response.JSON(v),
+		Formats: response.Formats(v),
+	}
+}
+
+func TestCSV(t *testing.T) {
+	is := is.New(t)
+	w := serve(format(users), "text/csv")
+	is.Equal(w.Code, 200)
+	is.Equal(w.Header().Get("Content-Type"), "text/csv; charset=utf-8")
+	is.Equal(w.Body.String(), "id,name\n1,alice\n2,bob\n")
+}
+
+type Post struct {
+	ID    int     `json:"id"`
+	Title *string `json:"title"`
+}
+
+type Posts []*Post
+
+func (Posts) Formats() []string {
+	return []string{"csv"}
+}
+
+func TestCSVNilPointer(t *testing.T) {
+	is := is.New(t)
+	title := "hello"
+	w := serve(format(Posts{{1, &title}, {2, nil}}), "text/csv")
+	is.Equal(w.Code, 200)
+	is.Equal(w.Body.String(), "id,title\n1,hello\n2,\n")
+}
+
+func TestXML(t *testing.T) {
+	is := is.New(t)
+	w := serve(format(users), "application/xml")
+	is.Equal(w.Code, 200)
+	is.Equal(w.Header().Get("Content-Type"), "application/xml; charset=utf-8")
+	is.Equal(w.Body.String(), `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+`<User><id>1</id><name>alice</name></User><User><id>2</id><name>bob</name></User>`)
+}
+
+func TestNDJSON(t *testing.T) {
+	is := is.New(t)
+	w := serve(format(users), "application/x-ndjson")
+	is.Equal(w.Code, 200)
+	is.Equal(w.Header().Get("Content-Type"), "application/x-ndjson")
+	is.Equal(w.Body.String(), "{\"id\":1,\"name\":\"alice\"}\n{\"id\":2,\"name\":\"bob\"}\n")
+}
+
+func TestText(t *testing.T) {
+	is := is.New(t)
+	w := serve(format(users), "text/plain")
+	is.Equal(w.Code, 200)
+	is.Equal(w.Header().Get("Content-Type"), "text/plain; charset=utf-8")
+	is.Equal(w.Body.String(), "alice\nbob")
+}
+
+func TestPreferJSON(t *testing.T) {
+	is := is.New(t)
+	w := serve(format(users), "text/csv, application/json")
+	is.Equal(w.Code, 200)
+	is.Equal(w.Header().Get("Content-Type"), "application/json")
+}
+
+func TestQuality(t *testing.T) {
+	is := is.New(t)
+	w := serve(format(users), "text/plain;q=0.5, text/csv")
+	is.Equal(w.Header().Get("Content-Type"), "text/csv; charset=utf-8")
+}
+
+func TestNotAcceptable(t *testing.T) {
+	is := is.New(t)
+	w := serve(format(users), "image/png")
+	is.Equal(w.Code, 406)
+	// Values without a renderer only support JSON
+	w = serve(format([]*User{{1, "alice", ""}}), "text/csv")
+	is.Equal(w.Code, 406)
+}
+
+type upper struct{}
+
+func (upper) ContentType() string { return "text/x-upper" }
+
+func (upper) Encode(w io.Writer, v interface{}) error {
+	_, err := io.WriteString(w, strings.ToUpper(v.(Shout).String()))
+	return err
+}
+
+type Shout string
+
+func (s Shout) String() string  { return string(s) }
+func (Shout) Formats() []string { return []string{"upper"} }
+
+func TestRegister(t *testing.T) {
+	is := is.New(t)
+	response.Register("upper", upper{})
+	w := serve(format(Shout("hello")), "text/x-upper")
+	is.Equal(w.Code, 200)
+	is.Equal(w.Header().Get("Content-Type"), "text/x-upper")
+	is.Equal(w.Body.String(), "HELLO")
+}
@@ -14,6 +14,8 @@ import (
 type Format struct {
 	HTML http.Handler
 	JSON http.Handler
+	// Formats are additional handlers keyed by content type
+	Formats map[string]http.Handler
 }
 
 var _ http.Handler = (*Format)(nil)
@@ -26,7 +28,11 @@ func (f *Format) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	case f.JSON != nil && acceptable.Accepts("application/json"):
 		f.JSON.ServeHTTP(w, r)
 	default:
-		w.WriteHeader(http.StatusUnsupportedMediaType)
+		if handler, ok := f.negotiate(r); ok {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusNotAcceptable)
 	}
 }
 
@@ -0,0 +1,301 @@
+package response
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+
+	"github.com/livebud/bud/framework/controller/controllerrt/request"
+	"github.com/livebud/bud/package/hot"
+)
+
+// Stream responds with each value as it's produced. The value may be:
+//
+//	io.Reader                          chunks are written as they're read
+//	<-chan T                           values are written until the channel closes
+//	func(yield func(T) bool)           values are written until the iterator returns
+//	func(yield func(T, error) bool)    same as above, but stops at the first error
+//
+// When the client asks for "text/event-stream", each value is sent as a
+// server-sent event. Values that are hot.Event are sent as they are and other
+// values are encoded as JSON. Otherwise values are written as newline-delimited
+// JSON. Streams stop when the client disconnects.
+func Stream(v interface{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Only send events when they're preferred, not when */* is accepted
+		contentType, _ := request.Accepts(r).Negotiate("application/x-ndjson", "text/event-stream")
+		isEvents := contentType == "text/event-stream"
+		var s streamer
+		if isEvents {
+			s = newEventStream(w)
+		} else {
+			s = newJSONStream(w, r)
+		}
+		ctx := r.Context()
+		if reader, ok := v.(io.Reader); ok {
+			if closer, ok := reader.(io.Closer); ok {
+				defer closer.Close()
+			}
+			if !isEvents {
+				streamReader(ctx, w, reader)
+				return
+			}
+			defer s.End()
+			streamLines(ctx, s, reader)
+			return
+		}
+		defer s.End()
+		rv := reflect.ValueOf(v)
+		switch rv.Kind() {
+		case reflect.Chan:
+			streamChan(ctx, s, rv)
+		case reflect.Func:
+			if err := streamFunc(ctx, s, rv); err != nil {
+				s.Error(err)
+			}
+		default:
+			s.Error(fmt.Errorf("response: unable to stream %T", v))
+		}
+	})
+}
+
+// streamer writes values and errors to the response
+type streamer interface {
+	Send(v interface{}) error
+	Error(err error)
+	// End writes the headers of empty streams
+	End()
+}
+
+func flush(w http.ResponseWriter) {
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func newEventStream(w http.ResponseWriter) *eventStream {
+	return &eventStream{w: w}
+}
+
+// eventStream sends values as server-sent events
+type eventStream struct {
+	w           http.ResponseWriter
+	wroteHeader bool
+}
+
+func (s *eventStream) writeHeader() {
+	if s.wroteHeader {
+		return
+	}
+	s.wroteHeader = true
+	header := s.w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	s.w.WriteHeader(http.StatusOK)
+}
+
+func (s *eventStream) End() {
+	s.writeHeader()
+}
+
+func (s *eventStream) write(event *hot.Event) error {
+	s.writeHeader()
+	if _, err := s.w.Write(event.Format().Bytes()); err != nil {
+		return err
+	}
+	flush(s.w)
+	return nil
+}
+
+func (s *eventStream) Send(v interface{}) error {
+	switch event := v.(type) {
+	case *hot.Event:
+		return s.write(event)
+	case hot.Event:
+		return s.write(&event)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.write(&hot.Event{Data: data})
+}
+
+// Error sends an "error" event. The status code has already been sent.
+func (s *eventStream) Error(err error) {
+	data, _ := json.Marshal(map[string]string{"error": err.Error()})
+	s.write(&hot.Event{Type: "error", Data: data})
+}
+
+func newJSONStream(w http.ResponseWriter, r *http.Request) *jsonStream {
+	return &jsonStream{w: w, r: r, enc: json.NewEncoder(w)}
+}
+
+// jsonStream writes values as newline-delimited JSON
+type jsonStream struct {
+	w           http.ResponseWriter
+	r           *http.Request
+	enc         *json.Encoder
+	wroteHeader bool
+}
+
+func (s *jsonStream) writeHeader() {
+	if s.wroteHeader {
+		return
+	}
+	s.wroteHeader = true
+	s.w.Header().Set("Content-Type", "application/x-ndjson")
+	s.w.WriteHeader(http.StatusOK)
+}
+
+func (s *jsonStream) End() {
+	s.writeHeader()
+}
+
+func (s *jsonStream) Send(v interface{}) error {
+	s.writeHeader()
+	if err := s.enc.Encode(v); err != nil {
+		return err
+	}
+	flush(s.w)
+	return nil
+}
+
+// Error responds with the error if nothing has been sent yet. Otherwise it's
+// written as the last line.
+func (s *jsonStream) Error(err error) {
+	if !s.wroteHeader {
+		ErrorJSON(err).ServeHTTP(s.w, s.r)
+		s.wroteHeader = true
+		return
+	}
+	s.enc.Encode(map[string]string{"error": err.Error()})
+	flush(s.w)
+}
+
+// streamReader copies the reader to the response, flushing after each read
+func streamReader(ctx context.Context, w http.ResponseWriter, r io.Reader) {
+	buf := make([]byte, 32*1024)
+	wroteHeader := false
+	for ctx.Err() == nil {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if !wroteHeader {
+				wroteHeader = true
+				if w.Header().Get("Content-Type") == "" {
+					w.Header().Set("Content-Type", http.DetectContentType(buf[:n]))
+				}
+				w.WriteHeader(http.StatusOK)
+			}
+			if _, err := w.Write(buf[:n]); err != nil {
+				return
+			}
+			flush(w)
+		}
+		if err == io.EOF {
+			if !wroteHeader {
+				w.WriteHeader(http.StatusOK)
+			}
+			return
+		} else if err != nil {
+			if !wroteHeader {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+	}
+}
+
+// streamLines sends each line of the reader as an event
+func streamLines(ctx context.Context, s streamer, r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for ctx.Err() == nil && scanner.Scan() {
+		if err := s.Send(&hot.Event{Data: scanner.Bytes()}); err != nil {
+			return
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		s.Error(err)
+	}
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// streamChan sends values until the channel is closed. Errors sent over the
+// channel are streamed as errors and stop the stream.
+func streamChan(ctx context.Context, s streamer, ch reflect.Value) {
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+		{Dir: reflect.SelectRecv, Chan: ch},
+	}
+	for {
+		chosen, value, ok := reflect.Select(cases)
+		if chosen == 1 && !ok {
+			return
+		}
+		if chosen == 0 {
+			// The client disconnected, don't leave the producer blocked on send
+			go drain(ch)
+			return
+		}
+		if err, isErr := value.Interface().(error); isErr && err != nil {
+			s.Error(err)
+			go drain(ch)
+			return
+		}
+		if err := s.Send(value.Interface()); err != nil {
+			go drain(ch)
+			return
+		}
+	}
+}
+
+// drain discards the values of a stream that stopped early until the producer
+// closes the channel. Producers that never close the channel should stop when
+// the request's context is canceled.
+func drain(ch reflect.Value) {
+	for {
+		if _, ok := ch.Recv(); !ok {
+			return
+		}
+	}
+}
+
+// streamFunc calls the iterator, sending each value until the iterator returns
+// or the client disconnects
+func streamFunc(ctx context.Context, s streamer, fn reflect.Value) error {
+	fnType := fn.Type()
+	if fnType.NumIn() != 1 || fnType.NumOut() != 0 {
+		return fmt.Errorf("response: unable to stream %s", fnType)
+	}
+	yieldType := fnType.In(0)
+	if yieldType.Kind() != reflect.Func ||
+		yieldType.NumOut() != 1 || yieldType.Out(0).Kind() != reflect.Bool ||
+		yieldType.NumIn() < 1 || yieldType.NumIn() > 2 ||
+		(yieldType.NumIn() == 2 && yieldType.In(1) != errorType) {
+		return fmt.Errorf("response: unable to stream %s", fnType)
+	}
+	stopped := false
+	yield := reflect.MakeFunc(yieldType, func(args []reflect.Value) []reflect.Value {
+		if stopped || ctx.Err() != nil {
+			stopped = true
+			return []reflect.Value{reflect.ValueOf(false)}
+		}
+		if len(args) == 2 && !args[1].IsNil() {
+			s.Error(args[1].Interface().(error))
+			stopped = true
+			return []reflect.Value{reflect.ValueOf(false)}
+		}
+		if err := s.Send(args[0].Interface()); err != nil {
+			stopped = true
+		}
+		return []reflect.Value{reflect.ValueOf(!stopped)}
+	})
+	fn.Call([]reflect.Value{yield})
+	return nil
+}
@@ -0,0 +1,160 @@
+package response_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/livebud/bud/framework/controller/controllerrt/response"
+	"github.com/livebud/bud/internal/is"
+	"github.com/livebud/bud/package/hot"
+)
+
+func userChan() <-chan *User {
+	ch := make(chan *User, len(users))
+	for _, user := range users {
+		ch <- user
+	}
+	close(ch)
+	return ch
+}
+
+func TestStreamChanJSON(t *testing.T) {
+	is := is.New(t)
+	w := serve(response.Stream(userChan()), "application/json")
+	is.Equal(w.Code, 200)
+	is.Equal(w.Header().Get("Content-Type"), "application/x-ndjson")
+	is.Equal(w.Body.String(), "{\"id\":1,\"name\":\"alice\"}\n{\"id\":2,\"name\":\"bob\"}\n")
+	is.True(w.Flushed)
+}
+
+func TestStreamChanEvents(t *testing.T) {
+	is := is.New(t)
+	w := serve(response.Stream(userChan()), "text/event-stream")
+	is.Equal(w.Code, 200)
+	is.Equal(w.Header().Get("Content-Type"), "text/event-stream")
+	is.Equal(w.Header().Get("Cache-Control"), "no-cache")
+	is.Equal(w.Body.String(), "data: {\"id\":1,\"name\":\"alice\"}\n\ndata: {\"id\":2,\"name\":\"bob\"}\n\n")
+}
+
+func TestStreamEvents(t *testing.T) {
+	is := is.New(t)
+	ch := make(chan *hot.Event, 1)
+	ch <- &hot.Event{ID: "1", Type: "message", Data: []byte("hi\nthere")}
+	close(ch)
+	w := serve(response.Stream(ch), "text/event-stream")
+	is.Equal(w.Body.String(), "id: 1\nevent: message\ndata: hi\ndata: there\n\n")
+}
+
+func TestStreamIterator(t *testing.T) {
+	is := is.New(t)
+	iterator := func(yield func(int) bool) {
+		for i := 1; i <= 3; i++ {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+	w := serve(response.Stream(iterator), "application/json")
+	is.Equal(w.Body.String(), "1\n2\n3\n")
+}
+
+func TestStreamIteratorError(t *testing.T) {
+	is := is.New(t)
+	iterator := func(yield func(int, error) bool) {
+		if !yield(1, nil) {
+			return
+		}
+		yield(0, errors.New("oops"))
+		yield(2, nil)
+	}
+	w := serve(response.Stream(iterator), "application/json")
+	is.Equal(w.Code, 200)
+	is.Equal(w.Body.String(), "1\n{\"error\":\"oops\"}\n")
+	w = serve(response.Stream(iterator), "text/event-stream")
+	is.Equal(w.Body.String(), "data: 1\n\nevent: error\ndata: {\"error\":\"oops\"}\n\n")
+}
+
+func TestStreamErrorFirst(t *testing.T) {
+	is := is.New(t)
+	iterator := func(yield func(int, error) bool) {
+		yield(0, response.NotFound("missing"))
+	}
+	w := serve(response.Stream(iterator), "application/json")
+	is.Equal(w.Code, 404)
+	is.Equal(w.Body.String(), `{"error":"missing"}`)
+}
+
+func TestStreamEmpty(t *testing.T) {
+	is := is.New(t)
+	ch := make(chan int)
+	close(ch)
+	w := serve(response.Stream(ch), "application/json")
+	is.Equal(w.Code, 200)
+	is.Equal(w.Header().Get("Content-Type"), "application/x-ndjson")
+	is.Equal(w.Body.String(), "")
+}
+
+func TestStreamReader(t *testing.T) {
+	is := is.New(t)
+	w := serve(response.Stream(strings.NewReader("hello\nworld")), "*/*")
+	is.Equal(w.Code, 200)
+	is.Equal(w.Header().Get("Content-Type"), "text/plain; charset=utf-8")
+	is.Equal(w.Body.String(), "hello\nworld")
+	w = serve(response.Stream(strings.NewReader("hello\nworld")), "text/event-stream")
+	is.Equal(w.Body.String(), "data: hello\n\ndata: world\n\n")
+}
+
+type readCloser struct {
+	io.Reader
+	closed bool
+}
+
+func (r *readCloser) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestStreamReaderClose(t *testing.T) {
+	is := is.New(t)
+	reader := &readCloser{Reader: strings.NewReader("hi")}
+	serve(response.Stream(reader), "*/*")
+	is.True(reader.closed)
+}
+
+func TestStreamDisconnect(t *testing.T) {
+	is := is.New(t)
+	ch := make(chan int)
+	ctx, cancel := context.WithCancel(context.Background())
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	r.Header.Set("Accept", "text/event-stream")
+	done := make(chan struct{})
+	go func() {
+		response.Stream(ch).ServeHTTP(w, r)
+		close(done)
+	}()
+	ch <- 1
+	cancel()
+	<-done
+	is.Equal(w.Body.String(), "data: 1\n\n")
+	// The producer isn't left blocked on the next send
+	select {
+	case ch <- 2:
+	case <-time.After(time.Second):
+		t.Fatal("producer blocked after the client disconnected")
+	}
+	close(ch)
+	is.Equal(w.Body.String(), "data: 1\n\n")
+}
+
+func TestStreamUnsupported(t *testing.T) {
+	is := is.New(t)
+	w := serve(response.Stream(func() {}), "application/json")
+	is.Equal(w.Code, 500)
+	is.Equal(w.Body.String(), `{"error":"response: unable to stream func()"}`)
+}
@@ -0,0 +1,84 @@
+package response
+
+import (
+	"net/http"
+)
+
+// Meta sets the status code and headers of an action's response. Embed it in
+// the result of an action:
+//
+//	type Created struct {
+//		response.Meta
+//		*Post
+//	}
+//
+//	func (c *Controller) Create() *Created {
+//		return &Created{response.Meta{Status: 201}, post}
+//	}
+type Meta struct {
+	Status int         `json:"-"`
+	Header http.Header `json:"-"`
+}
+
+// StatusCode of the response
+func (m Meta) StatusCode() int {
+	return m.Status
+}
+
+// Headers of the response
+func (m Meta) Headers() http.Header {
+	return m.Header
+}
+
+type headerer interface {
+	Headers() http.Header
+}
+
+// Apply the status code and headers of the value to the response. The status
+// code only replaces a successful 200 OK, so redirects and errors are kept.
+func Apply(v interface{}, handler http.Handler) http.Handler {
+	coder, hasStatus := v.(statusCoder)
+	headerer, hasHeaders := v.(headerer)
+	if !hasStatus && !hasHeaders {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hasHeaders {
+			header := w.Header()
+			for key, values := range headerer.Headers() {
+				for _, value := range values {
+					header.Add(key, value)
+				}
+			}
+		}
+		if hasStatus && coder.StatusCode() != 0 {
+			w = &statusWriter{ResponseWriter: w, status: coder.StatusCode()}
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// statusWriter replaces the 200 OK status code
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if status == http.StatusOK {
+		status = w.status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
@@ -0,0 +1,36 @@
+package response_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/livebud/bud/framework/controller/controllerrt/response"
+	"github.com/livebud/bud/internal/is"
+)
+
+type Created struct {
+	response.Meta
+	*User
+}
+
+func TestApplyMeta(t *testing.T) {
+	is := is.New(t)
+	result := &Created{response.Meta{Status: 201, Header: http.Header{"Location": {"/users/1"}}}, &User{ID: 1, Name: "alice"}}
+	w := serve(response.Apply(result, &response.Format{JSON: response.JSON(result)}), "application/json")
+	is.Equal(w.Code, 201)
+	is.Equal(w.Header().Get("Location"), "/users/1")
+	is.Equal(w.Body.String(), `{"id":1,"name":"alice"}`)
+}
+
+func TestApplyKeepsRedirect(t *testing.T) {
+	is := is.New(t)
+	result := &Created{response.Meta{Status: 201}, &User{ID: 1}}
+	w := serve(response.Apply(result, response.Status(302).Redirect("/users/1")), "text/html")
+	is.Equal(w.Code, 302)
+}
+
+func TestApplyPlain(t *testing.T) {
+	is := is.New(t)
+	handler := response.JSON(users)
+	is.Equal(response.Apply(users, handler), handler)
+}
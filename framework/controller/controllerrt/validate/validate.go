@@ -0,0 +1,328 @@
+package validate
+
+import (
+	"fmt"
+	"net/http"
+	"net/mail"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/livebud/bud/framework/controller/controllerrt/request"
+)
+
+// Errors maps field names to validation messages. Nested fields are joined
+// with dots and list items are indexed (e.g. params[0].name).
+type Errors map[string]string
+
+var _ error = Errors{}
+
+func (e Errors) Error() string {
+	keys := make([]string, 0, len(e))
+	for key := range e {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	messages := make([]string, len(keys))
+	for i, key := range keys {
+		messages[i] = key + " " + e[key]
+	}
+	return "validate: " + strings.Join(messages, ", ")
+}
+
+// StatusCode responds with 422 Unprocessable Entity
+func (e Errors) StatusCode() int {
+	return http.StatusUnprocessableEntity
+}
+
+// FieldErrors returns the message for each field
+func (e Errors) FieldErrors() map[string]string {
+	return e
+}
+
+// Struct validates v using the rules in the "validate" struct tags. Rules are
+// separated by commas:
+//
+//	required    must not be empty
+//	min=n       numbers must be at least n, strings and lists must have at least n items
+//	max=n       numbers must be at most n, strings and lists must have at most n items
+//	len=n       strings and lists must have exactly n items
+//	email       must be an email address
+//	oneof=a b   must be one of the space-separated values
+//	regex=expr  must match the regular expression. Must be the last rule.
+//
+// Struct returns Errors when fields are invalid and a regular error when the
+// rules themselves are invalid.
+func Struct(v interface{}) error {
+	errs := Errors{}
+	if err := validateValue(errs, "", reflect.ValueOf(v)); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateValue(errs Errors, path string, rv reflect.Value) error {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Struct:
+		return validateStruct(errs, path, rv)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			if err := validateValue(errs, fmt.Sprintf("%s[%d]", path, i), rv.Index(i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateStruct(errs Errors, path string, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		// Use the same names that request.Unmarshal decodes into
+		name := request.FieldName(field, "form", "json")
+		if name == "" {
+			continue
+		}
+		key := joinPath(path, name)
+		value := rv.Field(i)
+		if tag, ok := field.Tag.Lookup("validate"); ok {
+			rules, err := parseRules(tag)
+			if err != nil {
+				return fmt.Errorf("validate: invalid rules for field %q. %w", key, err)
+			}
+			if message := rules.check(value); message != "" {
+				errs[key] = message
+				continue
+			}
+		}
+		if err := validateValue(errs, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+type rule struct {
+	name  string
+	param string
+	// Parsed parameters
+	number float64
+	re     *regexp.Regexp
+}
+
+type rules struct {
+	required bool
+	list     []*rule
+}
+
+// Rules are parsed once per tag
+var cache sync.Map
+
+func parseRules(tag string) (*rules, error) {
+	if cached, ok := cache.Load(tag); ok {
+		return cached.(*rules), nil
+	}
+	rs := new(rules)
+	for rest := tag; rest != ""; {
+		var part string
+		// Regular expressions may contain commas, so they take the rest of the tag
+		if strings.HasPrefix(rest, "regex=") {
+			part, rest = rest, ""
+		} else if i := strings.Index(rest, ","); i >= 0 {
+			part, rest = rest[:i], rest[i+1:]
+		} else {
+			part, rest = rest, ""
+		}
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		r, err := parseRule(part)
+		if err != nil {
+			return nil, err
+		}
+		if r.name == "required" {
+			rs.required = true
+			continue
+		}
+		rs.list = append(rs.list, r)
+	}
+	cache.Store(tag, rs)
+	return rs, nil
+}
+
+func parseRule(part string) (*rule, error) {
+	r := new(rule)
+	r.name = part
+	if i := strings.Index(part, "="); i >= 0 {
+		r.name, r.param = part[:i], part[i+1:]
+	}
+	switch r.name {
+	case "required", "email":
+		if r.param != "" {
+			return nil, fmt.Errorf("%q doesn't take a parameter", r.name)
+		}
+	case "min", "max", "len":
+		n, err := strconv.ParseFloat(r.param, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q expects a number, got %q", r.name, r.param)
+		}
+		r.number = n
+	case "oneof":
+		if strings.TrimSpace(r.param) == "" {
+			return nil, fmt.Errorf("%q expects a list of values", r.name)
+		}
+	case "regex":
+		re, err := regexp.Compile(r.param)
+		if err != nil {
+			return nil, err
+		}
+		r.re = re
+	default:
+		return nil, fmt.Errorf("unknown rule %q", r.name)
+	}
+	return r, nil
+}
+
+// check the value, returning a message if it's invalid
+func (rs *rules) check(rv reflect.Value) string {
+	if isEmpty(rv) {
+		if rs.required {
+			return "is required"
+		}
+		// Optional empty values skip the remaining rules
+		return ""
+	}
+	rv = indirect(rv)
+	for _, r := range rs.list {
+		if message := r.check(rv); message != "" {
+			return message
+		}
+	}
+	return ""
+}
+
+func (r *rule) check(rv reflect.Value) string {
+	switch r.name {
+	case "min":
+		if n, ok := number(rv); ok {
+			if n < r.number {
+				return "must be at least " + r.param
+			}
+		} else if length(rv) < int(r.number) {
+			return "must have at least " + r.param + " " + unit(rv)
+		}
+	case "max":
+		if n, ok := number(rv); ok {
+			if n > r.number {
+				return "must be at most " + r.param
+			}
+		} else if length(rv) > int(r.number) {
+			return "must have at most " + r.param + " " + unit(rv)
+		}
+	case "len":
+		if length(rv) != int(r.number) {
+			return "must have exactly " + r.param + " " + unit(rv)
+		}
+	case "email":
+		if !isEmail(fmt.Sprint(rv.Interface())) {
+			return "must be a valid email address"
+		}
+	case "oneof":
+		value := fmt.Sprint(rv.Interface())
+		options := strings.Fields(r.param)
+		for _, option := range options {
+			if value == option {
+				return ""
+			}
+		}
+		return "must be one of " + strings.Join(options, ", ")
+	case "regex":
+		if !r.re.MatchString(fmt.Sprint(rv.Interface())) {
+			return "must match " + r.param
+		}
+	}
+	return ""
+}
+
+func indirect(rv reflect.Value) reflect.Value {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		rv = rv.Elem()
+	}
+	return rv
+}
+
+func isEmpty(rv reflect.Value) bool {
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return rv.IsNil()
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return rv.Len() == 0
+	default:
+		return rv.IsZero()
+	}
+}
+
+func number(rv reflect.Value) (float64, bool) {
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
+
+func length(rv reflect.Value) int {
+	switch rv.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(rv.String())
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return rv.Len()
+	default:
+		return len(fmt.Sprint(rv.Interface()))
+	}
+}
+
+func unit(rv reflect.Value) string {
+	if rv.Kind() == reflect.String {
+		return "characters"
+	}
+	return "items"
+}
+
+func isEmail(value string) bool {
+	address, err := mail.ParseAddress(value)
+	if err != nil {
+		return false
+	}
+	// Reject addresses with names (e.g. "Alice <alice@livebud.com>")
+	return address.Address == value
+}
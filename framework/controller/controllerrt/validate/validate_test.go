@@ -0,0 +1,141 @@
+package validate_test
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/livebud/bud/framework/controller/controllerrt/response"
+	"github.com/livebud/bud/framework/controller/controllerrt/validate"
+	"github.com/livebud/bud/internal/is"
+)
+
+func TestValid(t *testing.T) {
+	is := is.New(t)
+	type S struct {
+		Title  string   `json:"title" validate:"required,min=2,max=5"`
+		Email  string   `json:"email" validate:"email"`
+		Status string   `json:"status" validate:"oneof=draft published"`
+		Slug   string   `json:"slug" validate:"regex=^[a-z]+(-[a-z]+){0,3}$"`
+		Age    int      `json:"age" validate:"min=18,max=130"`
+		Tags   []string `json:"tags" validate:"max=2"`
+		Code   string   `json:"code" validate:"len=3"`
+	}
+	err := validate.Struct(&S{
+		Title:  "hi",
+		Email:  "alice@livebud.com",
+		Status: "draft",
+		Slug:   "hello-world",
+		Age:    20,
+		Tags:   []string{"a"},
+		Code:   "abc",
+	})
+	is.NoErr(err)
+}
+
+func TestOptional(t *testing.T) {
+	is := is.New(t)
+	type S struct {
+		Email  string `validate:"email"`
+		Status string `validate:"oneof=draft published"`
+		Age    *int   `validate:"min=18"`
+	}
+	is.NoErr(validate.Struct(S{}))
+}
+
+func TestInvalid(t *testing.T) {
+	is := is.New(t)
+	type S struct {
+		Title  string   `json:"title" validate:"required"`
+		Name   string   `json:"name" validate:"min=2"`
+		Bio    string   `json:"bio" validate:"max=3"`
+		Email  string   `json:"email" validate:"email"`
+		Status string   `json:"status" validate:"oneof=draft published"`
+		Slug   string   `json:"slug" validate:"regex=^[a-z]+$"`
+		Age    int      `json:"age" validate:"min=18"`
+		Tags   []string `json:"tags" validate:"max=1"`
+		Code   string   `json:"code" validate:"len=3"`
+	}
+	err := validate.Struct(&S{
+		Name:   "a",
+		Bio:    "hello",
+		Email:  "Alice <alice@livebud.com>",
+		Status: "archived",
+		Slug:   "Hello",
+		Age:    10,
+		Tags:   []string{"a", "b"},
+		Code:   "ab",
+	})
+	is.True(err != nil)
+	var errs validate.Errors
+	is.True(errors.As(err, &errs))
+	is.Equal(len(errs), 9)
+	is.Equal(errs["title"], "is required")
+	is.Equal(errs["name"], "must have at least 2 characters")
+	is.Equal(errs["bio"], "must have at most 3 characters")
+	is.Equal(errs["email"], "must be a valid email address")
+	is.Equal(errs["status"], "must be one of draft, published")
+	is.Equal(errs["slug"], "must match ^[a-z]+$")
+	is.Equal(errs["age"], "must be at least 18")
+	is.Equal(errs["tags"], "must have at most 1 items")
+	is.Equal(errs["code"], "must have exactly 3 characters")
+}
+
+func TestFirstMessage(t *testing.T) {
+	is := is.New(t)
+	type S struct {
+		Email string `json:"email" validate:"required,email,max=3"`
+	}
+	err := validate.Struct(&S{Email: "alice"})
+	is.Equal(err.Error(), "validate: email must be a valid email address")
+}
+
+func TestNested(t *testing.T) {
+	is := is.New(t)
+	type Param struct {
+		Name string `json:"name" validate:"required"`
+	}
+	type Op struct {
+		Name   string   `json:"name" validate:"required"`
+		Params []*Param `json:"params"`
+	}
+	in := struct {
+		Op *Op
+	}{
+		Op: &Op{Params: []*Param{{Name: "a"}, {}}},
+	}
+	err := validate.Struct(&in)
+	is.Equal(err, validate.Errors{
+		"Op.name":           "is required",
+		"Op.params[1].name": "is required",
+	})
+}
+
+func TestRegexComma(t *testing.T) {
+	is := is.New(t)
+	type S struct {
+		Zip string `json:"zip" validate:"required,regex=^[0-9]{5,6}$"`
+	}
+	is.NoErr(validate.Struct(&S{Zip: "12345"}))
+	is.Equal(validate.Struct(&S{Zip: "1234"}), validate.Errors{"zip": "must match ^[0-9]{5,6}$"})
+}
+
+func TestUnknownRule(t *testing.T) {
+	is := is.New(t)
+	type S struct {
+		Name string `json:"name" validate:"unique"`
+	}
+	err := validate.Struct(&S{})
+	is.True(err != nil)
+	is.Equal(err.Error(), `validate: invalid rules for field "name". unknown rule "unique"`)
+}
+
+func TestJSON(t *testing.T) {
+	is := is.New(t)
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/", nil)
+	response.ErrorJSON(validate.Errors{"title": "is required"}).ServeHTTP(w, r)
+	is.Equal(w.Code, 422)
+	is.Equal(w.Header().Get("Content-Type"), "application/json")
+	is.Equal(w.Body.String(), `{"errors":{"title":"is required"}}`)
+}
@@ -0,0 +1,282 @@
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/livebud/bud/framework/web/webrt"
+	"golang.org/x/net/websocket"
+)
+
+// PingInterval is how often idle connections are pinged to keep them alive.
+// Clients respond with a pong automatically.
+var PingInterval = 30 * time.Second
+
+// PongTimeout is how long reads wait for the client to answer a ping or send a
+// message. Each frame from the client, including pongs, extends the deadline.
+// This should be longer than PingInterval.
+var PongTimeout = 60 * time.Second
+
+// CloseTimeout is how long to wait for the client to acknowledge a close
+// before dropping the connection
+var CloseTimeout = 5 * time.Second
+
+// Close status codes
+// https://datatracker.ietf.org/doc/html/rfc6455#section-7.4.1
+const (
+	closeNormal        = 1000
+	closeGoingAway     = 1001
+	closeInternalError = 1011
+)
+
+// Conn is a WebSocket connection. Actions receive a connection by taking a
+// *websocket.Conn parameter:
+//
+//	func (c *Controller) Chat(ctx context.Context, conn *websocket.Conn) error {
+//		for {
+//			var msg Message
+//			if err := conn.Receive(&msg); err != nil {
+//				return err
+//			}
+//			...
+//		}
+//	}
+//
+// The connection is closed when the action returns. Returning io.EOF or nil
+// closes it normally.
+type Conn struct {
+	ws     *websocket.Conn
+	reader *deadlineReader
+	ctx    context.Context
+	cancel context.CancelFunc
+	once   sync.Once
+}
+
+// Context is canceled when the connection is closed or the server shuts down
+func (c *Conn) Context() context.Context {
+	return c.ctx
+}
+
+// Request that was upgraded to the connection
+func (c *Conn) Request() *http.Request {
+	return c.ws.Request()
+}
+
+// Receive the next message, decoding it from JSON into v. Receive returns
+// io.EOF once the connection has been closed.
+func (c *Conn) Receive(v interface{}) error {
+	return c.receive(websocket.JSON, v)
+}
+
+// ReceiveText receives the next message as text
+func (c *Conn) ReceiveText() (string, error) {
+	var msg string
+	if err := c.receive(websocket.Message, &msg); err != nil {
+		return "", err
+	}
+	return msg, nil
+}
+
+func (c *Conn) receive(codec websocket.Codec, v interface{}) error {
+	if err := codec.Receive(c.ws, v); err != nil {
+		// Reads fail after the connection is closed from our end
+		if c.ctx.Err() != nil {
+			return io.EOF
+		}
+		return err
+	}
+	return nil
+}
+
+// Send v as a JSON message
+func (c *Conn) Send(v interface{}) error {
+	return websocket.JSON.Send(c.ws, v)
+}
+
+// SendText sends a text message
+func (c *Conn) SendText(msg string) error {
+	return websocket.Message.Send(c.ws, msg)
+}
+
+// control frames are sent through a codec because they lock the connection's
+// writer
+func control(payloadType byte) websocket.Codec {
+	return websocket.Codec{
+		Marshal: func(v interface{}) ([]byte, byte, error) {
+			data, _ := v.([]byte)
+			return data, payloadType, nil
+		},
+	}
+}
+
+func (c *Conn) ping() error {
+	return control(websocket.PingFrame).Send(c.ws, []byte(nil))
+}
+
+// close sends a close frame with the status code, once
+func (c *Conn) close(status int) {
+	c.once.Do(func() {
+		c.cancel()
+		data := make([]byte, 2)
+		binary.BigEndian.PutUint16(data, uint16(status))
+		control(websocket.CloseFrame).Send(c.ws, data)
+		// Unblock readers that are waiting for the client
+		c.reader.stop(time.Now().Add(CloseTimeout))
+	})
+}
+
+// keepAlive pings the client until the connection is closed. When the server
+// shuts down, the connection is closed with 1001 Going Away.
+func (c *Conn) keepAlive(shutdown <-chan struct{}) {
+	ticker := time.NewTicker(PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.ctx.Done():
+			return
+		case <-shutdown:
+			c.close(closeGoingAway)
+			return
+		case <-ticker.C:
+			if err := c.ping(); err != nil {
+				c.cancel()
+				c.reader.stop(time.Now())
+				return
+			}
+		}
+	}
+}
+
+// Handler upgrades requests to WebSocket connections and calls fn with the
+// connection. Browsers must connect from the same host.
+func Handler(fn func(conn *Conn) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !isUpgrade(r) {
+			w.Header().Set("Upgrade", "websocket")
+			http.Error(w, "websocket: expected an upgrade request", http.StatusUpgradeRequired)
+			return
+		}
+		hijacker := &hijacker{ResponseWriter: w}
+		server := websocket.Server{
+			Handshake: checkOrigin,
+			Handler: func(ws *websocket.Conn) {
+				serve(ws, hijacker.reader, fn)
+			},
+		}
+		server.ServeHTTP(hijacker, r)
+	})
+}
+
+// hijacker reads the upgraded connection through a deadlineReader
+type hijacker struct {
+	http.ResponseWriter
+	reader *deadlineReader
+}
+
+func (h *hijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hj, ok := h.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("websocket: response writer can't be hijacked")
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		return nil, nil, err
+	}
+	h.reader = &deadlineReader{r: rw.Reader, conn: conn}
+	h.reader.extend()
+	return conn, bufio.NewReadWriter(bufio.NewReader(h.reader), rw.Writer), nil
+}
+
+// deadlineReader extends the connection's read deadline by PongTimeout each
+// time the client sends a frame. This includes the pongs that answer our pings,
+// so reads only time out when the client has gone away.
+type deadlineReader struct {
+	r       io.Reader
+	conn    net.Conn
+	mu      sync.Mutex
+	stopped bool
+}
+
+func (d *deadlineReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	if n > 0 {
+		d.extend()
+	}
+	return n, err
+}
+
+func (d *deadlineReader) extend() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.stopped {
+		return
+	}
+	d.conn.SetReadDeadline(time.Now().Add(PongTimeout))
+}
+
+// stop extending the read deadline and set it one last time
+func (d *deadlineReader) stop(deadline time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.stopped = true
+	d.conn.SetReadDeadline(deadline)
+}
+
+func isUpgrade(r *http.Request) bool {
+	return r.Method == http.MethodGet && headerContains(r.Header, "Connection", "upgrade") &&
+		headerContains(r.Header, "Upgrade", "websocket")
+}
+
+func headerContains(header http.Header, key, value string) bool {
+	for _, v := range header.Values(key) {
+		for _, token := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkOrigin allows clients without an Origin header (e.g. CLIs) and
+// browsers on the same host
+func checkOrigin(config *websocket.Config, r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return err
+	}
+	if u.Host != r.Host {
+		return fmt.Errorf("websocket: origin %q doesn't match host %q", origin, r.Host)
+	}
+	config.Origin = u
+	return nil
+}
+
+func serve(ws *websocket.Conn, reader *deadlineReader, fn func(conn *Conn) error) {
+	r := ws.Request()
+	shutdown, release := webrt.Hijack(r.Context())
+	defer release()
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	conn := &Conn{ws: ws, reader: reader, ctx: ctx, cancel: cancel}
+	go conn.keepAlive(shutdown)
+	if err := fn(conn); err != nil && !errors.Is(err, io.EOF) {
+		conn.close(closeInternalError)
+		return
+	}
+	conn.close(closeNormal)
+}
@@ -0,0 +1,208 @@
+package websocket_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/livebud/bud/framework/controller/controllerrt/websocket"
+	"github.com/livebud/bud/framework/web/webrt"
+	"github.com/livebud/bud/internal/is"
+	xwebsocket "golang.org/x/net/websocket"
+	"golang.org/x/sync/errgroup"
+)
+
+type Message struct {
+	Text string `json:"text"`
+}
+
+func echo(conn *websocket.Conn) error {
+	for {
+		var msg Message
+		if err := conn.Receive(&msg); err != nil {
+			return err
+		}
+		if err := conn.Send(&Message{"echo: " + msg.Text}); err != nil {
+			return err
+		}
+	}
+}
+
+// dial the server from the same origin, unless another origin is given
+func dial(url string, origin ...string) (*xwebsocket.Conn, error) {
+	if len(origin) == 0 {
+		origin = []string{url}
+	}
+	return xwebsocket.Dial(strings.Replace(url, "http://", "ws://", 1), "", origin[0])
+}
+
+func TestEcho(t *testing.T) {
+	is := is.New(t)
+	server := httptest.NewServer(websocket.Handler(echo))
+	defer server.Close()
+	ws, err := dial(server.URL)
+	is.NoErr(err)
+	defer ws.Close()
+	is.NoErr(xwebsocket.JSON.Send(ws, &Message{"hi"}))
+	var msg Message
+	is.NoErr(xwebsocket.JSON.Receive(ws, &msg))
+	is.Equal(msg.Text, "echo: hi")
+}
+
+func TestText(t *testing.T) {
+	is := is.New(t)
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) error {
+		msg, err := conn.ReceiveText()
+		if err != nil {
+			return err
+		}
+		return conn.SendText(strings.ToUpper(msg))
+	}))
+	defer server.Close()
+	ws, err := dial(server.URL)
+	is.NoErr(err)
+	defer ws.Close()
+	is.NoErr(xwebsocket.Message.Send(ws, "hi"))
+	var msg string
+	is.NoErr(xwebsocket.Message.Receive(ws, &msg))
+	is.Equal(msg, "HI")
+	// The connection closes after the handler returns
+	err = xwebsocket.Message.Receive(ws, &msg)
+	is.True(errors.Is(err, io.EOF))
+}
+
+func TestNotUpgrade(t *testing.T) {
+	is := is.New(t)
+	server := httptest.NewServer(websocket.Handler(echo))
+	defer server.Close()
+	res, err := http.Get(server.URL)
+	is.NoErr(err)
+	defer res.Body.Close()
+	is.Equal(res.StatusCode, http.StatusUpgradeRequired)
+	is.Equal(res.Header.Get("Upgrade"), "websocket")
+}
+
+func TestCrossOrigin(t *testing.T) {
+	is := is.New(t)
+	server := httptest.NewServer(websocket.Handler(echo))
+	defer server.Close()
+	_, err := dial(server.URL, "http://evil.com")
+	is.True(err != nil)
+}
+
+func TestPing(t *testing.T) {
+	is := is.New(t)
+	interval := websocket.PingInterval
+	websocket.PingInterval = 10 * time.Millisecond
+	defer func() { websocket.PingInterval = interval }()
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) error {
+		// Outlive a few pings
+		time.Sleep(50 * time.Millisecond)
+		if err := conn.SendText("done"); err != nil {
+			return err
+		}
+		_, err := conn.ReceiveText()
+		return err
+	}))
+	defer server.Close()
+	ws, err := dial(server.URL)
+	is.NoErr(err)
+	defer ws.Close()
+	// Pings are answered with pongs while reading
+	var msg string
+	is.NoErr(xwebsocket.Message.Receive(ws, &msg))
+	is.Equal(msg, "done")
+	is.NoErr(xwebsocket.Message.Send(ws, "bye"))
+}
+
+func TestPongTimeout(t *testing.T) {
+	is := is.New(t)
+	interval, timeout := websocket.PingInterval, websocket.PongTimeout
+	websocket.PingInterval = 10 * time.Millisecond
+	websocket.PongTimeout = 50 * time.Millisecond
+	defer func() { websocket.PingInterval, websocket.PongTimeout = interval, timeout }()
+	received := make(chan error, 1)
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) error {
+		_, err := conn.ReceiveText()
+		received <- err
+		return err
+	}))
+	defer server.Close()
+	ws, err := dial(server.URL)
+	is.NoErr(err)
+	defer ws.Close()
+	// The client doesn't read, so it doesn't answer the pings
+	select {
+	case err := <-received:
+		is.True(err != nil)
+	case <-time.After(time.Second):
+		t.Fatal("expected the read to time out")
+	}
+}
+
+func TestPongExtendsDeadline(t *testing.T) {
+	is := is.New(t)
+	interval, timeout := websocket.PingInterval, websocket.PongTimeout
+	websocket.PingInterval = 10 * time.Millisecond
+	websocket.PongTimeout = 50 * time.Millisecond
+	defer func() { websocket.PingInterval, websocket.PongTimeout = interval, timeout }()
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) error {
+		msg, err := conn.ReceiveText()
+		if err != nil {
+			return err
+		}
+		return conn.SendText(strings.ToUpper(msg))
+	}))
+	defer server.Close()
+	ws, err := dial(server.URL)
+	is.NoErr(err)
+	defer ws.Close()
+	// Reading answers the pings with pongs
+	reply := make(chan string, 1)
+	go func() {
+		var msg string
+		xwebsocket.Message.Receive(ws, &msg)
+		reply <- msg
+	}()
+	// Wait past the pong timeout before sending
+	time.Sleep(150 * time.Millisecond)
+	is.NoErr(xwebsocket.Message.Send(ws, "hi"))
+	is.Equal(<-reply, "HI")
+}
+
+func TestShutdown(t *testing.T) {
+	is := is.New(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	listener, err := webrt.Listen("APP", ":0")
+	is.NoErr(err)
+	canceled := make(chan struct{})
+	handler := websocket.Handler(func(conn *websocket.Conn) error {
+		err := echo(conn)
+		if conn.Context().Err() != nil {
+			close(canceled)
+		}
+		return err
+	})
+	eg := new(errgroup.Group)
+	eg.Go(func() error { return webrt.Serve(ctx, listener, handler) })
+	ws, err := dial("http://" + listener.Addr().String())
+	is.NoErr(err)
+	defer ws.Close()
+	is.NoErr(xwebsocket.JSON.Send(ws, &Message{"hi"}))
+	var msg Message
+	is.NoErr(xwebsocket.JSON.Receive(ws, &msg))
+	cancel()
+	// The server closes the connection when it shuts down
+	err = xwebsocket.JSON.Receive(ws, &msg)
+	is.True(errors.Is(err, io.EOF))
+	// Reply to the close, then the server finishes shutting down
+	ws.Close()
+	is.NoErr(eg.Wait())
+	<-canceled
+}
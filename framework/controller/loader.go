@@ -14,6 +14,8 @@ import (
 
 	"github.com/livebud/bud/internal/bail"
 	"github.com/livebud/bud/internal/imports"
+	"github.com/livebud/bud/internal/signature"
+	"github.com/livebud/bud/internal/slot"
 	"github.com/livebud/bud/package/di"
 	"github.com/livebud/bud/package/gomod"
 	"github.com/livebud/bud/package/parser"
@@ -57,13 +59,15 @@ type loader struct {
 func (l *loader) Load() (state *State, err error) {
 	defer l.Recover2(&err, "controller: unable to load")
 	state = new(State)
-	state.Controller = l.loadController("controller")
+	state.Controller = l.loadController("controller", nil)
 	state.Providers = l.providers.List()
 	state.Imports = l.imports.List()
 	return state, nil
 }
 
-func (l *loader) loadController(controllerPath string) *Controller {
+// loadController loads the controller and its nested controllers. Loaders are
+// the parent controllers' loaders, starting from the root.
+func (l *loader) loadController(controllerPath string, loaders []*Loader) *Controller {
 	des, err := fs.ReadDir(l.fsys, controllerPath)
 	if err != nil {
 		l.Bail(err)
@@ -76,34 +80,60 @@ func (l *loader) loadController(controllerPath string) *Controller {
 	controller.Pascal = gotext.Pascal(controller.Name)
 	// TODO: rename to route
 	controller.Route = l.loadControllerRoute(controller.Path)
-	shouldParse := false
-	for _, de := range des {
-		if !de.IsDir() && valid.ControllerFile(de.Name()) {
-			shouldParse = true
-			continue
+	stct := l.loadStruct(controllerPath, des)
+	// Load the controller's loader before the nested controllers, which may
+	// use it
+	nested := loaders
+	if stct != nil {
+		if loader := l.loadControllerLoader(controller, stct, loaders); loader != nil {
+			controller.Loader = loader
+			nested = append(loaders[:len(loaders):len(loaders)], loader)
 		}
+	}
+	for _, de := range des {
 		if de.IsDir() && valid.Dir(de.Name()) {
-			subController := l.loadController(path.Join(controllerPath, de.Name()))
+			subController := l.loadController(path.Join(controllerPath, de.Name()), nested)
 			if subController == nil {
 				continue
 			}
 			controller.Controllers = append(controller.Controllers, subController)
-			continue
 		}
 	}
-	if !shouldParse {
+	if stct == nil {
 		return controller
 	}
+	controller.Actions = l.loadActions(controller, stct, loaders)
+	return controller
+}
+
+// loadStruct parses the controller package and returns its Controller struct,
+// if any
+func (l *loader) loadStruct(controllerPath string, des []fs.DirEntry) *parser.Struct {
+	shouldParse := false
+	for _, de := range des {
+		if !de.IsDir() && valid.ControllerFile(de.Name()) {
+			shouldParse = true
+			break
+		}
+	}
+	if !shouldParse {
+		return nil
+	}
 	pkg, err := l.parser.Parse(controllerPath)
 	if err != nil {
 		l.Bail(err)
 	}
-	stct := pkg.Struct("Controller")
-	if stct == nil {
-		return controller
+	return pkg.Struct("Controller")
+}
+
+// loadControllerLoader loads the controller's Load method, if any
+func (l *loader) loadControllerLoader(controller *Controller, stct *parser.Struct, loaders []*Loader) *Loader {
+	for _, method := range stct.PublicMethods() {
+		if !l.isMiddleware(method) && signature.IsLoader(method) {
+			return l.loadLoader(controller, method, loaders)
+		}
 	}
-	controller.Actions = l.loadActions(controller, stct)
-	return controller
+	return nil
 }
 
 func (l *loader) loadControllerPath(controllerPath string) string {
@@ -135,17 +165,44 @@ func (l *loader) loadControllerRoute(controllerPath string) string {
 	return "/" + path.String()
 }
 
-func (l *loader) loadActions(controller *Controller, stct *parser.Struct) (actions []*Action) {
+func (l *loader) loadActions(controller *Controller, stct *parser.Struct, loaders []*Loader) (actions []*Action) {
 	var usesResponse bool
+	hooks := new(Hooks)
 	for _, method := range stct.PublicMethods() {
-		action := l.loadAction(controller, method)
-		if !action.HandlerFunc {
+		switch method.Name() {
+		case "Before":
+			hooks.Before = l.loadBefore(controller, method, loaders)
+		case "After":
+			hooks.After = l.loadAfter(controller, method, loaders)
+		case "Around":
+			hooks.Around = l.loadAround(controller, method, loaders)
+		}
+	}
+	for _, method := range stct.PublicMethods() {
+		if signature.IsHook(method) {
+			continue
+		}
+		if l.isMiddleware(method) {
+			controller.Middleware = l.loadMiddleware(controller, method)
+			continue
+		}
+		if method.Name() == "Prerender" {
+			controller.Prerender = l.loadPrerender(controller, method)
+			continue
+		}
+		if signature.IsLoader(method) {
+			usesResponse = usesResponse || len(controller.Loader.Params) > 0
+			continue
+		}
+		action := l.loadAction(controller, method, loaders, hooks)
+		if !action.HandlerFunc || action.Before != nil {
 			usesResponse = true
 		}
 		actions = append(actions, action)
 	}
-	// Add the imports if we have more than one action
-	if len(actions) > 0 {
+	// Add the imports if we have more than one action, middleware, loader or
+	// prerender
+	if len(actions) > 0 || controller.Middleware != nil || controller.Loader != nil || controller.Prerender != nil {
 		importPath, err := stct.File().Import()
 		if err != nil {
 			l.Bail(err)
@@ -159,8 +216,211 @@ func (l *loader) loadActions(controller *Controller, stct *parser.Struct) (actio
 	return actions
 }
 
-func (l *loader) loadAction(controller *Controller, method *parser.Function) *Action {
+// isMiddleware returns true for methods with the following signature:
+// Middleware(next http.Handler) http.Handler
+func (l *loader) isMiddleware(method *parser.Function) bool {
+	isMiddleware, err := signature.IsMiddleware(method)
+	if err != nil {
+		l.Bail(err)
+	}
+	return isMiddleware
+}
+
+func (l *loader) loadMiddleware(controller *Controller, method *parser.Function) *Middleware {
+	middleware := new(Middleware)
+	middleware.Provider = l.loadProvider(controller, method)
+	return middleware
+}
+
+// loadPrerender loads methods with the following signatures:
+// Prerender([ctx context.Context]) []prerender.Params
+// Prerender([ctx context.Context]) ([]prerender.Params, error)
+func (l *loader) loadPrerender(controller *Controller, method *parser.Function) *Prerender {
+	params, results := method.Params(), method.Results()
+	prerender := new(Prerender)
+	switch len(params) {
+	case 0:
+	case 1:
+		if params[0].Type().String() != "context.Context" {
+			l.Bail(fmt.Errorf("controller: %s Prerender can only take a context.Context", controller.Path))
+		}
+		prerender.Context = true
+	default:
+		l.Bail(fmt.Errorf("controller: %s Prerender can only take a context.Context", controller.Path))
+	}
+	switch len(results) {
+	case 1:
+	case 2:
+		if results[1].Type().String() != "error" {
+			l.Bail(fmt.Errorf("controller: %s Prerender must return ([]prerender.Params, error)", controller.Path))
+		}
+		prerender.Error = true
+	default:
+		l.Bail(fmt.Errorf("controller: %s Prerender must return []prerender.Params", controller.Path))
+	}
+	isParams, err := parser.IsImportType(results[0].Type(), "github.com/livebud/bud/package/prerender", "Params")
+	if err != nil {
+		l.Bail(err)
+	}
+	if !isParams || !strings.HasPrefix(results[0].Type().String(), "[]") {
+		l.Bail(fmt.Errorf("controller: %s Prerender must return []prerender.Params", controller.Path))
+	}
+	prerender.Provider = l.loadProvider(controller, method)
+	l.imports.Add("context")
+	l.imports.AddNamed("prerender", "github.com/livebud/bud/package/prerender")
+	if prerender.Provider.Variable("net/http.*Request") != "" || prerender.Provider.Variable("net/http.ResponseWriter") != "" {
+		l.imports.Add("net/http/httptest")
+	}
+	return prerender
+}
+
+func (l *loader) loadLoader(controller *Controller, method *parser.Function, parents []*Loader) *Loader {
+	results := method.Results()
+	def, err := results[0].Definition()
+	if err != nil {
+		l.Bail(fmt.Errorf("controller: unable to load result definition for %s . %w", results[0].Type(), err))
+	}
+	loader := new(Loader)
+	loader.Pascal = controller.Pascal
+	loader.Variable = gotext.Camel(controller.Name + " resource")
+	loader.Type = l.loadType(results[0].Type(), def)
+	loader.Error = len(results) == 2
+	loader.Params = l.loadActionParams(method.Params(), parents)
+	for _, param := range loader.Params {
+		if param.IsSocket {
+			l.Bail(fmt.Errorf("controller: %s Load can't take a websocket", controller.Path))
+		}
+		if param.Loader != nil {
+			loader.Parents = append(loader.Parents, param.Loader)
+		}
+	}
+	loader.Input = l.loadActionInput(loader.Params)
+	loader.Provider = l.loadProvider(controller, method)
+	return loader
+}
+
+// loadBefore loads the hook with one of the following signatures:
+// Before(...)
+// Before(...) error
+// Before(...) http.Handler
+func (l *loader) loadBefore(controller *Controller, method *parser.Function, loaders []*Loader) *Hook {
+	hook := l.loadHook(controller, method, method.Params(), loaders)
+	results := method.Results()
+	if len(results) > 1 {
+		l.Bail(fmt.Errorf("controller: %s Before may only return an error or an http.Handler", controller.Path))
+	}
+	if len(results) == 0 {
+		return hook
+	}
+	isHandler, err := parser.IsImportType(results[0].Type(), "net/http", "Handler")
+	if err != nil {
+		l.Bail(err)
+	}
+	switch {
+	case isHandler:
+		hook.Handler = true
+	case results[0].Type().String() == "error":
+		hook.Error = true
+	default:
+		l.Bail(fmt.Errorf("controller: %s Before may only return an error or an http.Handler", controller.Path))
+	}
+	return hook
+}
+
+// loadAfter loads the hook with one of the following signatures:
+// After(...)
+// After(...) error
+func (l *loader) loadAfter(controller *Controller, method *parser.Function, loaders []*Loader) *Hook {
+	hook := l.loadHook(controller, method, method.Params(), loaders)
+	hook.Error = l.loadHookError(controller, method)
+	return hook
+}
+
+// loadAround loads the hook with one of the following signatures:
+// Around(..., next func() error)
+// Around(..., next func() error) error
+func (l *loader) loadAround(controller *Controller, method *parser.Function, loaders []*Loader) *Hook {
+	params := method.Params()
+	if len(params) == 0 || params[len(params)-1].Type().String() != "func() error" {
+		l.Bail(fmt.Errorf("controller: %s Around must take next func() error as its last param", controller.Path))
+	}
+	hook := l.loadHook(controller, method, params[:len(params)-1], loaders)
+	hook.Error = l.loadHookError(controller, method)
+	return hook
+}
+
+// loadHookError returns true if the hook returns an error and bails if the
+// hook returns anything else
+func (l *loader) loadHookError(controller *Controller, method *parser.Function) bool {
+	results := method.Results()
+	switch {
+	case len(results) == 0:
+		return false
+	case len(results) == 1 && results[0].Type().String() == "error":
+		return true
+	default:
+		l.Bail(fmt.Errorf("controller: %s %s may only return an error", controller.Path, method.Name()))
+		return false
+	}
+}
+
+// loadHook loads the params of a hook. Like Load, a hook's params are
+// unmarshaled from the route's slots and the query string, while parent
+// resources are injected.
+func (l *loader) loadHook(controller *Controller, method *parser.Function, params []*parser.Param, loaders []*Loader) *Hook {
+	hook := new(Hook)
+	hook.Name = method.Name()
+	hook.Params = l.loadParams(params, loaders)
+	hook.Variable = gotext.Camel(hook.Name + " in")
+	hasInput := false
+	for _, param := range hook.Params {
+		switch {
+		case param.IsSocket:
+			l.Bail(fmt.Errorf("controller: %s %s can't take a websocket", controller.Path, hook.Name))
+		case param.Variable == "in":
+			param.Variable = hook.Variable
+			hasInput = true
+		case strings.HasPrefix(param.Variable, "in."):
+			param.Variable = hook.Variable + "." + param.Pascal
+			hasInput = true
+		}
+	}
+	// Only unmarshal the request when the hook takes some input
+	if hasInput {
+		hook.Input = l.loadActionInput(hook.Params)
+		l.imports.Add("github.com/livebud/bud/framework/controller/controllerrt/request")
+		l.imports.Add("github.com/livebud/bud/framework/controller/controllerrt/validate")
+	}
+	return hook
+}
+
+// loadActionLoaders returns the loaders of the resources injected into the
+// params, along with the loaders they depend on, starting from the root
+func (l *loader) loadActionLoaders(params []*ActionParam, loaders []*Loader) (used []*Loader) {
+	needs := map[*Loader]bool{}
+	var need func(loader *Loader)
+	need = func(loader *Loader) {
+		needs[loader] = true
+		for _, parent := range loader.Parents {
+			need(parent)
+		}
+	}
+	for _, param := range params {
+		if param.Loader != nil {
+			need(param.Loader)
+		}
+	}
+	for _, loader := range loaders {
+		if needs[loader] {
+			used = append(used, loader)
+		}
+	}
+	return used
+}
+
+func (l *loader) loadAction(controller *Controller, method *parser.Function, loaders []*Loader, hooks *Hooks) *Action {
 	action := new(Action)
+	action.Function = method
 	action.Name = method.Name()
 	action.Pascal = gotext.Pascal(action.Name)
 	action.Camel = gotext.Camel(action.Name)
@@ -173,10 +433,19 @@ func (l *loader) loadAction(controller *Controller, method *parser.Function) *Ac
 	results := method.Results()
 	action.HandlerFunc = l.isHandlerFunc(params, results)
 	if !action.HandlerFunc {
-		action.Params = l.loadActionParams(params)
+		action.Params = l.loadActionParams(params, loaders)
 		action.Input = l.loadActionInput(action.Params)
 		action.Results = l.loadActionResults(results)
+		action.Route = l.loadActionConstraints(action.Route, action.Params, loaders)
+		action.Socket = l.loadSocket(action)
 	}
+	// Handlers and sockets write their own responses, so they only run Before
+	action.Before = hooks.Before
+	if !action.HandlerFunc && !action.Socket {
+		action.After = hooks.After
+		action.Around = hooks.Around
+	}
+	action.Loaders = l.loadActionLoaders(action.allParams(), loaders)
 	action.RespondJSON = len(action.Results) > 0
 	action.RespondHTML = l.loadRespondHTML(action.Results)
 	action.Provider = l.loadProvider(controller, method)
@@ -184,6 +453,32 @@ func (l *loader) loadAction(controller *Controller, method *parser.Function) *Ac
 	return action
 }
 
+// loadSocket checks if the action serves WebSocket connections. WebSocket
+// actions are upgraded from GET requests and may only return an error.
+func (l *loader) loadSocket(action *Action) bool {
+	hasSocket := false
+	for _, param := range action.Params {
+		if param.IsSocket {
+			hasSocket = true
+		}
+	}
+	if !hasSocket {
+		return false
+	}
+	if len(action.Results) > 1 || (len(action.Results) == 1 && !action.Results[0].IsError) {
+		l.Bail(fmt.Errorf("controller: websocket action %q may only return an error", action.Name))
+	}
+	action.Method = http.MethodGet
+	// Contexts are canceled when the connection closes, not after the upgrade
+	for _, param := range action.Params {
+		if param.IsContext() {
+			param.Variable = "conn.Context()"
+		}
+	}
+	l.imports.Add("github.com/livebud/bud/framework/controller/controllerrt/websocket")
+	return true
+}
+
 func (l *loader) loadActionKey(controllerPath, actionName string) string {
 	return path.Join(controllerPath, text.Lower(text.Snake(actionName)))
 }
@@ -232,6 +527,22 @@ func (l *loader) loadActionRoute(controllerRoute, actionName string) string {
 	}
 }
 
+// Constrain the route's slots by the types of the matching action params, so
+// the router skips requests that wouldn't unmarshal (e.g. /users/:id|int).
+// Parent slots are also constrained by the params of the parent loaders.
+func (l *loader) loadActionConstraints(route string, params []*ActionParam, loaders []*Loader) string {
+	for _, loader := range loaders {
+		params = append(params[:len(params):len(params)], loader.Slots()...)
+	}
+	dataTypes := map[string]string{}
+	for _, param := range params {
+		if _, ok := dataTypes[param.Snake]; !ok {
+			dataTypes[param.Snake] = param.Type
+		}
+	}
+	return slot.Constrain(route, dataTypes)
+}
+
 // Method is the HTTP method for this controller
 func (l *loader) loadActionMethod(actionName string) string {
 	switch actionName {
@@ -258,7 +569,9 @@ func (l *loader) loadView(controllerKey, actionKey, actionRoute string) *View {
 	for _, de := range des {
 		name := de.Name()
 		ext := path.Ext(name)
-		if ext != ".svelte" {
+		switch ext {
+		case ".svelte", ".jsx", ".tsx", ".vue":
+		default:
 			continue
 		}
 		base := strings.TrimSuffix(path.Base(name), ext)
@@ -267,6 +580,7 @@ func (l *loader) loadView(controllerKey, actionKey, actionRoute string) *View {
 			continue
 		}
 		l.imports.Add(l.module.Import("bud/internal/app/view"))
+		l.imports.Add("github.com/livebud/bud/framework/view/viewrt")
 		return &View{
 			Route: actionRoute,
 		}
@@ -274,18 +588,30 @@ func (l *loader) loadView(controllerKey, actionKey, actionRoute string) *View {
 	return nil
 }
 
-func (l *loader) loadActionParams(params []*parser.Param) (inputs []*ActionParam) {
-	numParams := len(params)
-	for nth, param := range params {
-		inputs = append(inputs, l.loadActionParam(param, nth, numParams))
-	}
+func (l *loader) loadActionParams(params []*parser.Param, loaders []*Loader) (inputs []*ActionParam) {
+	inputs = l.loadParams(params, loaders)
 	if len(inputs) > 0 {
 		l.imports.Add("github.com/livebud/bud/framework/controller/controllerrt/request")
+		l.imports.Add("github.com/livebud/bud/framework/controller/controllerrt/validate")
+	}
+	return inputs
+}
+
+func (l *loader) loadParams(params []*parser.Param, loaders []*Loader) (inputs []*ActionParam) {
+	// Parent resources are injected, so they don't count towards the input
+	numParams := 0
+	for _, param := range params {
+		if l.findLoader(param, loaders) == nil {
+			numParams++
+		}
+	}
+	for nth, param := range params {
+		inputs = append(inputs, l.loadActionParam(param, nth, numParams, loaders))
 	}
 	return inputs
 }
 
-func (l *loader) loadActionParam(param *parser.Param, nth, numParams int) *ActionParam {
+func (l *loader) loadActionParam(param *parser.Param, nth, numParams int, loaders []*Loader) *ActionParam {
 	dec, err := param.Definition()
 	if err != nil {
 		l.Bail(fmt.Errorf("controller: unable to find param definition for %s. %w", param.Type(), err))
@@ -297,9 +623,22 @@ func (l *loader) loadActionParam(param *parser.Param, nth, numParams int) *Actio
 	ap.Type = l.loadType(param.Type(), dec)
 	ap.Tag = fmt.Sprintf("`json:\"%[1]s\"`", tagValue(ap.Snake))
 	ap.Kind = string(dec.Kind())
+	if loader := l.findLoader(param, loaders); loader != nil {
+		ap.Loader = loader
+		ap.Variable = loader.Variable
+		return ap
+	}
+	isFile, err := parser.IsImportType(param.Type(), "github.com/livebud/bud/framework/controller/controllerrt/request", "File")
+	if err != nil {
+		l.Bail(err)
+	}
+	ap.IsSocket = l.isSocket(param)
 	switch {
-	// Single struct input
-	case numParams == 1 && dec.Kind() == parser.KindStruct:
+	// WebSocket connections are passed in after upgrading
+	case ap.IsSocket:
+		ap.Variable = "conn"
+	// Single struct input. Uploaded files are bound by their parameter name.
+	case numParams == 1 && dec.Kind() == parser.KindStruct && !isFile:
 		ap.Variable = "in"
 	// Handle context.Context
 	case ap.IsContext():
@@ -310,6 +649,34 @@ func (l *loader) loadActionParam(param *parser.Param, nth, numParams int) *Actio
 	return ap
 }
 
+// findLoader finds the parent loader of the param's type, if any
+func (l *loader) findLoader(param *parser.Param, loaders []*Loader) *Loader {
+	if len(loaders) == 0 {
+		return nil
+	}
+	dec, err := param.Definition()
+	if err != nil {
+		l.Bail(fmt.Errorf("controller: unable to find param definition for %s. %w", param.Type(), err))
+	}
+	dataType := l.loadType(param.Type(), dec)
+	// Search from the closest parent
+	for i := len(loaders) - 1; i >= 0; i-- {
+		if loaders[i].Type == dataType {
+			return loaders[i]
+		}
+	}
+	return nil
+}
+
+// isSocket returns true for *websocket.Conn params
+func (l *loader) isSocket(param *parser.Param) bool {
+	isSocket, err := parser.IsImportType(param.Type(), "github.com/livebud/bud/framework/controller/controllerrt/websocket", "Conn")
+	if err != nil {
+		l.Bail(err)
+	}
+	return isSocket
+}
+
 func (l *loader) loadActionParamName(param *parser.Param, nth int) string {
 	name := param.Name()
 	if name != "" {
@@ -341,17 +708,23 @@ func (l *loader) loadType(dt parser.Type, dec parser.Declaration) string {
 }
 
 func (l *loader) loadActionInput(params []*ActionParam) string {
-	if len(params) == 1 && params[0].Kind == string(parser.KindStruct) {
-		return params[0].Type
+	var inputs []*ActionParam
+	for _, param := range params {
+		if param.Loader == nil {
+			inputs = append(inputs, param)
+		}
+	}
+	if len(inputs) == 1 && inputs[0].Variable == "in" {
+		return inputs[0].Type
 	}
-	return l.loadActionInputStruct(params)
+	return l.loadActionInputStruct(inputs)
 }
 
 func (l *loader) loadActionInputStruct(params []*ActionParam) string {
 	b := new(strings.Builder)
 	b.WriteString("struct {")
 	for _, param := range params {
-		if param.IsContext() {
+		if param.IsContext() || param.IsSocket {
 			continue
 		}
 		b.WriteString("\n")
@@ -373,6 +746,17 @@ func (l *loader) loadActionResults(results []*parser.Result) (outputs []*ActionR
 }
 
 func (l *loader) loadActionResult(order int, result *parser.Result) *ActionResult {
+	if l.isStream(result.Type()) {
+		output := new(ActionResult)
+		output.Name = l.loadActionResultName(order, result)
+		output.Pascal = gotext.Pascal(output.Name)
+		output.Named = result.Named()
+		output.Snake = gotext.Snake(output.Name)
+		output.Type = parser.Unqualify(result.Type()).String()
+		output.Variable = l.loadActionResultVariable(order, result)
+		output.IsStream = true
+		return output
+	}
 	def, err := result.Definition()
 	if err != nil {
 		l.Bail(fmt.Errorf("controller: unable to load result definition for %s . %w", result.Type(), err))
@@ -391,6 +775,52 @@ func (l *loader) loadActionResult(order int, result *parser.Result) *ActionResul
 	return output
 }
 
+// isStream returns true for results that are streamed as they're produced:
+// readers, channels and iterators like func(yield func(T) bool)
+func (l *loader) isStream(dt parser.Type) bool {
+	switch t := dt.(type) {
+	case *parser.ChanType:
+		return true
+	case *parser.FuncType:
+		return isIterator(t)
+	}
+	for _, name := range []string{"Reader", "ReadCloser"} {
+		isReader, err := parser.IsImportType(dt, "io", name)
+		if err != nil {
+			l.Bail(err)
+		}
+		if isReader {
+			return true
+		}
+	}
+	return false
+}
+
+// isIterator returns true for func(yield func(T) bool) and
+// func(yield func(T, error) bool). This matches what response.Stream accepts.
+func isIterator(fn *parser.FuncType) bool {
+	params := fn.Params()
+	if len(params) != 1 || len(fn.Results()) != 0 {
+		return false
+	}
+	yield, ok := params[0].(*parser.FuncType)
+	if !ok {
+		return false
+	}
+	in, out := yield.Params(), yield.Results()
+	if len(out) != 1 || out[0].String() != "bool" {
+		return false
+	}
+	switch len(in) {
+	case 1:
+		return true
+	case 2:
+		return in[1].String() == "error"
+	default:
+		return false
+	}
+}
+
 func (l *loader) loadActionResultName(order int, result *parser.Result) string {
 	name := result.Name()
 	if name != "" {
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/livebud/bud/internal/imports"
+	"github.com/livebud/bud/internal/slot"
 	"github.com/livebud/bud/package/di"
 	"github.com/livebud/bud/package/parser"
 	"github.com/matthewmueller/gotext"
@@ -24,6 +25,9 @@ type Controller struct {
 	JSON        string
 	Path        string // Path to controller without action dir
 	Route       string
+	Middleware  *Middleware
+	Loader      *Loader
+	Prerender   *Prerender
 	Actions     []*Action
 	Controllers []*Controller
 }
@@ -33,6 +37,69 @@ func (c *Controller) Last() Name {
 	return Name(names[len(names)-1])
 }
 
+// Middleware is declared by a controller with a
+// Middleware(next http.Handler) http.Handler method. It runs before each of the
+// controller's actions and the actions of its nested controllers.
+type Middleware struct {
+	Provider *di.Provider
+}
+
+// Prerender is declared by a controller with a Prerender method that lists the
+// params of its pages to prerender (e.g. Prerender(ctx context.Context)
+// ([]prerender.Params, error)).
+type Prerender struct {
+	Provider *di.Provider
+	Context  bool // Prerender takes a context
+	Error    bool // Prerender returns an error
+}
+
+// Loader is declared by a controller with a Load method that returns its
+// resource (e.g. Load(postID int) (*Post, error)). The resource is loaded once
+// per request and injected into the actions of the nested controllers that
+// take it as a param. Load's params are unmarshaled from the route's slots.
+type Loader struct {
+	Pascal   string
+	Variable string // Variable holding the resource
+	Type     string // Type of the resource
+	Provider *di.Provider
+	Params   []*ActionParam
+	Input    string
+	Parents  []*Loader // Loaders of the parent resources passed into Load
+	Error    bool      // Load returns an error
+}
+
+// Slots are the params unmarshaled from the route
+func (l *Loader) Slots() (slots []*ActionParam) {
+	for _, param := range l.Params {
+		if param.IsContext() || param.Loader != nil {
+			continue
+		}
+		slots = append(slots, param)
+	}
+	return slots
+}
+
+// Hooks are declared by a controller with Before, After or Around methods
+type Hooks struct {
+	Before *Hook
+	After  *Hook
+	Around *Hook
+}
+
+// Hook runs with each of the controller's actions on the same controller as
+// the action. Before runs before the action and may respond instead of the
+// action by returning an error or a non-nil http.Handler. After runs once the
+// action succeeds and Around runs the action when it calls next. Errors from
+// After and Around replace the action's response.
+type Hook struct {
+	Name     string
+	Params   []*ActionParam
+	Input    string // Input unmarshaled from the request, if any
+	Variable string // Variable holding the input
+	Error    bool   // Hook returns an error
+	Handler  bool   // Hook returns an http.Handler
+}
+
 type Name string
 
 func (n Name) Pascal() string {
@@ -50,9 +117,15 @@ type Action struct {
 	Route       string // Route to this action
 	Redirect    string
 	Method      string
+	Function    *parser.Function // Method on the controller
 	Provider    *di.Provider
 	Params      []*ActionParam
+	Loaders     []*Loader // Loaders of the parent resources, starting from the root
+	Before      *Hook
+	After       *Hook
+	Around      *Hook
 	HandlerFunc bool
+	Socket      bool
 	Input       string
 	Results     ActionResults
 	RespondJSON bool
@@ -60,6 +133,43 @@ type Action struct {
 	PropsKey    string
 }
 
+// Path to the action without the slot constraints of its route
+// e.g. /users/:id|int => /users/:id
+func (a *Action) Path() string {
+	return slot.Strip(a.Route)
+}
+
+// Hooks that run with the action
+func (a *Action) Hooks() (hooks []*Hook) {
+	for _, hook := range []*Hook{a.Before, a.After, a.Around} {
+		if hook != nil {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks
+}
+
+// allParams returns the params of the action along with its hooks' params
+func (a *Action) allParams() (params []*ActionParam) {
+	params = append(params, a.Params...)
+	for _, hook := range a.Hooks() {
+		params = append(params, hook.Params...)
+	}
+	return params
+}
+
+// ErrorFormat pairs the action with the variable holding an error, so the
+// error response can be generated by the "error" template
+func (a *Action) ErrorFormat(variable string) *ErrorFormat {
+	return &ErrorFormat{a, variable}
+}
+
+// ErrorFormat struct
+type ErrorFormat struct {
+	Action   *Action
+	Variable string
+}
+
 // View struct
 type View struct {
 	Route string
@@ -74,6 +184,8 @@ type ActionParam struct {
 	Kind     string
 	Variable string
 	Tag      string
+	IsSocket bool
+	Loader   *Loader // Loader of the parent resource injected into the param
 }
 
 func (ap *ActionParam) IsContext() bool {
@@ -136,7 +248,8 @@ func (results ActionResults) Result() string {
 	return ""
 }
 
-func (results ActionResults) propsKey() string {
+// PropsKey is the key of the results in the view's props
+func (results ActionResults) PropsKey() string {
 	for _, result := range results {
 		if result.IsError {
 			continue
@@ -160,7 +273,7 @@ func isList(dataType string) bool {
 }
 
 func (results ActionResults) ViewResult() string {
-	propsKey := results.propsKey()
+	propsKey := results.PropsKey()
 	out := new(strings.Builder)
 	out.WriteString(`map[string]interface{}{`)
 	if propsKey != "" {
@@ -200,6 +313,16 @@ func (results ActionResults) Error() string {
 	return ""
 }
 
+// Stream expression if there is one
+func (results ActionResults) Stream() string {
+	for _, result := range results {
+		if result.IsStream {
+			return result.Variable
+		}
+	}
+	return ""
+}
+
 // Error expression is only return
 func (results ActionResults) IsOnlyError() bool {
 	return len(results) == 1 && results[0].IsError
@@ -215,6 +338,7 @@ type ActionResult struct {
 	Kind     parser.Kind
 	Variable string
 	IsError  bool
+	IsStream bool
 	Fields   []*ActionResultField
 	Methods  []*ActionResultMethod
 }
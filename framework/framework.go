@@ -2,7 +2,8 @@ package framework
 
 // Flag is used by many of the framework generators
 type Flag struct {
-	Embed  bool
-	Minify bool
-	Hot    bool
+	Embed   bool
+	Minify  bool
+	Hot     bool
+	OpenAPI string // Path to serve the OpenAPI document from, if any
 }
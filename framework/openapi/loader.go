@@ -0,0 +1,392 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/livebud/bud/framework"
+	"github.com/livebud/bud/framework/controller"
+	"github.com/livebud/bud/internal/bail"
+	"github.com/livebud/bud/internal/imports"
+	"github.com/livebud/bud/package/di"
+	"github.com/livebud/bud/package/gomod"
+	"github.com/livebud/bud/package/parser"
+)
+
+func Load(fsys fs.FS, injector *di.Injector, module *gomod.Module, parser *parser.Parser, flag *framework.Flag) (*State, error) {
+	// Serving the document is opt-in
+	if flag.OpenAPI == "" {
+		return nil, fs.ErrNotExist
+	}
+	// The document describes the controllers
+	controllerState, err := controller.Load(fsys, injector, module, parser)
+	if err != nil {
+		return nil, err
+	}
+	loader := &loader{
+		imports:    imports.New(),
+		module:     module,
+		flag:       flag,
+		components: map[string]*Schema{},
+		refs:       map[string]string{},
+	}
+	return loader.Load(controllerState.Controller)
+}
+
+type loader struct {
+	bail.Struct
+	imports    *imports.Set
+	module     *gomod.Module
+	flag       *framework.Flag
+	components map[string]*Schema
+	refs       map[string]string // Maps import paths to component names
+}
+
+// Load the OpenAPI state
+func (l *loader) Load(controller *controller.Controller) (state *State, err error) {
+	defer l.Recover2(&err, "openapi: unable to load")
+	state = new(State)
+	state.Path = l.flag.OpenAPI
+	doc := l.loadDocument(controller)
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	state.Document = strconv.Quote(string(data))
+	l.imports.AddStd("net/http")
+	state.Imports = l.imports.List()
+	return state, nil
+}
+
+func (l *loader) loadDocument(controller *controller.Controller) *Document {
+	doc := new(Document)
+	doc.OpenAPI = "3.0.3"
+	doc.Info = &Info{
+		Title:   l.module.Import(),
+		Version: "0.0.0",
+	}
+	doc.Paths = map[string]*PathItem{}
+	l.loadPaths(doc.Paths, controller)
+	doc.Components = &Components{Schemas: l.components}
+	return doc
+}
+
+func (l *loader) loadPaths(paths map[string]*PathItem, controller *controller.Controller) {
+	for _, action := range controller.Actions {
+		// WebSocket connections aren't described by OpenAPI
+		if action.Socket {
+			continue
+		}
+		route := toPath(action.Route)
+		item, ok := paths[route]
+		if !ok {
+			item = &PathItem{}
+			paths[route] = item
+		}
+		(*item)[strings.ToLower(action.Method)] = l.loadOperation(controller, action)
+	}
+	for _, controller := range controller.Controllers {
+		l.loadPaths(paths, controller)
+	}
+}
+
+// toPath converts a route to an OpenAPI path
+// e.g. /users/:id|int/edit becomes /users/{id}/edit
+func toPath(route string) string {
+	segments := strings.Split(route, "/")
+	for i, segment := range segments {
+		if slot, ok := toSlot(segment); ok {
+			segments[i] = "{" + slot + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// toSlot returns the name of a route's slot
+func toSlot(segment string) (string, bool) {
+	if !strings.HasPrefix(segment, ":") {
+		return "", false
+	}
+	slot := strings.SplitN(segment[1:], "|", 2)[0]
+	return strings.TrimRight(slot, "?*"), true
+}
+
+// slotSchema returns the schema of a slot from its constraint (e.g. :id|int)
+func slotSchema(segment string) *Schema {
+	parts := strings.SplitN(strings.TrimRight(segment, "?*"), "|", 2)
+	if len(parts) == 2 {
+		switch parts[1] {
+		case "int":
+			return &Schema{Type: "integer"}
+		case "uint":
+			return &Schema{Type: "integer", Minimum: new(float64)}
+		}
+	}
+	return &Schema{Type: "string"}
+}
+
+func (l *loader) loadOperation(controller *controller.Controller, action *controller.Action) *Operation {
+	op := new(Operation)
+	op.OperationID = controller.Pascal + action.Pascal
+	if controller.Path != "/" {
+		op.Tags = []string{strings.TrimPrefix(controller.Path, "/")}
+	}
+	// Handler functions write their own responses
+	if action.HandlerFunc {
+		op.Parameters = l.loadPathParams(action.Route, &Schema{})
+		op.Responses = map[string]*Response{
+			"default": {Description: "Response"},
+		}
+		return op
+	}
+	input := l.loadInput(action)
+	op.Parameters = l.loadPathParams(action.Route, input.Schema)
+	if len(input.Schema.Properties) > 0 {
+		switch action.Method {
+		case http.MethodGet, http.MethodDelete:
+			op.Parameters = append(op.Parameters, l.loadQueryParams(input.Schema)...)
+		default:
+			op.RequestBody = l.loadRequestBody(input)
+		}
+	}
+	op.Responses = l.loadResponses(action, len(input.Schema.Properties) > 0)
+	return op
+}
+
+// input is the action's request body or query string
+type input struct {
+	Schema *Schema // Object schema with the input's fields
+	Ref    *Schema // Reference to a single struct input
+	File   bool    // Input contains an uploaded file
+}
+
+func (l *loader) loadInput(action *controller.Action) *input {
+	in := &input{Schema: &Schema{Type: "object"}}
+	params := action.Function.Params()
+	for i, param := range action.Params {
+		if param.IsContext() || param.IsSocket || param.Loader != nil {
+			continue
+		}
+		dt := params[i].Type()
+		isFile, err := parser.IsImportType(dt, "github.com/livebud/bud/framework/controller/controllerrt/request", "File")
+		if err != nil {
+			l.Bail(err)
+		}
+		if isFile {
+			in.File = true
+		}
+		// Single struct inputs are referenced in the request body
+		if param.Variable == "in" {
+			in.Ref = l.schemaOf(dt)
+			decl, err := parser.Definition(dt)
+			if err != nil {
+				l.Bail(err)
+			}
+			if stct, ok := decl.(*parser.Struct); ok {
+				l.loadProperties(in.Schema, stct)
+			}
+			in.File = in.File || hasFile(in.Schema)
+			continue
+		}
+		if in.Schema.Properties == nil {
+			in.Schema.Properties = map[string]*Schema{}
+		}
+		in.Schema.Properties[param.Snake] = l.schemaOf(dt)
+	}
+	return in
+}
+
+// hasFile returns true if one of the fields is an uploaded file
+func hasFile(schema *Schema) bool {
+	for _, property := range schema.Properties {
+		if property.Format == "binary" {
+			return true
+		}
+	}
+	return false
+}
+
+// loadPathParams loads the route's slots. Slots take the input field's schema
+// when there's a matching field and are removed from the input.
+func (l *loader) loadPathParams(route string, schema *Schema) (params []*Parameter) {
+	for _, segment := range strings.Split(route, "/") {
+		slot, ok := toSlot(segment)
+		if !ok {
+			continue
+		}
+		property, ok := schema.Properties[slot]
+		if !ok {
+			property = slotSchema(segment)
+		}
+		delete(schema.Properties, slot)
+		params = append(params, &Parameter{
+			Name:     slot,
+			In:       "path",
+			Required: true,
+			Schema:   property,
+		})
+	}
+	return params
+}
+
+// loadQueryParams loads the input's fields as query parameters
+func (l *loader) loadQueryParams(schema *Schema) (params []*Parameter) {
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		params = append(params, &Parameter{
+			Name:     name,
+			In:       "query",
+			Required: contains(schema.Required, name),
+			Schema:   schema.Properties[name],
+		})
+	}
+	return params
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *loader) loadRequestBody(in *input) *RequestBody {
+	schema := in.Schema
+	if in.Ref != nil {
+		schema = in.Ref
+	}
+	contentType := "application/json"
+	if in.File {
+		contentType = "multipart/form-data"
+	}
+	return &RequestBody{
+		Required: len(in.Schema.Required) > 0,
+		Content: map[string]*MediaType{
+			contentType: {Schema: schema},
+		},
+	}
+}
+
+func (l *loader) loadResponses(action *controller.Action, hasInput bool) map[string]*Response {
+	responses := map[string]*Response{}
+	results := action.Function.Results()
+	var schemas []*Schema
+	var named []string
+	hasError := false
+	for i, result := range action.Results {
+		if result.IsError {
+			hasError = true
+			continue
+		}
+		dt := results[i].Type()
+		if result.IsStream {
+			responses["200"] = l.loadStreamResponse(dt)
+			continue
+		}
+		schemas = append(schemas, l.schemaOf(dt))
+		if result.Named {
+			named = append(named, result.Snake)
+		}
+	}
+	switch {
+	case responses["200"] != nil:
+		// Streamed
+	case len(schemas) == 0:
+		responses["204"] = &Response{Description: "No Content"}
+	case len(schemas) == 1:
+		responses["200"] = jsonResponse("OK", schemas[0])
+	case len(named) == len(schemas):
+		// Named results are returned as an object
+		object := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		for i, name := range named {
+			object.Properties[name] = schemas[i]
+		}
+		responses["200"] = jsonResponse("OK", object)
+	default:
+		// Unnamed results are returned as an array
+		responses["200"] = jsonResponse("OK", &Schema{Type: "array", Items: &Schema{}})
+	}
+	if hasInput {
+		responses["422"] = jsonResponse("Validation Error", l.validationErrorSchema())
+	}
+	if hasError {
+		responses["default"] = jsonResponse("Error", l.errorSchema())
+	}
+	return responses
+}
+
+// loadStreamResponse describes the values of a streamed result. Values are
+// sent as newline-delimited JSON or server-sent events.
+func (l *loader) loadStreamResponse(dt parser.Type) *Response {
+	var item *Schema
+	switch t := dt.(type) {
+	case *parser.ChanType:
+		item = l.schemaOf(t.Value())
+	case *parser.FuncType:
+		// e.g. func(yield func(T) bool)
+		yield := t.Params()[0].(*parser.FuncType)
+		item = l.schemaOf(yield.Params()[0])
+	default:
+		// Readers are sent as-is
+		return &Response{
+			Description: "Stream",
+			Content: map[string]*MediaType{
+				"application/octet-stream": {Schema: &Schema{Type: "string", Format: "binary"}},
+				"text/event-stream":        {Schema: &Schema{Type: "string"}},
+			},
+		}
+	}
+	return &Response{
+		Description: "Stream",
+		Content: map[string]*MediaType{
+			"application/x-ndjson": {Schema: item},
+			"text/event-stream":    {Schema: &Schema{Type: "string"}},
+		},
+	}
+}
+
+func jsonResponse(description string, schema *Schema) *Response {
+	return &Response{
+		Description: description,
+		Content: map[string]*MediaType{
+			"application/json": {Schema: schema},
+		},
+	}
+}
+
+// errorSchema is the body of error responses
+func (l *loader) errorSchema() *Schema {
+	l.components["Error"] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"error": {Type: "string"},
+		},
+		Required: []string{"error"},
+	}
+	return &Schema{Ref: "#/components/schemas/Error"}
+}
+
+// validationErrorSchema is the body of responses to invalid input
+func (l *loader) validationErrorSchema() *Schema {
+	l.components["ValidationError"] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"errors": {
+				Type:                 "object",
+				AdditionalProperties: &Schema{Type: "string"},
+			},
+		},
+		Required: []string{"errors"},
+	}
+	return &Schema{Ref: "#/components/schemas/ValidationError"}
+}
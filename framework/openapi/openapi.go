@@ -0,0 +1,49 @@
+package openapi
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/livebud/bud/framework"
+	"github.com/livebud/bud/internal/gotemplate"
+	"github.com/livebud/bud/package/di"
+	"github.com/livebud/bud/package/gomod"
+	"github.com/livebud/bud/package/overlay"
+	"github.com/livebud/bud/package/parser"
+)
+
+//go:embed openapi.gotext
+var template string
+
+var generator = gotemplate.MustParse("framework/openapi/openapi.gotext", template)
+
+// Generate the OpenAPI file
+func Generate(state *State) ([]byte, error) {
+	return generator.Generate(state)
+}
+
+// New OpenAPI generator
+func New(injector *di.Injector, module *gomod.Module, parser *parser.Parser, flag *framework.Flag) *Generator {
+	return &Generator{injector, module, parser, flag}
+}
+
+// Generator for the OpenAPI document that describes the controllers
+type Generator struct {
+	injector *di.Injector
+	module   *gomod.Module
+	parser   *parser.Parser
+	flag     *framework.Flag
+}
+
+func (g *Generator) GenerateFile(ctx context.Context, fsys overlay.F, file *overlay.File) error {
+	state, err := Load(fsys, g.injector, g.module, g.parser, g.flag)
+	if err != nil {
+		return err
+	}
+	code, err := Generate(state)
+	if err != nil {
+		return err
+	}
+	file.Data = code
+	return nil
+}
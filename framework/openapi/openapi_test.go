@@ -0,0 +1,205 @@
+package openapi_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/livebud/bud/internal/cli/testcli"
+	"github.com/livebud/bud/internal/is"
+	"github.com/livebud/bud/internal/testdir"
+)
+
+func TestNoControllers(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	result, err := cli.Run(ctx, "build")
+	is.NoErr(err)
+	is.Equal(result.Stderr(), "")
+	is.NoErr(td.NotExists("bud/internal/openapi"))
+}
+
+const controller = `
+	package users
+	import (
+		"context"
+		"time"
+	)
+	type Controller struct {}
+	type Timestamps struct {
+		CreatedAt time.Time ` + "`" + `json:"created_at"` + "`" + `
+	}
+	type User struct {
+		Timestamps
+		ID     int      ` + "`" + `json:"id"` + "`" + `
+		Name   string   ` + "`" + `json:"name"` + "`" + `
+		Role   string   ` + "`" + `json:"role,omitempty"` + "`" + `
+		Friend *User    ` + "`" + `json:"friend,omitempty"` + "`" + `
+		Tags   []string ` + "`" + `json:"tags"` + "`" + `
+		secret string
+	}
+	type Input struct {
+		Name string ` + "`" + `json:"name" validate:"required,max=20"` + "`" + `
+		Role string ` + "`" + `json:"role" validate:"oneof=admin member"` + "`" + `
+	}
+	func (c *Controller) Index(ctx context.Context, page int) ([]*User, error) {
+		return nil, nil
+	}
+	func (c *Controller) Show(id int) (*User, error) {
+		return nil, nil
+	}
+	func (c *Controller) Create(in *Input) (*User, error) {
+		return nil, nil
+	}
+	func (c *Controller) Delete(id int) {}
+`
+
+func TestDocument(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/users/controller.go"] = controller
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run", "--openapi=/openapi.json")
+	is.NoErr(err)
+	defer app.Close()
+	res, err := app.Get("/openapi.json")
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	is.Equal(res.Header("Content-Type"), "application/json")
+	var doc struct {
+		OpenAPI    string                                       `json:"openapi"`
+		Paths      map[string]map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+	}
+	is.NoErr(json.Unmarshal(res.Body().Bytes(), &doc))
+	is.Equal(doc.OpenAPI, "3.0.3")
+	is.Equal(len(doc.Paths), 2)
+	is.Equal(doc.Paths["/users"]["get"]["operationId"], "UsersIndex")
+	is.Equal(doc.Paths["/users"]["post"]["operationId"], "UsersCreate")
+	is.Equal(doc.Paths["/users/{id}"]["get"]["operationId"], "UsersShow")
+	is.Equal(doc.Paths["/users/{id}"]["delete"]["operationId"], "UsersDelete")
+	params, err := json.Marshal(doc.Paths["/users"]["get"]["parameters"])
+	is.NoErr(err)
+	is.Equal(string(params), `[{"in":"query","name":"page","schema":{"type":"integer"}}]`)
+	params, err = json.Marshal(doc.Paths["/users/{id}"]["get"]["parameters"])
+	is.NoErr(err)
+	is.Equal(string(params), `[{"in":"path","name":"id","required":true,"schema":{"type":"integer"}}]`)
+	body, err := json.Marshal(doc.Paths["/users"]["post"]["requestBody"])
+	is.NoErr(err)
+	is.Equal(string(body), `{"content":{"application/json":{"schema":{"$ref":"#/components/schemas/Input"}}},"required":true}`)
+	responses, err := json.Marshal(doc.Paths["/users/{id}"]["delete"]["responses"])
+	is.NoErr(err)
+	is.Equal(string(responses), `{"204":{"description":"No Content"}}`)
+	is.Equal(string(doc.Components.Schemas["User"]), `{
+        "type": "object",
+        "properties": {
+          "created_at": {
+            "type": "string",
+            "format": "date-time"
+          },
+          "friend": {
+            "$ref": "#/components/schemas/User"
+          },
+          "id": {
+            "type": "integer"
+          },
+          "name": {
+            "type": "string"
+          },
+          "role": {
+            "type": "string"
+          },
+          "tags": {
+            "type": "array",
+            "items": {
+              "type": "string"
+            }
+          }
+        }
+      }`)
+	is.Equal(string(doc.Components.Schemas["Input"]), `{
+        "type": "object",
+        "properties": {
+          "name": {
+            "type": "string",
+            "maxLength": 20
+          },
+          "role": {
+            "type": "string",
+            "enum": [
+              "admin",
+              "member"
+            ]
+          }
+        },
+        "required": [
+          "name"
+        ]
+      }`)
+}
+
+func TestPath(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/users/controller.go"] = controller
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run", "--openapi=/api/spec.json")
+	is.NoErr(err)
+	defer app.Close()
+	res, err := app.Get("/openapi.json")
+	is.NoErr(err)
+	is.Equal(res.Status(), 404)
+	res, err = app.Get("/api/spec.json")
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	is.Equal(res.Header("Content-Type"), "application/json")
+}
+
+func TestDisabled(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/users/controller.go"] = controller
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	res, err := app.Get("/openapi.json")
+	is.NoErr(err)
+	is.Equal(res.Status(), 404)
+	// Serving the document is opt-in
+	is.NoErr(td.NotExists("bud/internal/openapi/openapi.go"))
+}
+
+func TestActionPrecedence(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/users/controller.go"] = controller
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run", "--openapi=/users")
+	is.NoErr(err)
+	defer app.Close()
+	// The document doesn't shadow the users index
+	res, err := app.Get("/users")
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	is.True(!strings.Contains(res.Body().String(), "3.0.3"))
+}
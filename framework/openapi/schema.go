@@ -0,0 +1,270 @@
+package openapi
+
+import (
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/livebud/bud/package/parser"
+	"github.com/matthewmueller/gotext"
+)
+
+// schemaOf maps a Go type to a JSON schema. Named structs are added to the
+// components and referenced.
+func (l *loader) schemaOf(t parser.Type) *Schema {
+	switch t := t.(type) {
+	case *parser.StarType:
+		return l.schemaOf(t.Inner())
+	case *parser.ArrayType:
+		// Byte slices are encoded as base64 strings by encoding/json
+		if t.Inner().String() == "byte" {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: l.schemaOf(t.Inner())}
+	case *parser.MapType:
+		return &Schema{Type: "object", AdditionalProperties: l.schemaOf(t.Value())}
+	case *parser.IdentType:
+		if parser.IsBuiltin(t) {
+			return builtinSchema(t.Name())
+		}
+		return l.schemaOfDefinition(t)
+	case *parser.SelectorType:
+		importPath, err := t.ImportPath()
+		if err != nil {
+			l.Bail(err)
+		}
+		if schema := knownSchema(importPath, t.Name()); schema != nil {
+			return schema
+		}
+		return l.schemaOfDefinition(t)
+	default:
+		// Interfaces, anonymous structs, functions and channels can hold anything
+		return &Schema{}
+	}
+}
+
+func builtinSchema(name string) *Schema {
+	switch name {
+	case "string":
+		return &Schema{Type: "string"}
+	case "bool":
+		return &Schema{Type: "boolean"}
+	case "int", "int8", "int16", "uint", "uint8", "uint16", "byte", "uintptr":
+		return &Schema{Type: "integer"}
+	case "int32", "uint32", "rune":
+		return &Schema{Type: "integer", Format: "int32"}
+	case "int64", "uint64":
+		return &Schema{Type: "integer", Format: "int64"}
+	case "float32":
+		return &Schema{Type: "number", Format: "float"}
+	case "float64":
+		return &Schema{Type: "number", Format: "double"}
+	case "error":
+		return &Schema{Type: "string"}
+	default:
+		return &Schema{}
+	}
+}
+
+// knownSchema maps types that marshal differently than their definition
+func knownSchema(importPath, name string) *Schema {
+	switch importPath + "." + name {
+	case "time.Time":
+		return &Schema{Type: "string", Format: "date-time"}
+	case "time.Duration":
+		return &Schema{Type: "integer", Format: "int64"}
+	case "net/url.URL":
+		return &Schema{Type: "string", Format: "uri"}
+	case "encoding/json.RawMessage":
+		return &Schema{}
+	case "github.com/livebud/bud/framework/controller/controllerrt/request.File":
+		return &Schema{Type: "string", Format: "binary"}
+	default:
+		return nil
+	}
+}
+
+func (l *loader) schemaOfDefinition(t parser.Type) *Schema {
+	decl, err := parser.Definition(t)
+	if err != nil {
+		l.Bail(err)
+	}
+	switch decl := decl.(type) {
+	case *parser.Struct:
+		return l.schemaOfStruct(decl)
+	case *parser.Alias:
+		return l.schemaOf(decl.Type())
+	case *parser.TypeDef:
+		return l.schemaOf(decl.Type())
+	default:
+		return &Schema{}
+	}
+}
+
+// schemaOfStruct references the struct's component, adding it the first time
+func (l *loader) schemaOfStruct(stct *parser.Struct) *Schema {
+	importPath, err := stct.Package().Import()
+	if err != nil {
+		l.Bail(err)
+	}
+	key := importPath + "." + stct.Name()
+	if name, ok := l.refs[key]; ok {
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	name := stct.Name()
+	// Qualify structs with the same name from different packages
+	if _, ok := l.components[name]; ok {
+		name = gotext.Pascal(path.Base(importPath)) + name
+	}
+	l.refs[key] = name
+	// Add the component before loading the fields to support recursive types
+	schema := &Schema{Type: "object"}
+	l.components[name] = schema
+	l.loadProperties(schema, stct)
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// loadProperties adds the struct's fields to the schema, following the rules
+// of encoding/json
+func (l *loader) loadProperties(schema *Schema, stct *parser.Struct) {
+	for _, field := range stct.Fields() {
+		tags, err := field.Tags()
+		if err != nil {
+			l.Bail(err)
+		}
+		name := jsonName(tags)
+		if name == "-" {
+			continue
+		}
+		// Fields of untagged embedded structs are promoted
+		if field.Embedded() && name == "" {
+			decl, err := parser.Definition(field.Type())
+			if err != nil {
+				l.Bail(err)
+			}
+			if embedded, ok := decl.(*parser.Struct); ok {
+				l.loadProperties(schema, embedded)
+				continue
+			}
+		}
+		if field.Private() {
+			continue
+		}
+		if name == "" {
+			name = field.Name()
+		}
+		property := l.schemaOf(field.Type())
+		required := loadRules(property, validateTag(tags))
+		if schema.Properties == nil {
+			schema.Properties = map[string]*Schema{}
+		}
+		schema.Properties[name] = property
+		// Only validated fields are required, since requests may leave out any
+		// other field
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// jsonName returns the field's name in JSON
+func jsonName(tags parser.Tags) string {
+	return tags.Get("json")
+}
+
+// validateTag rebuilds the validate tag. Struct tags are split by commas.
+func validateTag(tags parser.Tags) string {
+	for _, tag := range tags {
+		if tag.Key != "validate" {
+			continue
+		}
+		return strings.Join(append([]string{tag.Value}, tag.Options...), ",")
+	}
+	return ""
+}
+
+// loadRules adds validation rules to the schema, returning true if the value
+// is required. Rules follow the syntax of the validate package.
+func loadRules(schema *Schema, tag string) (required bool) {
+	for rest := tag; rest != ""; {
+		var part string
+		// Regular expressions may contain commas, so they take the rest of the tag
+		if strings.HasPrefix(rest, "regex=") {
+			part, rest = rest, ""
+		} else if i := strings.Index(rest, ","); i >= 0 {
+			part, rest = rest[:i], rest[i+1:]
+		} else {
+			part, rest = rest, ""
+		}
+		name, param := strings.TrimSpace(part), ""
+		if i := strings.Index(name, "="); i >= 0 {
+			name, param = name[:i], name[i+1:]
+		}
+		switch name {
+		case "required":
+			required = true
+		case "email":
+			schema.Format = "email"
+		case "regex":
+			schema.Pattern = param
+		case "oneof":
+			for _, option := range strings.Fields(param) {
+				schema.Enum = append(schema.Enum, enumValue(schema.Type, option))
+			}
+		case "min", "max", "len":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			loadBound(schema, name, n)
+		}
+	}
+	return required
+}
+
+// loadBound sets the bounds of numbers, strings and arrays
+func loadBound(schema *Schema, rule string, n float64) {
+	size := int(n)
+	switch schema.Type {
+	case "integer", "number":
+		switch rule {
+		case "min":
+			schema.Minimum = &n
+		case "max":
+			schema.Maximum = &n
+		}
+	case "string":
+		if rule == "min" || rule == "len" {
+			schema.MinLength = &size
+		}
+		if rule == "max" || rule == "len" {
+			schema.MaxLength = &size
+		}
+	case "array":
+		if rule == "min" || rule == "len" {
+			schema.MinItems = &size
+		}
+		if rule == "max" || rule == "len" {
+			schema.MaxItems = &size
+		}
+	}
+}
+
+// enumValue matches the option to the schema's type
+func enumValue(dataType, option string) interface{} {
+	switch dataType {
+	case "integer":
+		if n, err := strconv.ParseInt(option, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(option, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(option); err == nil {
+			return b
+		}
+	}
+	return option
+}
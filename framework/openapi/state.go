@@ -0,0 +1,11 @@
+package openapi
+
+import (
+	"github.com/livebud/bud/internal/imports"
+)
+
+type State struct {
+	Imports  []*imports.Import
+	Path     string // Path the document is served from
+	Document string // Quoted JSON document
+}
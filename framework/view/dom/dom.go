@@ -6,6 +6,7 @@ import (
 	_ "embed"
 	"fmt"
 	"io/fs"
+	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -25,6 +26,27 @@ var template string
 // generator
 var generator = gotemplate.MustParse("dom.gotext", template)
 
+//go:embed island.gotext
+var islandTemplate string
+
+// islandGenerator generates the client-side entrypoint of an island
+var islandGenerator = gotemplate.MustParse("island.gotext", islandTemplate)
+
+// define replaces the globals that libraries like React and Vue expect a
+// bundler to set
+var define = map[string]string{
+	"process.env.NODE_ENV":  `"production"`,
+	"__VUE_OPTIONS_API__":   "true",
+	"__VUE_PROD_DEVTOOLS__": "false",
+}
+
+// State of the client-side entrypoint
+type State struct {
+	*entrypoint.View
+	// Routes are the other views that the client-side router can navigate to
+	Routes []*entrypoint.View
+}
+
 // Serve node_modules
 // TODO: migrate to it's own package
 func NodeModules(module *gomod.Module) overlay.FileServer {
@@ -43,6 +65,7 @@ func NodeModules(module *gomod.Module) overlay.FileServer {
 			// Add "import" condition to support svelte/internal
 			// https://esbuild.github.io/api/#how-conditions-work
 			Conditions: []string{"browser", "default", "import"},
+			Define:     define,
 			Metafile:   true,
 			Bundle:     true,
 			Plugins:    plugins,
@@ -84,15 +107,28 @@ func (c *Compiler) Compile(ctx context.Context, fsys fs.FS) ([]esbuild.OutputFil
 	if err != nil {
 		return nil, err
 	}
-	entries := make([]esbuild.EntryPoint, len(views))
+	islands, err := entrypoint.ListIslands(fsys, "view")
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]esbuild.EntryPoint, 0, len(views)+len(islands))
 	viewDir := filepath.Join("bud", "view") + string(filepath.Separator)
-	for i, view := range views {
+	for _, view := range views {
 		entryPath := filepath.Join("bud", toEntry(string(view.Page)))
 		outPath := strings.TrimPrefix(entryPath, viewDir)
-		entries[i] = esbuild.EntryPoint{
+		entries = append(entries, esbuild.EntryPoint{
 			InputPath:  entryPath,
 			OutputPath: outPath,
-		}
+		})
+	}
+	// Islands have their own entries, so static pages only load their islands
+	for _, island := range islands {
+		entryPath := filepath.Join("bud", toEntry(string(island.Component)))
+		outPath := strings.TrimPrefix(entryPath, viewDir)
+		entries = append(entries, esbuild.EntryPoint{
+			InputPath:  entryPath,
+			OutputPath: outPath,
+		})
 	}
 	// If the name starts with node_modules, trim it to allow esbuild to do
 	// the resolving. e.g. node_modules/livebud => livebud
@@ -106,6 +142,9 @@ func (c *Compiler) Compile(ctx context.Context, fsys fs.FS) ([]esbuild.OutputFil
 		// Add "import" condition to support svelte/internal
 		// https://esbuild.github.io/api/#how-conditions-work
 		Conditions:        []string{"browser", "default", "import"},
+		JSXFactory:        "__budReact__.createElement",
+		JSXFragment:       "__budReact__.Fragment",
+		Define:            define,
 		Metafile:          false,
 		Bundle:            true,
 		Splitting:         true,
@@ -114,6 +153,8 @@ func (c *Compiler) Compile(ctx context.Context, fsys fs.FS) ([]esbuild.OutputFil
 		MinifyWhitespace:  true,
 		Plugins: append([]esbuild.Plugin{
 			domPlugin(fsys, c.module),
+			domIslandPlugin(fsys, c.module),
+			domJSXPlugin(),
 		}, c.transformer.Plugins()...),
 		Write: false,
 	})
@@ -162,11 +203,16 @@ func (c *Compiler) ServeFile(ctx context.Context, fsys overlay.F, file *overlay.
 		Platform:      esbuild.PlatformBrowser,
 		// Add "import" condition to support svelte/internal
 		// https://esbuild.github.io/api/#how-conditions-work
-		Conditions: []string{"browser", "default", "import"},
-		Metafile:   true,
-		Bundle:     true,
+		Conditions:  []string{"browser", "default", "import"},
+		JSXFactory:  "__budReact__.createElement",
+		JSXFragment: "__budReact__.Fragment",
+		Define:      define,
+		Metafile:    true,
+		Bundle:      true,
 		Plugins: append([]esbuild.Plugin{
 			domPlugin(fsys, c.module),
+			domIslandPlugin(fsys, c.module),
+			domJSXPlugin(),
 			domExternalizePlugin(),
 		}, c.transformer.Plugins()...),
 	})
@@ -221,12 +267,12 @@ func trimEntrypoint(path string) string {
 	return path
 }
 
-// Build the bud/view/$page.{jsx,svelte} client-side entrypoint
+// Build the bud/view/$page.{jsx,tsx,svelte} client-side entrypoint
 func domPlugin(fsys fs.FS, module *gomod.Module) esbuild.Plugin {
 	return esbuild.Plugin{
 		Name: "dom",
 		Setup: func(epb esbuild.PluginBuild) {
-			epb.OnResolve(esbuild.OnResolveOptions{Filter: `^bud\/view\/(?:[A-Za-z\-0-9]+\/)*_[A-Za-z\-0-9]+\.(svelte|jsx)\.js$`}, func(args esbuild.OnResolveArgs) (result esbuild.OnResolveResult, err error) {
+			epb.OnResolve(esbuild.OnResolveOptions{Filter: `^bud\/view\/(?:[A-Za-z\-0-9]+\/)*_[A-Za-z\-0-9]+\.(svelte|jsx|tsx|vue)\.js$`}, func(args esbuild.OnResolveArgs) (result esbuild.OnResolveResult, err error) {
 				result.Namespace = "dom"
 				result.Path = args.Path
 				return result, nil
@@ -236,7 +282,11 @@ func domPlugin(fsys fs.FS, module *gomod.Module) esbuild.Plugin {
 				if err != nil {
 					return result, err
 				}
-				code, err := generator.Generate(view)
+				routes, err := entrypoint.List(fsys, "view")
+				if err != nil {
+					return result, err
+				}
+				code, err := generator.Generate(&State{view, routes})
 				if err != nil {
 					return result, err
 				}
@@ -250,6 +300,58 @@ func domPlugin(fsys fs.FS, module *gomod.Module) esbuild.Plugin {
 	}
 }
 
+// Build the bud/view/$island.svelte client-side entrypoint
+func domIslandPlugin(fsys fs.FS, module *gomod.Module) esbuild.Plugin {
+	return esbuild.Plugin{
+		Name: "dom_island",
+		Setup: func(epb esbuild.PluginBuild) {
+			epb.OnResolve(esbuild.OnResolveOptions{Filter: `^bud\/view\/(?:[A-Za-z\-0-9]+\/)*_[A-Za-z\-0-9]+\.island\.svelte\.js$`}, func(args esbuild.OnResolveArgs) (result esbuild.OnResolveResult, err error) {
+				result.Namespace = "dom_island"
+				result.Path = args.Path
+				return result, nil
+			})
+			epb.OnLoad(esbuild.OnLoadOptions{Filter: `.*`, Namespace: "dom_island"}, func(args esbuild.OnLoadArgs) (result esbuild.OnLoadResult, err error) {
+				island, err := entrypoint.FindIslandByClient(fsys, filepath.Clean(args.Path))
+				if err != nil {
+					return result, err
+				}
+				code, err := islandGenerator.Generate(island)
+				if err != nil {
+					return result, err
+				}
+				contents := string(code)
+				result.ResolveDir = module.Directory()
+				result.Contents = &contents
+				result.Loader = esbuild.LoaderJS
+				return result, nil
+			})
+		},
+	}
+}
+
+// Load jsx and tsx files with React in scope, so views don't need to import it
+func domJSXPlugin() esbuild.Plugin {
+	return esbuild.Plugin{
+		Name: "dom_jsx",
+		Setup: func(epb esbuild.PluginBuild) {
+			epb.OnLoad(esbuild.OnLoadOptions{Filter: `\.(jsx|tsx)$`}, func(args esbuild.OnLoadArgs) (result esbuild.OnLoadResult, err error) {
+				code, err := os.ReadFile(args.Path)
+				if err != nil {
+					return result, err
+				}
+				contents := `import * as __budReact__ from "react"` + "\n\n" + string(code)
+				result.ResolveDir = filepath.Dir(args.Path)
+				result.Contents = &contents
+				result.Loader = esbuild.LoaderJSX
+				if filepath.Ext(args.Path) == ".tsx" {
+					result.Loader = esbuild.LoaderTSX
+				}
+				return result, nil
+			})
+		},
+	}
+}
+
 // Transforms the dom file imports into including the "__LIVEBUD_EXTERNAL__:" prefix
 func domExternalizePlugin() esbuild.Plugin {
 	return esbuild.Plugin{
@@ -51,6 +51,9 @@ func TestServeFile(t *testing.T) {
 	is.True(strings.Contains(string(code), `"/bud/view/index.svelte": view_default`))
 	is.True(strings.Contains(string(code), `page: "/bud/view/index.svelte",`))
 	is.True(strings.Contains(string(code), `hot: new Hot("http://127.0.0.1:35729/bud/hot/view/index.svelte", components)`))
+	is.True(strings.Contains(string(code), `client: "/bud/view/_index.svelte.js",`))
+	is.True(strings.Contains(string(code), `"/": "/bud/view/_index.svelte.js",`))
+	is.True(strings.Contains(string(code), `"/about": "/bud/view/about/_index.svelte.js"`))
 
 	// Unwrapped version with node_modules rewritten
 	code, err = fs.ReadFile(overlay, "bud/view/index.svelte")
@@ -85,6 +88,44 @@ func TestServeFile(t *testing.T) {
 	is.True(!strings.Contains(string(code), `hot: new Hot("http://127.0.0.1:35729/bud/hot/view/about/index.svelte", components)`))
 }
 
+func TestServeFileJSX(t *testing.T) {
+	is := is.New(t)
+	log := testlog.New()
+	ctx := context.Background()
+	dir := t.TempDir()
+	vm, err := v8.Load()
+	is.NoErr(err)
+	svelteCompiler, err := svelte.Load(vm)
+	is.NoErr(err)
+	transformer := transformrt.MustLoad(
+		svelte.NewTransformable(svelteCompiler),
+	)
+	td := testdir.New(dir)
+	td.Files["view/frame.tsx"] = `export default ({ children }: { children: any }) => <main>{children}</main>`
+	td.Files["view/index.tsx"] = `export default () => <h1>index</h1>`
+	is.NoErr(td.Write(ctx))
+	module, err := gomod.Find(dir)
+	is.NoErr(err)
+	overlay, err := overlay.Load(log, module)
+	is.NoErr(err)
+	overlay.FileServer("bud/view", dom.New(module, transformer.DOM))
+	// Read the wrapped version of index.tsx with node_modules rewritten
+	code, err := fs.ReadFile(overlay, "bud/view/_index.tsx.js")
+	is.NoErr(err)
+	is.True(strings.Contains(string(code), `from "/bud/node_modules/livebud/runtime/jsx"`))
+	is.True(strings.Contains(string(code), `from "/bud/node_modules/react"`))
+	is.True(strings.Contains(string(code), `createElement("h1", null, "index")`))
+	is.True(strings.Contains(string(code), `page: "/bud/view/index.tsx",`))
+	is.True(strings.Contains(string(code), `"/bud/view/frame.tsx",`))
+	is.True(strings.Contains(string(code), `client: "/bud/view/_index.tsx.js",`))
+	// Unwrapped version doesn't need to import React
+	code, err = fs.ReadFile(overlay, "bud/view/index.tsx")
+	is.NoErr(err)
+	is.True(strings.Contains(string(code), `from "/bud/node_modules/react"`))
+	is.True(strings.Contains(string(code), `createElement("h1", null, "index")`))
+	is.True(!strings.Contains(string(code), `page: "/bud/view/index.tsx",`))
+}
+
 func TestNodeModules(t *testing.T) {
 	is := is.New(t)
 	log := testlog.New()
@@ -169,6 +210,41 @@ func TestGenerateDir(t *testing.T) {
 	is.True(strings.Contains(string(code), `"bud_props"`))
 }
 
+func TestIsland(t *testing.T) {
+	is := is.New(t)
+	log := testlog.New()
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["view/index.svelte"] = `<script>import Counter from "./Counter.island.svelte"</script><h1>index</h1><Counter />`
+	td.Files["view/Counter.island.svelte"] = `<script>export let count = 0</script><button on:click={() => count++}>{count}</button>`
+	td.NodeModules["livebud"] = "*"
+	td.NodeModules["svelte"] = versions.Svelte
+	is.NoErr(td.Write(ctx))
+	vm, err := v8.Load()
+	is.NoErr(err)
+	svelteCompiler, err := svelte.Load(vm)
+	is.NoErr(err)
+	transformer := transformrt.MustLoad(svelte.NewTransformable(svelteCompiler))
+	module, err := gomod.Find(dir)
+	is.NoErr(err)
+	overlay, err := overlay.Load(log, module)
+	is.NoErr(err)
+	overlay.DirGenerator("bud/view", dom.New(module, transformer.DOM))
+	// Islands get their own entry alongside the page's
+	code, err := fs.ReadFile(overlay, "bud/view/_Counter.island.svelte.js")
+	is.NoErr(err)
+	is.True(strings.Contains(string(code), `client:"/bud/view/_Counter.island.svelte.js"`))
+	is.True(strings.Contains(string(code), `"bud-island"`))
+	is.True(!strings.Contains(string(code), `"H1"`))
+	code, err = fs.ReadFile(overlay, "bud/view/_index.svelte.js")
+	is.NoErr(err)
+	is.True(strings.Contains(string(code), `page:"/bud/view/index.svelte"`))
+	// Islands aren't pages
+	_, err = fs.ReadFile(overlay, "bud/view/_Counter.svelte.js")
+	is.True(err != nil)
+}
+
 func TestImportLocal(t *testing.T) {
 	t.SkipNow()
 }
@@ -2,10 +2,12 @@ package view
 
 import (
 	"context"
+	"errors"
 	"io/fs"
 	"path"
 
 	"github.com/livebud/bud/framework"
+	"github.com/livebud/bud/framework/client/tsclient"
 	"github.com/livebud/bud/framework/view/dom"
 	"github.com/livebud/bud/framework/view/ssr"
 
@@ -77,6 +79,16 @@ func (l *loader) Load(ctx context.Context) (state *State, err error) {
 				Data: file.Contents,
 			})
 		}
+		// Add the TypeScript client, if there are controllers
+		clientCode, err := tsclient.Compile(l.fsys)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		} else if err == nil {
+			state.Embeds = append(state.Embeds, &embed.File{
+				Path: "bud/view/_client.js",
+				Data: clientCode,
+			})
+		}
 	}
 	// fmt.Println(l.Flag.Embed, l.Transform.SSR, views)
 	if l.flag.Embed {
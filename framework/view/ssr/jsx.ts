@@ -1,5 +1,15 @@
 import ReactSSR from "react-dom/server"
 import React from "react"
+import {
+  Chunk,
+  PageError,
+  bodyMarker,
+  defaultCSS,
+  escapeHTML,
+  hydrateScripts,
+  renderHead,
+  withoutOverrides,
+} from "./html"
 
 type View = {
   page: any
@@ -10,35 +20,123 @@ type View = {
 }
 
 export function createView(view: View) {
-  return function ({ props, context }) {
-    let component = React.createElement(view.page, props, [])
-    for (let frame of view.frames) {
-      component = React.createElement(frame, props, component)
-    }
-    let component2 = React.createElement("div", { id: "bud_target" }, component)
-    const layout = view.layout || defaultLayout
-    let component3 = React.createElement(layout, props, component2)
-    let html = ReactSSR.renderToString(component3)
-    let inject = ""
-    const hydrate = JSON.stringify(props)
-    inject += `<script id="bud_props" type="text/template" defer>${hydrate}</script>`
-    inject += `<script type="module" src="${view.client}" defer></script>`
-    html = html.replace("</head>", inject + `</head>`)
+  const layout = view.layout || DefaultLayout
+  const error = view.error || DefaultError
+  // Render the view into a single response
+  function renderView({ props, context }) {
+    props = props || {}
+    const pageError: PageError | undefined = props.bud_error
+    const html = renderPage(view, error, props)
+    const head = `
+          ${renderHead(props.bud_head)}${errorTitle(view, props)}
+          ${hydrateScripts(view.client, props)}
+        `
+    const body = '<div id="bud_target">' + html + "</div>"
     return {
-      status: 200,
+      status: pageError ? pageError.status : 200,
       headers: {
         "Content-Type": "text/html",
       },
-      body: html,
+      body: renderLayout(layout, props, head, body),
     }
   }
+  // Stream the view, flushing the layout before rendering the page. The
+  // scripts are written after the page.
+  renderView.stream = function ({ props, context }, write: (chunk: Chunk) => void) {
+    props = props || {}
+    const pageError: PageError | undefined = props.bud_error
+    const head = `
+          ${renderHead(props.bud_head)}${errorTitle(view, props)}
+        `
+    const html = renderLayout(layout, props, head, bodyMarker)
+    const index = html.indexOf(bodyMarker)
+    write({
+      status: pageError ? pageError.status : 200,
+      headers: {
+        "Content-Type": "text/html",
+      },
+      body: index < 0 ? html : html.slice(0, index),
+    })
+    write({
+      body:
+        '<div id="bud_target">' +
+        renderPage(view, error, props) +
+        "</div>" +
+        hydrateScripts(view.client, props),
+    })
+    write({
+      body: index < 0 ? "" : html.slice(index + bodyMarker.length),
+      tail: true,
+    })
+  }
+  return renderView
 }
 
-function defaultLayout(props) {
+// Errors render the closest error page in place of the page and its frames.
+// Otherwise the page is rendered within its frames, from the innermost frame
+// out.
+function renderPage(view: View, error: any, props: any): string {
+  const pageError: PageError | undefined = props.bud_error
+  if (pageError) {
+    return ReactSSR.renderToString(React.createElement(error, { ...props, error: pageError }))
+  }
+  let element = React.createElement(view.page, props)
+  for (let i = view.frames.length - 1; i >= 0; i--) {
+    element = React.createElement(view.frames[i], props, element)
+  }
+  return ReactSSR.renderToString(element)
+}
+
+// Render the layout around the head and body. Layouts aren't hydrated, so
+// they're rendered as static markup.
+function renderLayout(layout: any, props: any, head: string, body: string): string {
+  let html = ReactSSR.renderToStaticMarkup(
+    React.createElement(layout, props, React.createElement("bud-body"))
+  )
+  html = html.replace("<bud-body></bud-body>", () => body)
+  // The controller's head takes precedence over the layout's head
+  const start = html.indexOf("<head>")
+  const end = html.indexOf("</head>")
+  if (start >= 0 && end > start) {
+    const layoutHead = withoutOverrides(props.bud_head, html.slice(start + 6, end))
+    html = html.slice(0, start + 6) + layoutHead + head + html.slice(end)
+  } else {
+    html = head + html
+  }
+  if (html.startsWith("<html")) {
+    html = "<!doctype html>" + html
+  }
+  return html
+}
+
+// The default error page has a title, unless the controller set one
+function errorTitle(view: View, props: any): string {
+  const pageError: PageError | undefined = props.bud_error
+  if (!pageError || view.error || (props.bud_head && props.bud_head.title)) return ""
+  return `<title>${escapeHTML(String(pageError.status))}</title>`
+}
+
+function DefaultLayout(props: any) {
   return React.createElement(
     "html",
     null,
-    React.createElement("head", null),
+    React.createElement(
+      "head",
+      null,
+      React.createElement("meta", { charSet: "utf-8" }),
+      React.createElement("style", { dangerouslySetInnerHTML: { __html: defaultCSS } })
+    ),
     React.createElement("body", null, props.children)
   )
 }
+
+// Default error page when there's no error.jsx. This needs to match the
+// default error page in livebud/runtime/jsx.
+function DefaultError(props: { error: PageError }) {
+  return React.createElement(
+    React.Fragment,
+    null,
+    React.createElement("h1", null, props.error.status),
+    React.createElement("p", null, props.error.message)
+  )
+}
@@ -1,6 +1,8 @@
 package ssr
 
 //go:generate go run github.com/evanw/esbuild/cmd/esbuild svelte.ts --outfile=svelte.js --log-level=warning --format=esm --bundle
+//go:generate go run github.com/evanw/esbuild/cmd/esbuild jsx.ts --outfile=jsx.js --log-level=warning --format=esm --bundle --external:react --external:react-dom
+//go:generate go run github.com/evanw/esbuild/cmd/esbuild vue.ts --outfile=vue.js --log-level=warning --format=esm --bundle --external:vue
 
 import (
 	"context"
@@ -37,6 +39,16 @@ func (res *Response) Write(w http.ResponseWriter) {
 	w.Write([]byte(res.Body))
 }
 
+// Chunk of a streamed response. The first chunk has the status and headers.
+// The chunk that closes the document is marked as the tail, so deferred props
+// can be streamed in before it.
+type Chunk struct {
+	Status  int               `json:"status,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	Tail    bool              `json:"tail,omitempty"`
+}
+
 func New(module *gomod.Module, transformer transformrt.Transformer) *Compiler {
 	return &Compiler{module, transformer}
 }
@@ -64,14 +76,20 @@ func (c *Compiler) Compile(ctx context.Context, fsys fs.FS) ([]byte, error) {
 		JSXFragment:   "__budReact__.Fragment",
 		Bundle:        true,
 		Metafile:      true,
+		Define: map[string]string{
+			"process.env.NODE_ENV": `"production"`,
+		},
 		Plugins: append([]esbuild.Plugin{
 			ssrPlugin(fsys, dir),
 			ssrRuntimePlugin(fsys, dir),
+			islandPlugin(fsys, dir),
 			jsxPlugin(fsys, dir),
 			jsxRuntimePlugin(fsys, dir),
 			jsxTransformPlugin(fsys, dir),
 			sveltePlugin(fsys, dir),
 			svelteRuntimePlugin(fsys, dir),
+			vuePlugin(fsys, dir),
+			vueRuntimePlugin(fsys, dir),
 		}, c.transformer.Plugins()...),
 	})
 	if len(result.Errors) > 0 {
@@ -169,12 +187,12 @@ var jsxTemplate string
 
 var jsxGenerator = gotemplate.MustParse("jsx.gotext", jsxTemplate)
 
-// Generate the jsx entry file: bud/view/$page.jsx
+// Generate the jsx entry file: bud/view/$page.{jsx,tsx}
 func jsxPlugin(osfs fs.FS, dir string) esbuild.Plugin {
 	return esbuild.Plugin{
 		Name: "jsx",
 		Setup: func(epb esbuild.PluginBuild) {
-			epb.OnResolve(esbuild.OnResolveOptions{Filter: `^\./bud/view/.*\.jsx$`}, func(args esbuild.OnResolveArgs) (result esbuild.OnResolveResult, err error) {
+			epb.OnResolve(esbuild.OnResolveOptions{Filter: `^\./bud/view/.*\.(jsx|tsx)$`}, func(args esbuild.OnResolveArgs) (result esbuild.OnResolveResult, err error) {
 				result.Path = args.Path
 				result.Namespace = "jsx"
 				return result, nil
@@ -198,7 +216,7 @@ func jsxPlugin(osfs fs.FS, dir string) esbuild.Plugin {
 	}
 }
 
-//go:embed jsx.ts
+//go:embed jsx.js
 var jsxRuntime string
 
 // Generate the jsx runtime for the entry files
@@ -206,7 +224,7 @@ func jsxRuntimePlugin(osfs fs.FS, dir string) esbuild.Plugin {
 	return esbuild.Plugin{
 		Name: "jsx_runtime",
 		Setup: func(epb esbuild.PluginBuild) {
-			epb.OnResolve(esbuild.OnResolveOptions{Filter: `^\./bud/view/_jsx\.ts$`}, func(args esbuild.OnResolveArgs) (result esbuild.OnResolveResult, err error) {
+			epb.OnResolve(esbuild.OnResolveOptions{Filter: `^\./bud/view/_jsx\.js$`}, func(args esbuild.OnResolveArgs) (result esbuild.OnResolveResult, err error) {
 				result.Path = args.Path
 				result.Namespace = "jsx_runtime"
 				return result, nil
@@ -214,7 +232,7 @@ func jsxRuntimePlugin(osfs fs.FS, dir string) esbuild.Plugin {
 			epb.OnLoad(esbuild.OnLoadOptions{Filter: `.*`, Namespace: "jsx_runtime"}, func(args esbuild.OnLoadArgs) (result esbuild.OnLoadResult, err error) {
 				result.ResolveDir = dir
 				result.Contents = &jsxRuntime
-				result.Loader = esbuild.LoaderTS
+				result.Loader = esbuild.LoaderJS
 				return result, nil
 			})
 		},
@@ -225,8 +243,8 @@ func jsxTransformPlugin(osfs fs.FS, dir string) esbuild.Plugin {
 	return esbuild.Plugin{
 		Name: "jsx_transform",
 		Setup: func(epb esbuild.PluginBuild) {
-			// Load jsx files. Add import if not present
-			epb.OnLoad(esbuild.OnLoadOptions{Filter: `\.jsx$`}, func(args esbuild.OnLoadArgs) (result esbuild.OnLoadResult, err error) {
+			// Load jsx and tsx files. Add import if not present
+			epb.OnLoad(esbuild.OnLoadOptions{Filter: `\.(jsx|tsx)$`}, func(args esbuild.OnLoadArgs) (result esbuild.OnLoadResult, err error) {
 				code, err := os.ReadFile(args.Path)
 				if err != nil {
 					return result, err
@@ -236,6 +254,9 @@ func jsxTransformPlugin(osfs fs.FS, dir string) esbuild.Plugin {
 				result.ResolveDir = filepath.Dir(args.Path)
 				result.Contents = &contents
 				result.Loader = esbuild.LoaderJSX
+				if filepath.Ext(args.Path) == ".tsx" {
+					result.Loader = esbuild.LoaderTSX
+				}
 				return result, nil
 			})
 		},
@@ -298,3 +319,97 @@ func svelteRuntimePlugin(osfs fs.FS, dir string) esbuild.Plugin {
 		},
 	}
 }
+
+//go:embed vue.gotext
+var vueTemplate string
+
+var vueGenerator = gotemplate.MustParse("vue.gotext", vueTemplate)
+
+// Generate the vue entry file: bud/view/$page.vue
+func vuePlugin(osfs fs.FS, dir string) esbuild.Plugin {
+	return esbuild.Plugin{
+		Name: "vue",
+		Setup: func(epb esbuild.PluginBuild) {
+			epb.OnResolve(esbuild.OnResolveOptions{Filter: `^\./bud/view/.*\.vue$`}, func(args esbuild.OnResolveArgs) (result esbuild.OnResolveResult, err error) {
+				result.Path = args.Path
+				result.Namespace = "vue"
+				return result, nil
+			})
+			epb.OnLoad(esbuild.OnLoadOptions{Filter: `.*`, Namespace: "vue"}, func(args esbuild.OnLoadArgs) (result esbuild.OnLoadResult, err error) {
+				view, err := entrypoint.FindByPage(osfs, strings.Trim(filepath.Clean(args.Path), "bud/"))
+				if err != nil {
+					return result, err
+				}
+				code, err := vueGenerator.Generate(view)
+				if err != nil {
+					return result, err
+				}
+				contents := string(code)
+				result.ResolveDir = dir
+				result.Contents = &contents
+				result.Loader = esbuild.LoaderJSX
+				return result, nil
+			})
+		},
+	}
+}
+
+//go:embed vue.js
+var vueRuntime string
+
+// Generate the vue runtime for the entry files
+func vueRuntimePlugin(osfs fs.FS, dir string) esbuild.Plugin {
+	return esbuild.Plugin{
+		Name: "vue_runtime",
+		Setup: func(epb esbuild.PluginBuild) {
+			epb.OnResolve(esbuild.OnResolveOptions{Filter: `^\./bud/view/_vue\.js$`}, func(args esbuild.OnResolveArgs) (result esbuild.OnResolveResult, err error) {
+				result.Path = args.Path
+				result.Namespace = "vue_runtime"
+				return result, nil
+			})
+			epb.OnLoad(esbuild.OnLoadOptions{Filter: `.*`, Namespace: "vue_runtime"}, func(args esbuild.OnLoadArgs) (result esbuild.OnLoadResult, err error) {
+				result.ResolveDir = dir
+				result.Contents = &vueRuntime
+				result.Loader = esbuild.LoaderJS
+				return result, nil
+			})
+		},
+	}
+}
+
+// Wrap islands imported by views, so they're hydrated on their own: e.g.
+// import Counter from "./Counter.island.svelte"
+func islandPlugin(osfs fs.FS, dir string) esbuild.Plugin {
+	return esbuild.Plugin{
+		Name: "island",
+		Setup: func(epb esbuild.PluginBuild) {
+			epb.OnResolve(esbuild.OnResolveOptions{Filter: `\.island\.svelte$`}, func(args esbuild.OnResolveArgs) (result esbuild.OnResolveResult, err error) {
+				// The wrapper imports the island itself
+				if args.Namespace == "island" || !strings.HasPrefix(args.Path, ".") {
+					return result, nil
+				}
+				result.Path = filepath.Join(args.ResolveDir, args.Path)
+				result.Namespace = "island"
+				return result, nil
+			})
+			epb.OnLoad(esbuild.OnLoadOptions{Filter: `.*`, Namespace: "island"}, func(args esbuild.OnLoadArgs) (result esbuild.OnLoadResult, err error) {
+				rel, err := filepath.Rel(dir, args.Path)
+				if err != nil {
+					return result, err
+				}
+				island, err := entrypoint.FindIsland(osfs, filepath.ToSlash(rel))
+				if err != nil {
+					return result, err
+				}
+				contents := fmt.Sprintf(`import { island } from "./bud/view/_svelte.js"
+import Component from %q
+export default island(Component, "/%s")
+`, args.Path, island.Client)
+				result.ResolveDir = dir
+				result.Contents = &contents
+				result.Loader = esbuild.LoaderJS
+				return result, nil
+			})
+		},
+	}
+}
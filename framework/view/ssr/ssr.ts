@@ -11,7 +11,7 @@ type Response = {
   body: string
 }
 
-export function renderHTML(input: Input): Response {
+export function renderHTML(input: Input): Response | Promise<Response> {
   // Handle the missing view
   if (!input.view) {
     return {
@@ -23,6 +23,26 @@ export function renderHTML(input: Input): Response {
   return input.view({ props: input.props, context: input.context })
 }
 
+type Chunk = {
+  status?: number
+  headers?: Record<string, string>
+  body?: string
+  tail?: boolean
+}
+
+// Stream the view in chunks. Views that can't stream are written as a single
+// chunk. Views that render asynchronously return a promise.
+export function streamHTML(input: Input, write: (chunk: Chunk) => void): void | Promise<void> {
+  if (input.view && input.view.stream) {
+    return input.view.stream({ props: input.props, context: input.context }, write)
+  }
+  const response = renderHTML(input)
+  if (response instanceof Promise) {
+    return response.then(write)
+  }
+  write(response)
+}
+
 function fallback(err: Error) {
   return `fallback error: ${err.message}`
 }
@@ -94,7 +94,7 @@ var require_jsesc = __commonJS({
     var regexWhitespace = /[\xA0\u1680\u2000-\u200A\u2028\u2029\u202F\u205F\u3000]/;
     var escapeEverythingRegex = /([\uD800-\uDBFF][\uDC00-\uDFFF])|([\uD800-\uDFFF])|(['"`])|[^]/g;
     var escapeNonAsciiRegex = /([\uD800-\uDBFF][\uDC00-\uDFFF])|([\uD800-\uDFFF])|(['"`])|[^ !#-&\(-\[\]-_a-~]/g;
-    var jsesc2 = (argument, options) => {
+    var jsesc22 = (argument, options) => {
       const increaseIndentation = () => {
         oldIndent = indent;
         ++options.indentLevel;
@@ -151,19 +151,19 @@ var require_jsesc = __commonJS({
             options.__inline1__ = true;
             options.__inline2__ = false;
           }
-          return "new Map(" + jsesc2(Array.from(argument), options) + ")";
+          return "new Map(" + jsesc22(Array.from(argument), options) + ")";
         }
         if (isSet(argument)) {
           if (argument.size == 0) {
             return "new Set()";
           }
-          return "new Set(" + jsesc2(Array.from(argument), options) + ")";
+          return "new Set(" + jsesc22(Array.from(argument), options) + ")";
         }
         if (isBuffer(argument)) {
           if (argument.length == 0) {
             return "Buffer.from([])";
           }
-          return "Buffer.from(" + jsesc2(Array.from(argument), options) + ")";
+          return "Buffer.from(" + jsesc22(Array.from(argument), options) + ")";
         }
         if (isArray(argument)) {
           result = [];
@@ -180,7 +180,7 @@ var require_jsesc = __commonJS({
             if (inline2) {
               options.__inline2__ = false;
             }
-            result.push((compact || inline2 ? "" : indent) + jsesc2(value, options));
+            result.push((compact || inline2 ? "" : indent) + jsesc22(value, options));
           });
           if (isEmpty) {
             return "[]";
@@ -220,7 +220,7 @@ var require_jsesc = __commonJS({
           increaseIndentation();
           forOwn(argument, (key, value) => {
             isEmpty = false;
-            result.push((compact ? "" : indent) + jsesc2(key, options) + ":" + (compact ? "" : " ") + jsesc2(value, options));
+            result.push((compact ? "" : indent) + jsesc22(key, options) + ":" + (compact ? "" : " ") + jsesc22(value, options));
           });
           if (isEmpty) {
             return "{}";
@@ -277,65 +277,80 @@ var require_jsesc = __commonJS({
       }
       return result;
     };
-    jsesc2.version = "3.0.2";
-    module.exports = jsesc2;
+    jsesc22.version = "3.0.2";
+    module.exports = jsesc22;
   }
 });
 
 // svelte.ts
+var import_jsesc2 = __toESM(require_jsesc());
+
+// html.ts
 var import_jsesc = __toESM(require_jsesc());
-function createView(view) {
-  view.layout = view.layout || defaultLayout;
-  return function({ props, context }) {
-    const page = view.page.render(props);
-    let css = page.css.code;
-    let html = page.html;
-    let head = page.head;
-    const hydrate = (0, import_jsesc.default)(props, { isScriptContext: true, json: true });
-    const layout = view.layout.render(props, {
-      head: function() {
-        return `
-          ${head}
-          <style>#bud{}${css}</style>
-          <script id="bud_props" type="text/template" defer>${hydrate}<\/script>
-          <script type="module" src="${view.client}" defer><\/script>
-        `;
-      },
-      default: function() {
-        return '<div id="bud_target">' + html + "</div>";
-      }
-    });
-    html = layout.html.replace("#bud{}", layout.css.code);
-    return {
-      status: 200,
-      headers: {
-        "Content-Type": "text/html"
-      },
-      body: html
-    };
-  };
+var bodyMarker = "<!--bud_body-->";
+function hydrateScripts(client, props) {
+  const hydrate = (0, import_jsesc.default)(props, { isScriptContext: true, json: true });
+  return `<script id="bud_props" type="text/template" defer>${hydrate}<\/script>
+          <script type="module" src="${client}" defer><\/script>`;
 }
-var defaultLayout = {
-  render(props, slots) {
-    return {
-      css: {
-        code: ""
-      },
-      head: "",
-      html: `
-        <!doctype html>
-        <html>
-          <head>
-            <meta charset="utf-8"/>
-            <style>${defaultCSS}</style>
-            ${slots.head(props)}
-          </head>
-          <body>${slots.default(props)}</body>
-        </html>
-      `
-    };
+function renderHead(head) {
+  if (!head)
+    return "";
+  let html = "";
+  if (head.title) {
+    html += `<title>${escapeHTML(head.title)}</title>`;
   }
-};
+  for (let meta of head.meta || []) {
+    const key = meta.property ? `property="${escapeHTML(meta.property)}"` : `name="${escapeHTML(meta.name || "")}"`;
+    html += `<meta ${key} content="${escapeHTML(meta.content)}">`;
+  }
+  for (let link of head.links || []) {
+    html += `<link rel="${escapeHTML(link.rel)}" href="${escapeHTML(link.href)}"`;
+    if (link.type)
+      html += ` type="${escapeHTML(link.type)}"`;
+    if (link.hreflang)
+      html += ` hreflang="${escapeHTML(link.hreflang)}"`;
+    html += ">";
+  }
+  return html;
+}
+function withoutOverrides(head, html) {
+  if (!head)
+    return html;
+  if (head.title) {
+    html = html.replace(/<title\b[^>]*>[\s\S]*?<\/title>/g, "");
+  }
+  const names = /* @__PURE__ */ new Set();
+  const properties = /* @__PURE__ */ new Set();
+  for (let meta of head.meta || []) {
+    if (meta.property)
+      properties.add(meta.property);
+    else if (meta.name)
+      names.add(meta.name);
+  }
+  html = html.replace(/<meta\b[^>]*>/g, (tag) => {
+    const name = attribute(tag, "name");
+    const property = attribute(tag, "property");
+    if (name !== void 0 && names.has(name))
+      return "";
+    if (property !== void 0 && properties.has(property))
+      return "";
+    return tag;
+  });
+  if ((head.links || []).some((link) => link.rel === "canonical")) {
+    html = html.replace(/<link\b[^>]*>/g, (tag) => attribute(tag, "rel") === "canonical" ? "" : tag);
+  }
+  return html;
+}
+function attribute(tag, name) {
+  const match = tag.match(new RegExp(`\\s${name}=(?:"([^"]*)"|'([^']*)'|([^\\s>]+))`, "i"));
+  if (!match)
+    return void 0;
+  return match[1] ?? match[2] ?? match[3];
+}
+function escapeHTML(text) {
+  return String(text).replace(/&/g, "&amp;").replace(/</g, "&lt;").replace(/>/g, "&gt;").replace(/"/g, "&quot;").replace(/'/g, "&#39;");
+}
 var defaultCSS = `
 /*! modern-normalize v1.1.0 | MIT License | https://github.com/sindresorhus/modern-normalize */
 
@@ -623,6 +638,165 @@ summary {
 	display: list-item;
 }
 `;
+
+// svelte.ts
+function createView(view) {
+  const layout = view.layout || defaultLayout;
+  const error = view.error || defaultError;
+  function renderView({ props, context }) {
+    props = props || {};
+    const pageError = props.bud_error;
+    const rendered = renderPage(view, error, props);
+    const head = `
+          ${renderHead(props.bud_head)}${withoutOverrides(props.bud_head, rendered.head)}
+          <style>#bud{}${rendered.css}</style>
+          ${scripts(view, props, rendered.islands)}
+        `;
+    const body = '<div id="bud_target">' + rendered.html + "</div>";
+    return {
+      status: pageError ? pageError.status : 200,
+      headers: {
+        "Content-Type": "text/html"
+      },
+      body: renderLayout(layout, props, head, body)
+    };
+  }
+  renderView.stream = function({ props, context }, write) {
+    props = props || {};
+    const pageError = props.bud_error;
+    const head = `
+          ${renderHead(props.bud_head)}
+          <style>#bud{}</style>
+        `;
+    const html = renderLayout(layout, props, head, bodyMarker);
+    const index = html.indexOf(bodyMarker);
+    write({
+      status: pageError ? pageError.status : 200,
+      headers: {
+        "Content-Type": "text/html"
+      },
+      body: index < 0 ? html : html.slice(0, index)
+    });
+    const rendered = renderPage(view, error, props);
+    write({
+      body: withoutOverrides(props.bud_head, rendered.head) + (rendered.css ? `<style>${rendered.css}</style>` : "") + '<div id="bud_target">' + rendered.html + "</div>" + scripts(view, props, rendered.islands)
+    });
+    write({
+      body: index < 0 ? "" : html.slice(index + bodyMarker.length),
+      tail: true
+    });
+  };
+  return renderView;
+}
+function scripts(view, props, islands2) {
+  if (islands2.length > 0) {
+    return islands2.map((client) => `<script type="module" src="${client}" defer><\/script>`).join("");
+  }
+  return hydrateScripts(view.client, props);
+}
+function renderPage(view, error, props) {
+  const pageError = props.bud_error;
+  islands = /* @__PURE__ */ new Set();
+  try {
+    const rendered = pageError ? render(error, { ...props, error: pageError }) : renderFrames(view.page, view.frames, props);
+    return { ...rendered, islands: Array.from(islands) };
+  } finally {
+    islands = void 0;
+  }
+}
+var islands;
+var insideIsland = false;
+function island(Component, client) {
+  return {
+    $$render(result, props, bindings, slots, context) {
+      if (!islands || insideIsland) {
+        return Component.$$render(result, props, bindings, slots, context);
+      }
+      islands.add(client);
+      insideIsland = true;
+      let html;
+      try {
+        html = Component.$$render(result, props, bindings, {}, context);
+      } finally {
+        insideIsland = false;
+      }
+      const json = (0, import_jsesc2.default)(props || {}, { json: true });
+      return `<bud-island client="${escapeHTML(client)}" props="${escapeHTML(json)}" style="display:contents">${html}</bud-island>`;
+    }
+  };
+}
+function renderLayout(layout, props, head, body) {
+  const page = layout.render(props, {
+    $$slots: {
+      head: () => head,
+      default: () => body
+    }
+  });
+  let html = page.html;
+  if (!html.includes("<style>#bud{}")) {
+    html = html.includes("</head>") ? html.replace("</head>", head + "</head>") : head + html;
+  }
+  const layoutHead = withoutOverrides(props.bud_head, page.head);
+  return html.replace("<style>#bud{}", layoutHead + "<style>" + page.css.code);
+}
+function renderFrames(page, frames, props) {
+  let rendered = render(page, props);
+  for (let i = frames.length - 1; i >= 0; i--) {
+    const inner = rendered;
+    const frame = render(frames[i], props, { default: () => inner.html });
+    rendered = {
+      html: frame.html,
+      css: join(frame.css, inner.css),
+      head: frame.head + inner.head
+    };
+  }
+  return rendered;
+}
+function render(component, props, slots = {}) {
+  const result = component.render(props, { $$slots: slots });
+  return {
+    html: result.html,
+    css: result.css.code,
+    head: result.head
+  };
+}
+function join(...css) {
+  return css.filter(Boolean).join("\n");
+}
+var defaultLayout = {
+  render(props, { $$slots }) {
+    return {
+      css: {
+        code: ""
+      },
+      head: "",
+      html: `
+        <!doctype html>
+        <html>
+          <head>
+            <meta charset="utf-8"/>
+            <style>${defaultCSS}</style>
+            ${$$slots.head(props)}
+          </head>
+          <body>${$$slots.default(props)}</body>
+        </html>
+      `
+    };
+  }
+};
+var defaultError = {
+  render(props) {
+    const { status, message } = props.error;
+    return {
+      css: {
+        code: ""
+      },
+      head: `<title>${status}</title>`,
+      html: `<h1>${status}</h1><p>${escapeHTML(message)}</p>`
+    };
+  }
+};
 export {
-  createView
+  createView,
+  island
 };
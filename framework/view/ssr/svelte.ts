@@ -1,4 +1,14 @@
-import jsesc from 'jsesc'
+import jsesc from "jsesc"
+import {
+  Chunk,
+  PageError,
+  bodyMarker,
+  defaultCSS,
+  escapeHTML,
+  hydrateScripts,
+  renderHead,
+  withoutOverrides,
+} from "./html"
 
 type View = {
   page: any
@@ -8,46 +18,184 @@ type View = {
   client: string
 }
 
-// TODO:
-// - Test custom layouts
-// - Support frames
-// - Support default errors
-// - Support custom errors
+type Rendered = {
+  html: string
+  css: string
+  head: string
+}
+
+// Rendered page along with the clients of the islands it rendered
+type Page = Rendered & {
+  islands: string[]
+}
+
 export function createView(view: View) {
-  view.layout = view.layout || defaultLayout
-  return function ({ props, context }) {
-    const page = view.page.render(props)
-    let css = page.css.code
-    let html = page.html
-    let head = page.head
-    // Render the layout
-    const hydrate = jsesc(props, { isScriptContext: true, json: true })
-    const layout = view.layout.render(props, {
-      head: function () {
-        return `
-          ${head}
-          <style>#bud{}${css}</style>
-          <script id="bud_props" type="text/template" defer>${hydrate}</script>
-          <script type="module" src="${view.client}" defer></script>
+  const layout = view.layout || defaultLayout
+  const error = view.error || defaultError
+  // Render the view into a single response
+  function renderView({ props, context }) {
+    props = props || {}
+    const pageError: PageError | undefined = props.bud_error
+    const rendered = renderPage(view, error, props)
+    const head = `
+          ${renderHead(props.bud_head)}${withoutOverrides(props.bud_head, rendered.head)}
+          <style>#bud{}${rendered.css}</style>
+          ${scripts(view, props, rendered.islands)}
         `
-      },
-      default: function () {
-        return '<div id="bud_target">' + html + "</div>"
-      },
-    })
-    html = layout.html.replace("#bud{}", layout.css.code)
+    const body = '<div id="bud_target">' + rendered.html + "</div>"
     return {
-      status: 200,
+      status: pageError ? pageError.status : 200,
       headers: {
         "Content-Type": "text/html",
       },
-      body: html,
+      body: renderLayout(layout, props, head, body),
+    }
+  }
+  // Stream the view, flushing the layout before rendering the page. The
+  // controller's head is known up front, but the page's head and styles are
+  // written at the start of the body, because the document's head has already
+  // been sent. The scripts are written after the page, once the islands it
+  // renders are known.
+  renderView.stream = function ({ props, context }, write: (chunk: Chunk) => void) {
+    props = props || {}
+    const pageError: PageError | undefined = props.bud_error
+    const head = `
+          ${renderHead(props.bud_head)}
+          <style>#bud{}</style>
+        `
+    const html = renderLayout(layout, props, head, bodyMarker)
+    const index = html.indexOf(bodyMarker)
+    write({
+      status: pageError ? pageError.status : 200,
+      headers: {
+        "Content-Type": "text/html",
+      },
+      body: index < 0 ? html : html.slice(0, index),
+    })
+    const rendered = renderPage(view, error, props)
+    write({
+      body:
+        withoutOverrides(props.bud_head, rendered.head) +
+        (rendered.css ? `<style>${rendered.css}</style>` : "") +
+        '<div id="bud_target">' +
+        rendered.html +
+        "</div>" +
+        scripts(view, props, rendered.islands),
+    })
+    write({
+      body: index < 0 ? "" : html.slice(index + bodyMarker.length),
+      tail: true,
+    })
+  }
+  return renderView
+}
+
+// Scripts that hydrate the page in the browser. Pages that render islands are
+// static, apart from their islands, so only the islands are hydrated.
+function scripts(view: View, props: any, islands: string[]): string {
+  if (islands.length > 0) {
+    return islands
+      .map((client) => `<script type="module" src="${client}" defer></script>`)
+      .join("")
+  }
+  return hydrateScripts(view.client, props)
+}
+
+// Errors render the closest error page in place of the page and its frames
+function renderPage(view: View, error: any, props: any): Page {
+  const pageError: PageError | undefined = props.bud_error
+  islands = new Set()
+  try {
+    const rendered = pageError
+      ? render(error, { ...props, error: pageError })
+      : renderFrames(view.page, view.frames, props)
+    return { ...rendered, islands: Array.from(islands) }
+  } finally {
+    islands = undefined
+  }
+}
+
+// Clients of the islands rendered by the current page
+let islands: Set<string> | undefined
+
+// Islands within an island are hydrated along with the outer island
+let insideIsland = false
+
+// island wraps an interactive component, so it can be hydrated on its own
+// within an otherwise static page. The island's props are serialized into
+// its wrapper element, so they need to be JSON. Slot content isn't available
+// in the browser, so it's not rendered.
+export function island(Component: any, client: string) {
+  return {
+    $$render(result: any, props: any, bindings: any, slots: any, context: any) {
+      // Islands in layouts aren't hydrated
+      if (!islands || insideIsland) {
+        return Component.$$render(result, props, bindings, slots, context)
+      }
+      islands.add(client)
+      insideIsland = true
+      let html: string
+      try {
+        html = Component.$$render(result, props, bindings, {}, context)
+      } finally {
+        insideIsland = false
+      }
+      const json = jsesc(props || {}, { json: true })
+      return `<bud-island client="${escapeHTML(client)}" props="${escapeHTML(json)}" style="display:contents">${html}</bud-island>`
+    },
+  }
+}
+
+// Render the layout around the head and body
+function renderLayout(layout: any, props: any, head: string, body: string): string {
+  const page = layout.render(props, {
+    $$slots: {
+      head: () => head,
+      default: () => body,
+    },
+  })
+  let html = page.html
+  // Custom layouts may leave out the head slot
+  if (!html.includes("<style>#bud{}")) {
+    html = html.includes("</head>")
+      ? html.replace("</head>", head + "</head>")
+      : head + html
+  }
+  const layoutHead = withoutOverrides(props.bud_head, page.head)
+  return html.replace("<style>#bud{}", layoutHead + "<style>" + page.css.code)
+}
+
+// Render the page within its frames, from the innermost frame out
+function renderFrames(page: any, frames: any[], props: any): Rendered {
+  let rendered = render(page, props)
+  for (let i = frames.length - 1; i >= 0; i--) {
+    const inner = rendered
+    const frame = render(frames[i], props, { default: () => inner.html })
+    rendered = {
+      html: frame.html,
+      css: join(frame.css, inner.css),
+      head: frame.head + inner.head,
     }
   }
+  return rendered
+}
+
+// Render a component with its slots
+function render(component: any, props: any, slots = {}): Rendered {
+  const result = component.render(props, { $$slots: slots })
+  return {
+    html: result.html,
+    css: result.css.code,
+    head: result.head,
+  }
+}
+
+function join(...css: string[]): string {
+  return css.filter(Boolean).join("\n")
 }
 
 const defaultLayout = {
-  render(props, slots) {
+  render(props, { $$slots }) {
     return {
       css: {
         code: "",
@@ -59,301 +207,25 @@ const defaultLayout = {
           <head>
             <meta charset="utf-8"/>
             <style>${defaultCSS}</style>
-            ${slots.head(props)}
+            ${$$slots.head(props)}
           </head>
-          <body>${slots.default(props)}</body>
+          <body>${$$slots.default(props)}</body>
         </html>
       `,
     }
   },
 }
 
-// Default CSS is modern-normalize by Sindre Sorhus
-// https://raw.githubusercontent.com/sindresorhus/modern-normalize/v1.1.0/modern-normalize.css
-const defaultCSS = `
-/*! modern-normalize v1.1.0 | MIT License | https://github.com/sindresorhus/modern-normalize */
-
-/*
-Document
-========
-*/
-
-/**
-Use a better box model (opinionated).
-*/
-
-*,
-::before,
-::after {
-	box-sizing: border-box;
-}
-
-/**
-1. Correct the line height in all browsers.
-2. Prevent adjustments of font size after orientation changes in iOS.
-3. Use a more readable tab size (opinionated).
-*/
-
-html {
-	line-height: 1.15; /* 1 */
-	-webkit-text-size-adjust: 100%; /* 2 */
-	-moz-tab-size: 4; /* 3 */
-	tab-size: 4; /* 3 */
-}
-
-/*
-Sections
-========
-*/
-
-/**
-1. Remove the margin in all browsers.
-2. Improve consistency of default fonts in all browsers. (https://github.com/sindresorhus/modern-normalize/issues/3)
-*/
-
-body {
-	margin: 0; /* 1 */
-	font-family:
-		system-ui,
-		-apple-system, /* Firefox supports this but not yet system-ui */
-		'Segoe UI',
-		Roboto,
-		Helvetica,
-		Arial,
-		sans-serif,
-		'Apple Color Emoji',
-		'Segoe UI Emoji'; /* 2 */
-}
-
-/*
-Grouping content
-================
-*/
-
-/**
-1. Add the correct height in Firefox.
-2. Correct the inheritance of border color in Firefox. (https://bugzilla.mozilla.org/show_bug.cgi?id=190655)
-*/
-
-hr {
-	height: 0; /* 1 */
-	color: inherit; /* 2 */
-}
-
-/*
-Text-level semantics
-====================
-*/
-
-/**
-Add the correct text decoration in Chrome, Edge, and Safari.
-*/
-
-abbr[title] {
-	text-decoration: underline dotted;
-}
-
-/**
-Add the correct font weight in Edge and Safari.
-*/
-
-b,
-strong {
-	font-weight: bolder;
-}
-
-/**
-1. Improve consistency of default fonts in all browsers. (https://github.com/sindresorhus/modern-normalize/issues/3)
-2. Correct the odd 'em' font sizing in all browsers.
-*/
-
-code,
-kbd,
-samp,
-pre {
-	font-family:
-		ui-monospace,
-		SFMono-Regular,
-		Consolas,
-		'Liberation Mono',
-		Menlo,
-		monospace; /* 1 */
-	font-size: 1em; /* 2 */
-}
-
-/**
-Add the correct font size in all browsers.
-*/
-
-small {
-	font-size: 80%;
-}
-
-/**
-Prevent 'sub' and 'sup' elements from affecting the line height in all browsers.
-*/
-
-sub,
-sup {
-	font-size: 75%;
-	line-height: 0;
-	position: relative;
-	vertical-align: baseline;
-}
-
-sub {
-	bottom: -0.25em;
-}
-
-sup {
-	top: -0.5em;
-}
-
-/*
-Tabular data
-============
-*/
-
-/**
-1. Remove text indentation from table contents in Chrome and Safari. (https://bugs.chromium.org/p/chromium/issues/detail?id=999088, https://bugs.webkit.org/show_bug.cgi?id=201297)
-2. Correct table border color inheritance in all Chrome and Safari. (https://bugs.chromium.org/p/chromium/issues/detail?id=935729, https://bugs.webkit.org/show_bug.cgi?id=195016)
-*/
-
-table {
-	text-indent: 0; /* 1 */
-	border-color: inherit; /* 2 */
-}
-
-/*
-Forms
-=====
-*/
-
-/**
-1. Change the font styles in all browsers.
-2. Remove the margin in Firefox and Safari.
-*/
-
-button,
-input,
-optgroup,
-select,
-textarea {
-	font-family: inherit; /* 1 */
-	font-size: 100%; /* 1 */
-	line-height: 1.15; /* 1 */
-	margin: 0; /* 2 */
-}
-
-/**
-Remove the inheritance of text transform in Edge and Firefox.
-*/
-
-button,
-select {
-	text-transform: none;
-}
-
-/**
-Correct the inability to style clickable types in iOS and Safari.
-*/
-
-button,
-[type='button'],
-[type='reset'],
-[type='submit'] {
-	-webkit-appearance: button;
-}
-
-/**
-Remove the inner border and padding in Firefox.
-*/
-
-::-moz-focus-inner {
-	border-style: none;
-	padding: 0;
-}
-
-/**
-Restore the focus styles unset by the previous rule.
-*/
-
-:-moz-focusring {
-	outline: 1px dotted ButtonText;
-}
-
-/**
-Remove the additional ':invalid' styles in Firefox.
-See: https://github.com/mozilla/gecko-dev/blob/2f9eacd9d3d995c937b4251a5557d95d494c9be1/layout/style/res/forms.css#L728-L737
-*/
-
-:-moz-ui-invalid {
-	box-shadow: none;
-}
-
-/**
-Remove the padding so developers are not caught out when they zero out 'fieldset' elements in all browsers.
-*/
-
-legend {
-	padding: 0;
-}
-
-/**
-Add the correct vertical alignment in Chrome and Firefox.
-*/
-
-progress {
-	vertical-align: baseline;
-}
-
-/**
-Correct the cursor style of increment and decrement buttons in Safari.
-*/
-
-::-webkit-inner-spin-button,
-::-webkit-outer-spin-button {
-	height: auto;
-}
-
-/**
-1. Correct the odd appearance in Chrome and Safari.
-2. Correct the outline style in Safari.
-*/
-
-[type='search'] {
-	-webkit-appearance: textfield; /* 1 */
-	outline-offset: -2px; /* 2 */
-}
-
-/**
-Remove the inner padding in Chrome and Safari on macOS.
-*/
-
-::-webkit-search-decoration {
-	-webkit-appearance: none;
-}
-
-/**
-1. Correct the inability to style clickable types in iOS and Safari.
-2. Change font properties to 'inherit' in Safari.
-*/
-
-::-webkit-file-upload-button {
-	-webkit-appearance: button; /* 1 */
-	font: inherit; /* 2 */
-}
-
-/*
-Interactive
-===========
-*/
-
-/*
-Add the correct display in Chrome and Safari.
-*/
-
-summary {
-	display: list-item;
+// Default error page when there's no error.svelte
+const defaultError = {
+  render(props) {
+    const { status, message } = props.error as PageError
+    return {
+      css: {
+        code: "",
+      },
+      head: `<title>${status}</title>`,
+      html: `<h1>${status}</h1><p>${escapeHTML(message)}</p>`,
+    }
+  },
 }
-`
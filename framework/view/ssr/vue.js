@@ -0,0 +1,776 @@
+var __create = Object.create;
+var __defProp = Object.defineProperty;
+var __getOwnPropDesc = Object.getOwnPropertyDescriptor;
+var __getOwnPropNames = Object.getOwnPropertyNames;
+var __getProtoOf = Object.getPrototypeOf;
+var __hasOwnProp = Object.prototype.hasOwnProperty;
+var __markAsModule = (target) => __defProp(target, "__esModule", { value: true });
+var __commonJS = (cb, mod) => function __require() {
+  return mod || (0, cb[__getOwnPropNames(cb)[0]])((mod = { exports: {} }).exports, mod), mod.exports;
+};
+var __reExport = (target, module, copyDefault, desc) => {
+  if (module && typeof module === "object" || typeof module === "function") {
+    for (let key of __getOwnPropNames(module))
+      if (!__hasOwnProp.call(target, key) && (copyDefault || key !== "default"))
+        __defProp(target, key, { get: () => module[key], enumerable: !(desc = __getOwnPropDesc(module, key)) || desc.enumerable });
+  }
+  return target;
+};
+var __toESM = (module, isNodeMode) => {
+  return __reExport(__markAsModule(__defProp(module != null ? __create(__getProtoOf(module)) : {}, "default", !isNodeMode && module && module.__esModule ? { get: () => module.default, enumerable: true } : { value: module, enumerable: true })), module);
+};
+
+// ../../../node_modules/jsesc/jsesc.js
+var require_jsesc = __commonJS({
+  "../../../node_modules/jsesc/jsesc.js"(exports, module) {
+    "use strict";
+    var object = {};
+    var hasOwnProperty = object.hasOwnProperty;
+    var forOwn = (object2, callback) => {
+      for (const key in object2) {
+        if (hasOwnProperty.call(object2, key)) {
+          callback(key, object2[key]);
+        }
+      }
+    };
+    var extend = (destination, source) => {
+      if (!source) {
+        return destination;
+      }
+      forOwn(source, (key, value) => {
+        destination[key] = value;
+      });
+      return destination;
+    };
+    var forEach = (array, callback) => {
+      const length = array.length;
+      let index = -1;
+      while (++index < length) {
+        callback(array[index]);
+      }
+    };
+    var fourHexEscape = (hex) => {
+      return "\\u" + ("0000" + hex).slice(-4);
+    };
+    var hexadecimal = (code, lowercase) => {
+      let hexadecimal2 = code.toString(16);
+      if (lowercase)
+        return hexadecimal2;
+      return hexadecimal2.toUpperCase();
+    };
+    var toString = object.toString;
+    var isArray = Array.isArray;
+    var isBuffer = (value) => {
+      return typeof Buffer === "function" && Buffer.isBuffer(value);
+    };
+    var isObject = (value) => {
+      return toString.call(value) == "[object Object]";
+    };
+    var isString = (value) => {
+      return typeof value == "string" || toString.call(value) == "[object String]";
+    };
+    var isNumber = (value) => {
+      return typeof value == "number" || toString.call(value) == "[object Number]";
+    };
+    var isFunction = (value) => {
+      return typeof value == "function";
+    };
+    var isMap = (value) => {
+      return toString.call(value) == "[object Map]";
+    };
+    var isSet = (value) => {
+      return toString.call(value) == "[object Set]";
+    };
+    var singleEscapes = {
+      "\\": "\\\\",
+      "\b": "\\b",
+      "\f": "\\f",
+      "\n": "\\n",
+      "\r": "\\r",
+      "	": "\\t"
+    };
+    var regexSingleEscape = /[\\\b\f\n\r\t]/;
+    var regexDigit = /[0-9]/;
+    var regexWhitespace = /[\xA0\u1680\u2000-\u200A\u2028\u2029\u202F\u205F\u3000]/;
+    var escapeEverythingRegex = /([\uD800-\uDBFF][\uDC00-\uDFFF])|([\uD800-\uDFFF])|(['"`])|[^]/g;
+    var escapeNonAsciiRegex = /([\uD800-\uDBFF][\uDC00-\uDFFF])|([\uD800-\uDFFF])|(['"`])|[^ !#-&\(-\[\]-_a-~]/g;
+    var jsesc2 = (argument, options) => {
+      const increaseIndentation = () => {
+        oldIndent = indent;
+        ++options.indentLevel;
+        indent = options.indent.repeat(options.indentLevel);
+      };
+      const defaults = {
+        "escapeEverything": false,
+        "minimal": false,
+        "isScriptContext": false,
+        "quotes": "single",
+        "wrap": false,
+        "es6": false,
+        "json": false,
+        "compact": true,
+        "lowercaseHex": false,
+        "numbers": "decimal",
+        "indent": "	",
+        "indentLevel": 0,
+        "__inline1__": false,
+        "__inline2__": false
+      };
+      const json = options && options.json;
+      if (json) {
+        defaults.quotes = "double";
+        defaults.wrap = true;
+      }
+      options = extend(defaults, options);
+      if (options.quotes != "single" && options.quotes != "double" && options.quotes != "backtick") {
+        options.quotes = "single";
+      }
+      const quote = options.quotes == "double" ? '"' : options.quotes == "backtick" ? "`" : "'";
+      const compact = options.compact;
+      const lowercaseHex = options.lowercaseHex;
+      let indent = options.indent.repeat(options.indentLevel);
+      let oldIndent = "";
+      const inline1 = options.__inline1__;
+      const inline2 = options.__inline2__;
+      const newLine = compact ? "" : "\n";
+      let result;
+      let isEmpty = true;
+      const useBinNumbers = options.numbers == "binary";
+      const useOctNumbers = options.numbers == "octal";
+      const useDecNumbers = options.numbers == "decimal";
+      const useHexNumbers = options.numbers == "hexadecimal";
+      if (json && argument && isFunction(argument.toJSON)) {
+        argument = argument.toJSON();
+      }
+      if (!isString(argument)) {
+        if (isMap(argument)) {
+          if (argument.size == 0) {
+            return "new Map()";
+          }
+          if (!compact) {
+            options.__inline1__ = true;
+            options.__inline2__ = false;
+          }
+          return "new Map(" + jsesc2(Array.from(argument), options) + ")";
+        }
+        if (isSet(argument)) {
+          if (argument.size == 0) {
+            return "new Set()";
+          }
+          return "new Set(" + jsesc2(Array.from(argument), options) + ")";
+        }
+        if (isBuffer(argument)) {
+          if (argument.length == 0) {
+            return "Buffer.from([])";
+          }
+          return "Buffer.from(" + jsesc2(Array.from(argument), options) + ")";
+        }
+        if (isArray(argument)) {
+          result = [];
+          options.wrap = true;
+          if (inline1) {
+            options.__inline1__ = false;
+            options.__inline2__ = true;
+          }
+          if (!inline2) {
+            increaseIndentation();
+          }
+          forEach(argument, (value) => {
+            isEmpty = false;
+            if (inline2) {
+              options.__inline2__ = false;
+            }
+            result.push((compact || inline2 ? "" : indent) + jsesc2(value, options));
+          });
+          if (isEmpty) {
+            return "[]";
+          }
+          if (inline2) {
+            return "[" + result.join(", ") + "]";
+          }
+          return "[" + newLine + result.join("," + newLine) + newLine + (compact ? "" : oldIndent) + "]";
+        } else if (isNumber(argument)) {
+          if (json) {
+            return JSON.stringify(argument);
+          }
+          if (useDecNumbers) {
+            return String(argument);
+          }
+          if (useHexNumbers) {
+            let hexadecimal2 = argument.toString(16);
+            if (!lowercaseHex) {
+              hexadecimal2 = hexadecimal2.toUpperCase();
+            }
+            return "0x" + hexadecimal2;
+          }
+          if (useBinNumbers) {
+            return "0b" + argument.toString(2);
+          }
+          if (useOctNumbers) {
+            return "0o" + argument.toString(8);
+          }
+        } else if (!isObject(argument)) {
+          if (json) {
+            return JSON.stringify(argument) || "null";
+          }
+          return String(argument);
+        } else {
+          result = [];
+          options.wrap = true;
+          increaseIndentation();
+          forOwn(argument, (key, value) => {
+            isEmpty = false;
+            result.push((compact ? "" : indent) + jsesc2(key, options) + ":" + (compact ? "" : " ") + jsesc2(value, options));
+          });
+          if (isEmpty) {
+            return "{}";
+          }
+          return "{" + newLine + result.join("," + newLine) + newLine + (compact ? "" : oldIndent) + "}";
+        }
+      }
+      const regex = options.escapeEverything ? escapeEverythingRegex : escapeNonAsciiRegex;
+      result = argument.replace(regex, (char, pair, lone, quoteChar, index, string) => {
+        if (pair) {
+          if (options.minimal)
+            return pair;
+          const first = pair.charCodeAt(0);
+          const second = pair.charCodeAt(1);
+          if (options.es6) {
+            const codePoint = (first - 55296) * 1024 + second - 56320 + 65536;
+            const hex2 = hexadecimal(codePoint, lowercaseHex);
+            return "\\u{" + hex2 + "}";
+          }
+          return fourHexEscape(hexadecimal(first, lowercaseHex)) + fourHexEscape(hexadecimal(second, lowercaseHex));
+        }
+        if (lone) {
+          return fourHexEscape(hexadecimal(lone.charCodeAt(0), lowercaseHex));
+        }
+        if (char == "\0" && !json && !regexDigit.test(string.charAt(index + 1))) {
+          return "\\0";
+        }
+        if (quoteChar) {
+          if (quoteChar == quote || options.escapeEverything) {
+            return "\\" + quoteChar;
+          }
+          return quoteChar;
+        }
+        if (regexSingleEscape.test(char)) {
+          return singleEscapes[char];
+        }
+        if (options.minimal && !regexWhitespace.test(char)) {
+          return char;
+        }
+        const hex = hexadecimal(char.charCodeAt(0), lowercaseHex);
+        if (json || hex.length > 2) {
+          return fourHexEscape(hex);
+        }
+        return "\\x" + ("00" + hex).slice(-2);
+      });
+      if (quote == "`") {
+        result = result.replace(/\$\{/g, "\\${");
+      }
+      if (options.isScriptContext) {
+        result = result.replace(/<\/(script|style)/gi, "<\\/$1").replace(/<!--/g, json ? "\\u003C!--" : "\\x3C!--");
+      }
+      if (options.wrap) {
+        result = quote + result + quote;
+      }
+      return result;
+    };
+    jsesc2.version = "3.0.2";
+    module.exports = jsesc2;
+  }
+});
+
+// vue.ts
+import { createSSRApp, h } from "vue";
+import { renderToString } from "vue/server-renderer";
+
+// html.ts
+var import_jsesc = __toESM(require_jsesc());
+var bodyMarker = "<!--bud_body-->";
+function hydrateScripts(client, props) {
+  const hydrate = (0, import_jsesc.default)(props, { isScriptContext: true, json: true });
+  return `<script id="bud_props" type="text/template" defer>${hydrate}<\/script>
+          <script type="module" src="${client}" defer><\/script>`;
+}
+function renderHead(head) {
+  if (!head)
+    return "";
+  let html = "";
+  if (head.title) {
+    html += `<title>${escapeHTML(head.title)}</title>`;
+  }
+  for (let meta of head.meta || []) {
+    const key = meta.property ? `property="${escapeHTML(meta.property)}"` : `name="${escapeHTML(meta.name || "")}"`;
+    html += `<meta ${key} content="${escapeHTML(meta.content)}">`;
+  }
+  for (let link of head.links || []) {
+    html += `<link rel="${escapeHTML(link.rel)}" href="${escapeHTML(link.href)}"`;
+    if (link.type)
+      html += ` type="${escapeHTML(link.type)}"`;
+    if (link.hreflang)
+      html += ` hreflang="${escapeHTML(link.hreflang)}"`;
+    html += ">";
+  }
+  return html;
+}
+function withoutOverrides(head, html) {
+  if (!head)
+    return html;
+  if (head.title) {
+    html = html.replace(/<title\b[^>]*>[\s\S]*?<\/title>/g, "");
+  }
+  const names = /* @__PURE__ */ new Set();
+  const properties = /* @__PURE__ */ new Set();
+  for (let meta of head.meta || []) {
+    if (meta.property)
+      properties.add(meta.property);
+    else if (meta.name)
+      names.add(meta.name);
+  }
+  html = html.replace(/<meta\b[^>]*>/g, (tag) => {
+    const name = attribute(tag, "name");
+    const property = attribute(tag, "property");
+    if (name !== void 0 && names.has(name))
+      return "";
+    if (property !== void 0 && properties.has(property))
+      return "";
+    return tag;
+  });
+  if ((head.links || []).some((link) => link.rel === "canonical")) {
+    html = html.replace(/<link\b[^>]*>/g, (tag) => attribute(tag, "rel") === "canonical" ? "" : tag);
+  }
+  return html;
+}
+function attribute(tag, name) {
+  const match = tag.match(new RegExp(`\\s${name}=(?:"([^"]*)"|'([^']*)'|([^\\s>]+))`, "i"));
+  if (!match)
+    return void 0;
+  return match[1] ?? match[2] ?? match[3];
+}
+function escapeHTML(text) {
+  return String(text).replace(/&/g, "&amp;").replace(/</g, "&lt;").replace(/>/g, "&gt;").replace(/"/g, "&quot;").replace(/'/g, "&#39;");
+}
+var defaultCSS = `
+/*! modern-normalize v1.1.0 | MIT License | https://github.com/sindresorhus/modern-normalize */
+
+/*
+Document
+========
+*/
+
+/**
+Use a better box model (opinionated).
+*/
+
+*,
+::before,
+::after {
+	box-sizing: border-box;
+}
+
+/**
+1. Correct the line height in all browsers.
+2. Prevent adjustments of font size after orientation changes in iOS.
+3. Use a more readable tab size (opinionated).
+*/
+
+html {
+	line-height: 1.15; /* 1 */
+	-webkit-text-size-adjust: 100%; /* 2 */
+	-moz-tab-size: 4; /* 3 */
+	tab-size: 4; /* 3 */
+}
+
+/*
+Sections
+========
+*/
+
+/**
+1. Remove the margin in all browsers.
+2. Improve consistency of default fonts in all browsers. (https://github.com/sindresorhus/modern-normalize/issues/3)
+*/
+
+body {
+	margin: 0; /* 1 */
+	font-family:
+		system-ui,
+		-apple-system, /* Firefox supports this but not yet system-ui */
+		'Segoe UI',
+		Roboto,
+		Helvetica,
+		Arial,
+		sans-serif,
+		'Apple Color Emoji',
+		'Segoe UI Emoji'; /* 2 */
+}
+
+/*
+Grouping content
+================
+*/
+
+/**
+1. Add the correct height in Firefox.
+2. Correct the inheritance of border color in Firefox. (https://bugzilla.mozilla.org/show_bug.cgi?id=190655)
+*/
+
+hr {
+	height: 0; /* 1 */
+	color: inherit; /* 2 */
+}
+
+/*
+Text-level semantics
+====================
+*/
+
+/**
+Add the correct text decoration in Chrome, Edge, and Safari.
+*/
+
+abbr[title] {
+	text-decoration: underline dotted;
+}
+
+/**
+Add the correct font weight in Edge and Safari.
+*/
+
+b,
+strong {
+	font-weight: bolder;
+}
+
+/**
+1. Improve consistency of default fonts in all browsers. (https://github.com/sindresorhus/modern-normalize/issues/3)
+2. Correct the odd 'em' font sizing in all browsers.
+*/
+
+code,
+kbd,
+samp,
+pre {
+	font-family:
+		ui-monospace,
+		SFMono-Regular,
+		Consolas,
+		'Liberation Mono',
+		Menlo,
+		monospace; /* 1 */
+	font-size: 1em; /* 2 */
+}
+
+/**
+Add the correct font size in all browsers.
+*/
+
+small {
+	font-size: 80%;
+}
+
+/**
+Prevent 'sub' and 'sup' elements from affecting the line height in all browsers.
+*/
+
+sub,
+sup {
+	font-size: 75%;
+	line-height: 0;
+	position: relative;
+	vertical-align: baseline;
+}
+
+sub {
+	bottom: -0.25em;
+}
+
+sup {
+	top: -0.5em;
+}
+
+/*
+Tabular data
+============
+*/
+
+/**
+1. Remove text indentation from table contents in Chrome and Safari. (https://bugs.chromium.org/p/chromium/issues/detail?id=999088, https://bugs.webkit.org/show_bug.cgi?id=201297)
+2. Correct table border color inheritance in all Chrome and Safari. (https://bugs.chromium.org/p/chromium/issues/detail?id=935729, https://bugs.webkit.org/show_bug.cgi?id=195016)
+*/
+
+table {
+	text-indent: 0; /* 1 */
+	border-color: inherit; /* 2 */
+}
+
+/*
+Forms
+=====
+*/
+
+/**
+1. Change the font styles in all browsers.
+2. Remove the margin in Firefox and Safari.
+*/
+
+button,
+input,
+optgroup,
+select,
+textarea {
+	font-family: inherit; /* 1 */
+	font-size: 100%; /* 1 */
+	line-height: 1.15; /* 1 */
+	margin: 0; /* 2 */
+}
+
+/**
+Remove the inheritance of text transform in Edge and Firefox.
+*/
+
+button,
+select {
+	text-transform: none;
+}
+
+/**
+Correct the inability to style clickable types in iOS and Safari.
+*/
+
+button,
+[type='button'],
+[type='reset'],
+[type='submit'] {
+	-webkit-appearance: button;
+}
+
+/**
+Remove the inner border and padding in Firefox.
+*/
+
+::-moz-focus-inner {
+	border-style: none;
+	padding: 0;
+}
+
+/**
+Restore the focus styles unset by the previous rule.
+*/
+
+:-moz-focusring {
+	outline: 1px dotted ButtonText;
+}
+
+/**
+Remove the additional ':invalid' styles in Firefox.
+See: https://github.com/mozilla/gecko-dev/blob/2f9eacd9d3d995c937b4251a5557d95d494c9be1/layout/style/res/forms.css#L728-L737
+*/
+
+:-moz-ui-invalid {
+	box-shadow: none;
+}
+
+/**
+Remove the padding so developers are not caught out when they zero out 'fieldset' elements in all browsers.
+*/
+
+legend {
+	padding: 0;
+}
+
+/**
+Add the correct vertical alignment in Chrome and Firefox.
+*/
+
+progress {
+	vertical-align: baseline;
+}
+
+/**
+Correct the cursor style of increment and decrement buttons in Safari.
+*/
+
+::-webkit-inner-spin-button,
+::-webkit-outer-spin-button {
+	height: auto;
+}
+
+/**
+1. Correct the odd appearance in Chrome and Safari.
+2. Correct the outline style in Safari.
+*/
+
+[type='search'] {
+	-webkit-appearance: textfield; /* 1 */
+	outline-offset: -2px; /* 2 */
+}
+
+/**
+Remove the inner padding in Chrome and Safari on macOS.
+*/
+
+::-webkit-search-decoration {
+	-webkit-appearance: none;
+}
+
+/**
+1. Correct the inability to style clickable types in iOS and Safari.
+2. Change font properties to 'inherit' in Safari.
+*/
+
+::-webkit-file-upload-button {
+	-webkit-appearance: button; /* 1 */
+	font: inherit; /* 2 */
+}
+
+/*
+Interactive
+===========
+*/
+
+/*
+Add the correct display in Chrome and Safari.
+*/
+
+summary {
+	display: list-item;
+}
+`;
+
+// vue.ts
+function createView(view) {
+  const error = view.error || DefaultError;
+  async function renderView({ props, context }) {
+    props = props || {};
+    const pageError = props.bud_error;
+    const rendered = await renderPage(view, error, props);
+    const head = `
+          ${renderHead(props.bud_head)}${errorTitle(view, props)}
+          ${rendered.css ? `<style>${rendered.css}</style>` : ""}
+          ${hydrateScripts(view.client, props)}
+        `;
+    const body = '<div id="bud_target">' + rendered.html + "</div>";
+    return {
+      status: pageError ? pageError.status : 200,
+      headers: {
+        "Content-Type": "text/html"
+      },
+      body: await renderLayout(view.layout, props, head, body)
+    };
+  }
+  renderView.stream = async function({ props, context }, write) {
+    props = props || {};
+    const pageError = props.bud_error;
+    const head = `
+          ${renderHead(props.bud_head)}${errorTitle(view, props)}
+        `;
+    const html = await renderLayout(view.layout, props, head, bodyMarker);
+    const index = html.indexOf(bodyMarker);
+    write({
+      status: pageError ? pageError.status : 200,
+      headers: {
+        "Content-Type": "text/html"
+      },
+      body: index < 0 ? html : html.slice(0, index)
+    });
+    const rendered = await renderPage(view, error, props);
+    write({
+      body: (rendered.css ? `<style>${rendered.css}</style>` : "") + '<div id="bud_target">' + rendered.html + "</div>" + hydrateScripts(view.client, props)
+    });
+    write({
+      body: index < 0 ? "" : html.slice(index + bodyMarker.length),
+      tail: true
+    });
+  };
+  return renderView;
+}
+async function renderPage(view, error, props) {
+  const pageError = props.bud_error;
+  const app = createSSRApp({
+    render: pageError ? () => h(error, propsOf(error, { ...props, error: pageError })) : nest(view.page, view.frames, props)
+  });
+  const context = {};
+  const html = await renderToString(app, context);
+  return {
+    html,
+    css: Array.from(context.css || []).join("\n")
+  };
+}
+function nest(page, frames, props) {
+  let render = () => h(page, propsOf(page, props));
+  for (let i = frames.length - 1; i >= 0; i--) {
+    const inner = render;
+    const frame = frames[i];
+    render = () => h(frame, propsOf(frame, props), { default: inner });
+  }
+  return render;
+}
+function propsOf(component, props) {
+  const declared = component.props;
+  if (!declared)
+    return {};
+  const keys = Array.isArray(declared) ? declared : Object.keys(declared);
+  const picked = {};
+  for (let key of keys) {
+    if (key in props)
+      picked[key] = props[key];
+  }
+  return picked;
+}
+async function renderLayout(layout, props, head, body) {
+  if (!layout) {
+    return defaultLayout(head, body);
+  }
+  const context = {};
+  const app = createSSRApp({
+    render: () => h(layout, propsOf(layout, props), { default: () => h("bud-body") })
+  });
+  let html = await renderToString(app, context);
+  html = html.replace("<bud-body></bud-body>", () => body);
+  const css = Array.from(context.css || []).join("\n");
+  const layoutHead = (css ? `<style>${css}</style>` : "") + head;
+  const start = html.indexOf("<head>");
+  const end = html.indexOf("</head>");
+  if (start >= 0 && end > start) {
+    const inner = withoutOverrides(props.bud_head, html.slice(start + 6, end));
+    html = html.slice(0, start + 6) + inner + layoutHead + html.slice(end);
+  } else {
+    html = layoutHead + html;
+  }
+  if (html.startsWith("<html")) {
+    html = "<!doctype html>" + html;
+  }
+  return html;
+}
+function errorTitle(view, props) {
+  const pageError = props.bud_error;
+  if (!pageError || view.error || props.bud_head && props.bud_head.title)
+    return "";
+  return `<title>${escapeHTML(String(pageError.status))}</title>`;
+}
+function defaultLayout(head, body) {
+  return `
+    <!doctype html>
+    <html>
+      <head>
+        <meta charset="utf-8"/>
+        <style>${defaultCSS}</style>
+        ${head}
+      </head>
+      <body>${body}</body>
+    </html>
+  `;
+}
+var DefaultError = {
+  props: ["error"],
+  render() {
+    const error = this.error;
+    return [h("h1", String(error.status)), h("p", error.message)];
+  }
+};
+export {
+  createView
+};
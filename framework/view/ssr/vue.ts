@@ -0,0 +1,182 @@
+import { createSSRApp, h } from "vue"
+import { renderToString } from "vue/server-renderer"
+import {
+  Chunk,
+  PageError,
+  bodyMarker,
+  defaultCSS,
+  escapeHTML,
+  hydrateScripts,
+  renderHead,
+  withoutOverrides,
+} from "./html"
+
+type View = {
+  page: any
+  frames: any[]
+  layout: any
+  error?: any
+  client: string
+}
+
+type Rendered = {
+  html: string
+  css: string
+}
+
+// Vue renders asynchronously, so the views return promises
+export function createView(view: View) {
+  const error = view.error || DefaultError
+  // Render the view into a single response
+  async function renderView({ props, context }) {
+    props = props || {}
+    const pageError: PageError | undefined = props.bud_error
+    const rendered = await renderPage(view, error, props)
+    const head = `
+          ${renderHead(props.bud_head)}${errorTitle(view, props)}
+          ${rendered.css ? `<style>${rendered.css}</style>` : ""}
+          ${hydrateScripts(view.client, props)}
+        `
+    const body = '<div id="bud_target">' + rendered.html + "</div>"
+    return {
+      status: pageError ? pageError.status : 200,
+      headers: {
+        "Content-Type": "text/html",
+      },
+      body: await renderLayout(view.layout, props, head, body),
+    }
+  }
+  // Stream the view, flushing the layout before rendering the page. The page's
+  // styles and scripts are written along with the page.
+  renderView.stream = async function ({ props, context }, write: (chunk: Chunk) => void) {
+    props = props || {}
+    const pageError: PageError | undefined = props.bud_error
+    const head = `
+          ${renderHead(props.bud_head)}${errorTitle(view, props)}
+        `
+    const html = await renderLayout(view.layout, props, head, bodyMarker)
+    const index = html.indexOf(bodyMarker)
+    write({
+      status: pageError ? pageError.status : 200,
+      headers: {
+        "Content-Type": "text/html",
+      },
+      body: index < 0 ? html : html.slice(0, index),
+    })
+    const rendered = await renderPage(view, error, props)
+    write({
+      body:
+        (rendered.css ? `<style>${rendered.css}</style>` : "") +
+        '<div id="bud_target">' +
+        rendered.html +
+        "</div>" +
+        hydrateScripts(view.client, props),
+    })
+    write({
+      body: index < 0 ? "" : html.slice(index + bodyMarker.length),
+      tail: true,
+    })
+  }
+  return renderView
+}
+
+// Errors render the closest error page in place of the page and its frames.
+// Otherwise the page is rendered within the default slot of its frames, from
+// the innermost frame out.
+async function renderPage(view: View, error: any, props: any): Promise<Rendered> {
+  const pageError: PageError | undefined = props.bud_error
+  const app = createSSRApp({
+    render: pageError
+      ? () => h(error, propsOf(error, { ...props, error: pageError }))
+      : nest(view.page, view.frames, props),
+  })
+  const context: { css?: Set<string> } = {}
+  const html = await renderToString(app, context)
+  return {
+    html,
+    css: Array.from(context.css || []).join("\n"),
+  }
+}
+
+// nest the page within its frames
+function nest(page: any, frames: any[], props: any) {
+  let render = () => h(page, propsOf(page, props))
+  for (let i = frames.length - 1; i >= 0; i--) {
+    const inner = render
+    const frame = frames[i]
+    render = () => h(frame, propsOf(frame, props), { default: inner })
+  }
+  return render
+}
+
+// propsOf picks the props that the component declares. Vue would otherwise
+// render the rest as attributes of the component's root element.
+function propsOf(component: any, props: any): Record<string, any> {
+  const declared = component.props
+  if (!declared) return {}
+  const keys = Array.isArray(declared) ? declared : Object.keys(declared)
+  const picked: Record<string, any> = {}
+  for (let key of keys) {
+    if (key in props) picked[key] = props[key]
+  }
+  return picked
+}
+
+// Render the layout around the head and body. Layouts aren't hydrated.
+async function renderLayout(layout: any, props: any, head: string, body: string): Promise<string> {
+  if (!layout) {
+    return defaultLayout(head, body)
+  }
+  const context: { css?: Set<string> } = {}
+  const app = createSSRApp({
+    render: () => h(layout, propsOf(layout, props), { default: () => h("bud-body") }),
+  })
+  let html = await renderToString(app, context)
+  html = html.replace("<bud-body></bud-body>", () => body)
+  // The controller's head takes precedence over the layout's head
+  const css = Array.from(context.css || []).join("\n")
+  const layoutHead = (css ? `<style>${css}</style>` : "") + head
+  const start = html.indexOf("<head>")
+  const end = html.indexOf("</head>")
+  if (start >= 0 && end > start) {
+    const inner = withoutOverrides(props.bud_head, html.slice(start + 6, end))
+    html = html.slice(0, start + 6) + inner + layoutHead + html.slice(end)
+  } else {
+    html = layoutHead + html
+  }
+  if (html.startsWith("<html")) {
+    html = "<!doctype html>" + html
+  }
+  return html
+}
+
+// The default error page has a title, unless the controller set one
+function errorTitle(view: View, props: any): string {
+  const pageError: PageError | undefined = props.bud_error
+  if (!pageError || view.error || (props.bud_head && props.bud_head.title)) return ""
+  return `<title>${escapeHTML(String(pageError.status))}</title>`
+}
+
+function defaultLayout(head: string, body: string): string {
+  return `
+    <!doctype html>
+    <html>
+      <head>
+        <meta charset="utf-8"/>
+        <style>${defaultCSS}</style>
+        ${head}
+      </head>
+      <body>${body}</body>
+    </html>
+  `
+}
+
+// Default error page when there's no error.vue. This needs to match the
+// default error page in livebud/runtime/vue.
+const DefaultError = {
+  props: ["error"],
+  render() {
+    const error: PageError = (this as any).error
+    return [h("h1", String(error.status)), h("p", error.message)]
+  },
+}
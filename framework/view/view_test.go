@@ -1,11 +1,13 @@
 package view_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/lithammer/dedent"
@@ -391,3 +393,557 @@ func TestSvelteImportFromNodeModule(t *testing.T) {
 	is.In(res.Body().String(), "<time datetime=\"2022-07-19 10:19:00\">Jul 19, 2022</time>")
 	is.NoErr(app.Close())
 }
+
+func TestLayoutFrames(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/posts/controller.go"] = `
+		package posts
+		type Controller struct {}
+		type Post struct {
+			Title string ` + "`" + `json:"title"` + "`" + `
+		}
+		func (c *Controller) Show(id int) *Post { return &Post{"Hello"} }
+	`
+	td.Files["view/layout.svelte"] = `<html><head><slot name="head" /></head><body><slot /></body></html>`
+	td.Files["view/frame.svelte"] = `<nav>root</nav><main><slot /></main>`
+	td.Files["view/posts/frame.svelte"] = `<script>export let post = {}</script><article data-title={post.title}><slot /></article>`
+	td.Files["view/posts/show.svelte"] = `<script>export let post = {}</script><h1>{post.title}</h1>`
+	td.NodeModules["svelte"] = versions.Svelte
+	td.NodeModules["livebud"] = "*"
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	res, err := app.Get("/posts/1")
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	body := res.Body().String()
+	// Frames nest from the root frame in, within the layout
+	is.In(body, `<body><div id="bud_target"><nav>root</nav><main><article data-title="Hello"><h1>Hello</h1></article></main></div></body>`)
+	// The layout's head slot holds the props and the client
+	is.In(body, `<script id="bud_props" type="text/template" defer>{"post":{"title":"Hello"}}</script>`)
+	is.In(body, `<script type="module" src="/bud/view/posts/_show.svelte.js" defer></script>`)
+	// Layouts and frames aren't pages
+	res, err = app.Get("/layout")
+	is.NoErr(err)
+	is.Equal(res.Status(), 404)
+	// Frames are hydrated along with the page
+	res, err = app.Get("/bud/view/posts/_show.svelte.js")
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	is.In(res.Body().String(), `"/bud/view/frame.svelte",`)
+	is.In(res.Body().String(), `"/bud/view/posts/frame.svelte",`)
+}
+
+func TestErrorPage(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/posts/controller.go"] = `
+		package posts
+		import "github.com/livebud/bud/framework/controller/controllerrt/response"
+		type Controller struct {}
+		type Post struct {
+			Title string ` + "`" + `json:"title"` + "`" + `
+		}
+		func (c *Controller) Index() ([]*Post, error) {
+			return nil, response.Forbidden("not allowed")
+		}
+		func (c *Controller) Show(id int) (*Post, error) {
+			return nil, response.NotFound("post %d not found", id)
+		}
+	`
+	td.Files["view/frame.svelte"] = `<nav>root</nav><slot />`
+	td.Files["view/posts/index.svelte"] = `<script>export let posts = []</script><h1>{posts.length}</h1>`
+	td.Files["view/posts/show.svelte"] = `<script>export let post = {}</script><h1>{post.title}</h1>`
+	td.Files["view/posts/error.svelte"] = `<script>export let error = {}</script><h1>{error.status}: {error.message}</h1>`
+	td.NodeModules["svelte"] = versions.Svelte
+	td.NodeModules["livebud"] = "*"
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	// The closest error page renders in place of the page and its frames
+	res, err := app.Get("/posts/1")
+	is.NoErr(err)
+	is.Equal(res.Status(), 404)
+	body := res.Body().String()
+	is.In(body, `<div id="bud_target"><h1>404: post 1 not found</h1></div>`)
+	is.NotIn(body, "<nav>root</nav>")
+	res, err = app.Get("/posts")
+	is.NoErr(err)
+	is.Equal(res.Status(), 403)
+	is.In(res.Body().String(), `<h1>403: not allowed</h1>`)
+	// JSON responses are unchanged
+	res, err = app.GetJSON("/posts/1")
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 404 Not Found
+		Content-Type: application/json
+
+		{"error":"post 1 not found"}
+	`))
+}
+
+func TestStreamDeferred(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/posts/controller.go"] = `
+		package posts
+		import "github.com/livebud/bud/framework/view/viewrt"
+		type Controller struct {}
+		type Post struct {
+			Title    string            ` + "`" + `json:"title"` + "`" + `
+			Comments *viewrt.Deferred ` + "`" + `json:"comments"` + "`" + `
+		}
+		func (c *Controller) Show(id int) (*Post, error) {
+			return &Post{
+				Title: "hello",
+				Comments: viewrt.Defer(func() (interface{}, error) {
+					return []string{"first"}, nil
+				}),
+			}, nil
+		}
+	`
+	td.Files["view/posts/show.svelte"] = `
+		<script>export let post = {}</script>
+		<h1>{post.title}</h1>
+		{#if post.comments}<p>{post.comments.length}</p>{:else}<p>loading</p>{/if}
+	`
+	td.NodeModules["svelte"] = versions.Svelte
+	td.NodeModules["livebud"] = "*"
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	// Pages render without the deferred prop, which is streamed in before the
+	// end of the document
+	res, err := app.Get("/posts/1")
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	body := res.Body().String()
+	is.In(body, `<script id="bud_props" type="text/template" defer>{"post":{"title":"hello","comments":null}}</script>`)
+	is.In(body, `<h1>hello</h1>`)
+	is.In(body, `<p>loading</p>`)
+	is.In(body, `<script>(self.bud_deferred=self.bud_deferred||[]).push({"path":["post","comments"],"value":["first"]})</script></body>`)
+	// JSON responses wait for the deferred prop
+	res, err = app.GetJSON("/posts/1")
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 200 OK
+		Content-Type: application/json
+
+		{"title":"hello","comments":["first"]}
+	`))
+}
+
+func TestControllerHead(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/posts/controller.go"] = `
+		package posts
+		import (
+			"context"
+			"github.com/livebud/bud/package/head"
+		)
+		type Controller struct {}
+		type Post struct {
+			Title string ` + "`" + `json:"title"` + "`" + `
+		}
+		func (c *Controller) Show(ctx context.Context, id int) (*Post, error) {
+			h := head.From(ctx)
+			h.SetTitle("Hello | Blog")
+			h.SetMeta("description", "All about hello")
+			h.SetProperty("og:title", "Hello")
+			h.SetCanonical("https://example.com/posts/1")
+			return &Post{Title: "Hello"}, nil
+		}
+	`
+	td.Files["view/layout.svelte"] = `
+		<svelte:head><title>Blog</title></svelte:head>
+		<html><head><slot name="head" /></head><body><slot /></body></html>
+	`
+	td.Files["view/posts/show.svelte"] = `
+		<script>export let post = {}</script>
+		<svelte:head>
+			<title>{post.title}</title>
+			<meta name="description" content="A post">
+			<meta name="author" content="Alice">
+		</svelte:head>
+		<h1>{post.title}</h1>
+	`
+	td.NodeModules["svelte"] = versions.Svelte
+	td.NodeModules["livebud"] = "*"
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	res, err := app.Get("/posts/1")
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	body := res.Body().String()
+	// The controller's head comes first and overrides the matching tags
+	is.In(body, `<title>Hello | Blog</title><meta name="description" content="All about hello"><meta property="og:title" content="Hello"><link rel="canonical" href="https://example.com/posts/1">`)
+	is.NotIn(body, `<title>Blog</title>`)
+	is.NotIn(body, `<title>Hello</title>`)
+	is.NotIn(body, `content="A post"`)
+	is.In(body, `<meta name="author" content="Alice"`)
+}
+
+func TestNavigate(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/posts/controller.go"] = `
+		package posts
+		import (
+			"context"
+			"github.com/livebud/bud/framework/controller/controllerrt/response"
+			"github.com/livebud/bud/package/head"
+		)
+		type Controller struct {}
+		type Post struct {
+			Title string ` + "`" + `json:"title"` + "`" + `
+		}
+		func (c *Controller) Show(ctx context.Context, id int) (*Post, error) {
+			if id == 0 {
+				return nil, response.NotFound("post not found")
+			}
+			head.From(ctx).SetTitle("Hello")
+			return &Post{Title: "hello"}, nil
+		}
+	`
+	td.Files["view/posts/show.svelte"] = `
+		<script>export let post = {}</script>
+		<h1>{post.title}</h1>
+	`
+	td.NodeModules["svelte"] = versions.Svelte
+	td.NodeModules["livebud"] = "*"
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	// The router gets the route and props of the page rather than the page
+	req, err := app.GetRequest("/posts/1")
+	is.NoErr(err)
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("X-Bud-Navigate", "true")
+	res, err := app.Do(req)
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 200 OK
+		Content-Type: application/json
+
+		{"route":"/posts/:id","props":{"bud_head":{"title":"Hello"},"post":{"title":"hello"}}}
+	`))
+	// Errors are rendered by the router too
+	req, err = app.GetRequest("/posts/0")
+	is.NoErr(err)
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("X-Bud-Navigate", "true")
+	res, err = app.Do(req)
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 404 Not Found
+		Content-Type: application/json
+
+		{"route":"/posts/:id","props":{"bud_error":{"message":"post not found","status":404}}}
+	`))
+	// The page itself is still rendered without the header
+	res, err = app.Get("/posts/1")
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	is.In(res.Body().String(), `<h1>hello</h1>`)
+}
+
+func TestSubmitForm(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/posts/controller.go"] = `
+		package posts
+		type Controller struct {}
+		type Post struct {
+			Title string ` + "`" + `json:"title" validate:"required"` + "`" + `
+		}
+		func (c *Controller) New() {}
+		func (c *Controller) Create(post *Post) *Post {
+			return post
+		}
+		func (c *Controller) Update(id int, title string) {}
+	`
+	td.Files["view/posts/new.svelte"] = `
+		<script>export let errors = {}</script>
+		<form method="post" action="/posts" data-bud-navigate>
+			<input name="title" />
+			{#if errors.title}<p>{errors.title}</p>{/if}
+		</form>
+	`
+	td.NodeModules["svelte"] = versions.Svelte
+	td.NodeModules["livebud"] = "*"
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	// Forms submitted by the router redirect back with the errors, like forms
+	// submitted by the browser. Fetch adds the charset to the content type.
+	req, err := app.PostRequest("/posts", bytes.NewBufferString(`title=`))
+	is.NoErr(err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=UTF-8")
+	req.Header.Set("Referer", "/posts/new")
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("X-Bud-Navigate", "true")
+	res, err := app.Do(req)
+	is.NoErr(err)
+	is.Equal(res.Status(), 303)
+	is.Equal(res.Header("Location"), "/posts/new")
+	cookie := res.Header("Set-Cookie")
+	// The router follows the redirect and renders the errors
+	req, err = app.GetRequest("/posts/new")
+	is.NoErr(err)
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("X-Bud-Navigate", "true")
+	req.Header.Set("Cookie", strings.Split(cookie, ";")[0])
+	res, err = app.Do(req)
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	is.In(res.Body().String(), `{"route":"/posts/new","props":{"errors":{"title":"is required"},"old":{"title":""}}}`)
+	// PATCH forms are submitted with the _method field
+	req, err = app.PostRequest("/posts/1", bytes.NewBufferString(`_method=patch&title=hello`))
+	is.NoErr(err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=UTF-8")
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("X-Bud-Navigate", "true")
+	res, err = app.Do(req)
+	is.NoErr(err)
+	is.Equal(res.Status(), 302)
+	is.Equal(res.Header("Location"), "/posts/1")
+}
+
+func TestIslands(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/posts/controller.go"] = `
+		package posts
+		type Controller struct {}
+		type Post struct {
+			Title string ` + "`" + `json:"title"` + "`" + `
+			Likes int    ` + "`" + `json:"likes"` + "`" + `
+		}
+		func (c *Controller) Index() []*Post { return []*Post{{"Hello", 1}} }
+		func (c *Controller) Show(id int) *Post { return &Post{"Hello", 3} }
+	`
+	td.Files["view/posts/index.svelte"] = `
+		<script>export let posts = []</script>
+		{#each posts as post}<h2>{post.title}</h2>{/each}
+	`
+	td.Files["view/posts/show.svelte"] = `
+		<script>
+			import Like from "./Like.island.svelte"
+			export let post = {}
+		</script>
+		<h1>{post.title}</h1>
+		<Like count={post.likes} />
+	`
+	td.Files["view/posts/Like.island.svelte"] = `
+		<script>export let count = 0</script>
+		<button on:click={() => count++}>{count}</button>
+	`
+	td.NodeModules["svelte"] = versions.Svelte
+	td.NodeModules["livebud"] = "*"
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	// Pages with islands are static, apart from their islands
+	res, err := app.Get("/posts/1")
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	body := res.Body().String()
+	is.In(body, `<h1>Hello</h1>`)
+	is.In(body, `<bud-island client="/bud/view/posts/_Like.island.svelte.js" props="{&quot;count&quot;:3}" style="display:contents"><button>3</button></bud-island>`)
+	is.In(body, `<script type="module" src="/bud/view/posts/_Like.island.svelte.js" defer></script>`)
+	is.True(!strings.Contains(body, `bud_props`))
+	is.True(!strings.Contains(body, `/bud/view/posts/_show.svelte.js`))
+	// Pages without islands are hydrated as usual
+	res, err = app.Get("/posts")
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	body = res.Body().String()
+	is.In(body, `<script id="bud_props" type="text/template" defer>{"posts":[{"title":"Hello","likes":1}]}</script>`)
+	is.In(body, `<script type="module" src="/bud/view/posts/_index.svelte.js" defer></script>`)
+	// Islands have their own client entry
+	res, err = app.Get("/bud/view/posts/_Like.island.svelte.js")
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	is.In(res.Body().String(), `client: "/bud/view/posts/_Like.island.svelte.js",`)
+}
+
+func TestJSXLayoutFrames(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/posts/controller.go"] = `
+		package posts
+		import (
+			"context"
+			"github.com/livebud/bud/framework/controller/controllerrt/response"
+			"github.com/livebud/bud/package/head"
+		)
+		type Controller struct {}
+		type Post struct {
+			Title string ` + "`" + `json:"title"` + "`" + `
+		}
+		func (c *Controller) Index() []*Post { return []*Post{{"Hello"}} }
+		func (c *Controller) Show(ctx context.Context, id int) (*Post, error) {
+			if id == 0 {
+				return nil, response.NotFound("post not found")
+			}
+			head.From(ctx).SetTitle("Hello")
+			return &Post{"Hello"}, nil
+		}
+	`
+	td.Files["view/layout.jsx"] = `
+		export default function Layout({ children }) {
+			return <html><head><title>Layout</title></head><body>{children}</body></html>
+		}
+	`
+	td.Files["view/frame.jsx"] = `
+		export default function Frame({ children }) {
+			return <><nav>root</nav><main>{children}</main></>
+		}
+	`
+	td.Files["view/posts/frame.jsx"] = `
+		export default function Frame({ post, children }) {
+			return <article data-title={post.title}>{children}</article>
+		}
+	`
+	td.Files["view/posts/show.jsx"] = `
+		export default function Show({ post }) {
+			return <h1>{post.title}</h1>
+		}
+	`
+	td.Files["view/posts/index.tsx"] = `
+		export default function Index({ posts }: { posts: { title: string }[] }) {
+			return <ul>{posts.map((post) => <li>{post.title}</li>)}</ul>
+		}
+	`
+	td.NodeModules["react"] = versions.React
+	td.NodeModules["react-dom"] = versions.React
+	td.NodeModules["livebud"] = "*"
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	res, err := app.Get("/posts/1")
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	body := res.Body().String()
+	// Frames nest from the root frame in, within the layout
+	is.In(body, `<body><div id="bud_target"><nav>root</nav><main><article data-title="Hello"><h1>Hello</h1></article></main></div></body>`)
+	// The controller's title takes precedence over the layout's
+	is.In(body, `<title>Hello</title>`)
+	is.True(!strings.Contains(body, `<title>Layout</title>`))
+	// The layout's head holds the props and the client
+	is.In(body, `<script id="bud_props" type="text/template" defer>{"bud_head":{"title":"Hello"},"post":{"title":"Hello"}}</script>`)
+	is.In(body, `<script type="module" src="/bud/view/posts/_show.jsx.js" defer></script>`)
+	// Frames are hydrated along with the page
+	res, err = app.Get("/bud/view/posts/_show.jsx.js")
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	is.In(res.Body().String(), `"/bud/view/frame.jsx",`)
+	is.In(res.Body().String(), `"/bud/view/posts/frame.jsx",`)
+	// TSX pages are views too. Frames and layouts only wrap pages of the same
+	// type.
+	res, err = app.Get("/posts")
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	is.In(res.Body().String(), `<div id="bud_target"><ul><li>Hello</li></ul></div>`)
+	is.In(res.Body().String(), `<script type="module" src="/bud/view/posts/_index.tsx.js" defer></script>`)
+	// Errors render the default error page
+	res, err = app.Get("/posts/0")
+	is.NoErr(err)
+	is.Equal(res.Status(), 404)
+	is.In(res.Body().String(), `<h1>404</h1><p>post not found</p>`)
+}
+
+func TestVue(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/posts/controller.go"] = `
+		package posts
+		import (
+			"context"
+			"github.com/livebud/bud/framework/controller/controllerrt/response"
+		)
+		type Controller struct {}
+		type Post struct {
+			Title string ` + "`" + `json:"title"` + "`" + `
+		}
+		func (c *Controller) Show(ctx context.Context, id int) (*Post, error) {
+			if id == 0 {
+				return nil, response.NotFound("post not found")
+			}
+			return &Post{"Hello"}, nil
+		}
+	`
+	td.Files["view/posts/frame.vue"] = `
+		<template><main><slot /></main></template>
+	`
+	td.Files["view/posts/show.vue"] = `
+		<script setup>
+		defineProps(["post"])
+		</script>
+		<template><h1>{{ post.title }}</h1></template>
+		<style scoped>h1 { color: red }</style>
+	`
+	td.NodeModules["vue"] = versions.Vue
+	td.NodeModules["livebud"] = "*"
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	res, err := app.Get("/posts/1")
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	body := res.Body().String()
+	// The page is rendered within its frame
+	is.True(regexp.MustCompile(`<main><!--\[--><h1 data-v-[a-z0-9]+>Hello</h1><!--\]--></main>`).MatchString(body))
+	// Scoped styles are written into the page
+	is.True(regexp.MustCompile(`<style>h1\[data-v-[a-z0-9]+\]`).MatchString(body))
+	is.In(body, `<script type="module" src="/bud/view/posts/_show.vue.js" defer></script>`)
+	// The page is hydrated along with its frame
+	res, err = app.Get("/bud/view/posts/_show.vue.js")
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	is.In(res.Body().String(), `"/bud/view/posts/frame.vue",`)
+	// Errors render the default error page
+	res, err = app.Get("/posts/0")
+	is.NoErr(err)
+	is.Equal(res.Status(), 404)
+	is.In(res.Body().String(), `<h1>404</h1><p>post not found</p>`)
+}
@@ -0,0 +1,157 @@
+package viewrt
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Defer a prop until it resolves. Pages are rendered and flushed with the prop
+// set to null, then the prop is streamed into the page once it resolves. Other
+// responses like JSON wait for the prop to resolve.
+func Defer(fn func() (interface{}, error)) *Deferred {
+	return &Deferred{fn: fn, done: make(chan struct{})}
+}
+
+// Deferred prop
+type Deferred struct {
+	fn       func() (interface{}, error)
+	once     sync.Once
+	done     chan struct{}
+	value    interface{}
+	err      error
+	streamed bool
+}
+
+// MarshalJSON waits for the prop to resolve. Props that are being streamed into
+// a page are null until they resolve.
+func (d *Deferred) MarshalJSON() ([]byte, error) {
+	d.resolve()
+	// Set within the once, so it's safe to read after resolving
+	if d.streamed {
+		return []byte("null"), nil
+	}
+	<-d.done
+	if d.err != nil {
+		return nil, d.err
+	}
+	return json.Marshal(d.value)
+}
+
+// stream the prop into the page once it resolves
+func (d *Deferred) stream() {
+	d.once.Do(func() {
+		d.streamed = true
+		d.start()
+	})
+}
+
+// resolve the deferred prop in the background
+func (d *Deferred) resolve() {
+	d.once.Do(d.start)
+}
+
+// start resolving the prop
+func (d *Deferred) start() {
+	go func() {
+		defer close(d.done)
+		d.value, d.err = d.fn()
+	}()
+}
+
+// deferredProp is a deferred prop and its path within the props
+type deferredProp struct {
+	Path     []interface{}
+	Deferred *Deferred
+}
+
+var deferredType = reflect.TypeOf((*Deferred)(nil))
+
+// findDeferred finds the deferred props within the props
+func findDeferred(props interface{}) (found []*deferredProp) {
+	var walk func(path []interface{}, value reflect.Value)
+	walk = func(path []interface{}, value reflect.Value) {
+		if !value.IsValid() {
+			return
+		}
+		if value.Type() == deferredType {
+			if !value.IsNil() {
+				found = append(found, &deferredProp{path, value.Interface().(*Deferred)})
+			}
+			return
+		}
+		switch value.Kind() {
+		case reflect.Ptr, reflect.Interface:
+			if !value.IsNil() {
+				walk(path, value.Elem())
+			}
+		case reflect.Map:
+			if value.Type().Key().Kind() != reflect.String {
+				return
+			}
+			iter := value.MapRange()
+			for iter.Next() {
+				walk(appendPath(path, iter.Key().String()), iter.Value())
+			}
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < value.Len(); i++ {
+				walk(appendPath(path, i), value.Index(i))
+			}
+		case reflect.Struct:
+			valueType := value.Type()
+			for i := 0; i < valueType.NumField(); i++ {
+				field := valueType.Field(i)
+				if field.PkgPath != "" {
+					continue
+				}
+				name := jsonName(field)
+				if name == "-" {
+					continue
+				}
+				// Embedded structs are flattened into their parent
+				if field.Anonymous && field.Tag.Get("json") == "" {
+					walk(path, value.Field(i))
+					continue
+				}
+				walk(appendPath(path, name), value.Field(i))
+			}
+		}
+	}
+	walk(nil, reflect.ValueOf(props))
+	return found
+}
+
+// appendPath copies the path so siblings don't share the underlying array
+func appendPath(path []interface{}, key interface{}) []interface{} {
+	next := make([]interface{}, len(path), len(path)+1)
+	copy(next, path)
+	return append(next, key)
+}
+
+// jsonName is the name of the field when it's marshaled to JSON
+func jsonName(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return tag
+	}
+	if name := strings.Split(tag, ",")[0]; name != "" {
+		return name
+	}
+	return field.Name
+}
+
+// deferredScript streams a resolved prop into the page. The browser runtime
+// picks up the props from self.bud_deferred, whether it's loaded before or
+// after the script runs. JSON is already escaped for script tags.
+func deferredScript(prop *deferredProp, value interface{}) ([]byte, error) {
+	data, err := json.Marshal(map[string]interface{}{
+		"path":  prop.Path,
+		"value": value,
+	})
+	if err != nil {
+		return nil, err
+	}
+	script := "<script>(self.bud_deferred=self.bud_deferred||[]).push(" + string(data) + ")</script>"
+	return []byte(script), nil
+}
@@ -0,0 +1,42 @@
+package viewrt
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/livebud/bud/internal/is"
+)
+
+func TestDeferredStream(t *testing.T) {
+	is := is.New(t)
+	deferred := Defer(func() (interface{}, error) {
+		return "hello", nil
+	})
+	deferred.stream()
+	data, err := deferred.MarshalJSON()
+	is.NoErr(err)
+	is.Equal(string(data), "null")
+	<-deferred.done
+	is.Equal(deferred.value, "hello")
+}
+
+func TestDeferredConcurrent(t *testing.T) {
+	is := is.New(t)
+	deferred := Defer(func() (interface{}, error) {
+		return "hello", nil
+	})
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		deferred.stream()
+	}()
+	go func() {
+		defer wg.Done()
+		data, err := deferred.MarshalJSON()
+		is.NoErr(err)
+		// Depends on whether the prop started streaming first
+		is.True(string(data) == "null" || string(data) == `"hello"`)
+	}()
+	wg.Wait()
+}
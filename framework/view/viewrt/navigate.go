@@ -0,0 +1,49 @@
+package viewrt
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// NavigateHeader is sent by the client-side router when it navigates to a
+// page. Rather than rendering the page, the server responds with the page's
+// route and props as JSON, so the router can render the page in the browser.
+const NavigateHeader = "X-Bud-Navigate"
+
+func isNavigate(r *http.Request) bool {
+	return r.Header.Get(NavigateHeader) != ""
+}
+
+// navigation is the response to the client-side router
+type navigation struct {
+	Route string      `json:"route"`
+	Props interface{} `json:"props"`
+}
+
+// navigate responds with the route and props of the page. Deferred props are
+// resolved before responding.
+func navigate(w http.ResponseWriter, route string, props interface{}) {
+	data, err := json.Marshal(navigation{route, props})
+	if err != nil {
+		// TODO: swap with logger
+		fmt.Println("view: navigate error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(pageStatus(props))
+	w.Write(data)
+}
+
+// pageStatus is the status of the page, which is set on error pages
+func pageStatus(props interface{}) int {
+	if props, ok := props.(Map); ok {
+		if pageError, ok := props["bud_error"].(Map); ok {
+			if status, ok := pageError["status"].(int); ok {
+				return status
+			}
+		}
+	}
+	return http.StatusOK
+}
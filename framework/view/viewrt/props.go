@@ -0,0 +1,29 @@
+package viewrt
+
+import (
+	"net/http"
+
+	"github.com/livebud/bud/package/head"
+	"github.com/livebud/bud/package/session"
+)
+
+// Props adds the flash and the head to the view props, lifting the errors and
+// old input from a failed submission into their own props.
+func Props(w http.ResponseWriter, r *http.Request, props map[string]interface{}) map[string]interface{} {
+	props = head.Props(r, props)
+	props = session.Props(w, r, props)
+	flash, ok := props["flash"].(map[string]interface{})
+	if !ok {
+		return props
+	}
+	for _, key := range []string{"errors", "old"} {
+		if value, ok := flash[key]; ok {
+			props[key] = value
+			delete(flash, key)
+		}
+	}
+	if len(flash) == 0 {
+		delete(props, "flash")
+	}
+	return props
+}
@@ -0,0 +1,63 @@
+package viewrt_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/livebud/bud/framework/controller/controllerrt/response"
+	"github.com/livebud/bud/framework/controller/controllerrt/validate"
+	"github.com/livebud/bud/framework/view/viewrt"
+	"github.com/livebud/bud/internal/is"
+)
+
+func TestRedirectBackProps(t *testing.T) {
+	is := is.New(t)
+	form := url.Values{"title": {""}, "body": {"hello"}}
+	r := httptest.NewRequest("POST", "/posts", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("Referer", "/posts/new")
+	is.NoErr(r.ParseForm())
+	w := httptest.NewRecorder()
+	response.ErrorHTML(validate.Errors{"title": "is required"}, "/posts").ServeHTTP(w, r)
+	is.Equal(w.Code, http.StatusSeeOther)
+	is.Equal(w.Header().Get("Location"), "/posts/new")
+	cookies := w.Result().Cookies()
+	is.Equal(len(cookies), 1)
+	// Follow the redirect
+	r = httptest.NewRequest("GET", "/posts/new", nil)
+	r.AddCookie(cookies[0])
+	w = httptest.NewRecorder()
+	props := viewrt.Props(w, r, map[string]interface{}{"post": "p"})
+	is.Equal(len(props), 3)
+	is.Equal(props["post"], "p")
+	is.Equal(props["errors"], map[string]interface{}{"title": "is required"})
+	is.Equal(props["old"], map[string]interface{}{"title": "", "body": "hello"})
+	// The flash is cleared
+	cookies = w.Result().Cookies()
+	is.Equal(len(cookies), 1)
+	is.Equal(cookies[0].MaxAge, -1)
+	// Without the cookie there are no extra props
+	r = httptest.NewRequest("GET", "/posts/new", nil)
+	w = httptest.NewRecorder()
+	props = viewrt.Props(w, r, map[string]interface{}{})
+	is.Equal(len(props), 0)
+}
+
+func TestRedirectBackError(t *testing.T) {
+	is := is.New(t)
+	r := httptest.NewRequest("POST", "/posts", nil)
+	w := httptest.NewRecorder()
+	response.ErrorHTML(errors.New("unable to create post"), "/posts").ServeHTTP(w, r)
+	is.Equal(w.Code, http.StatusSeeOther)
+	is.Equal(w.Header().Get("Location"), "/posts")
+	// Follow the redirect
+	r = httptest.NewRequest("GET", "/posts", nil)
+	r.AddCookie(w.Result().Cookies()[0])
+	w = httptest.NewRecorder()
+	props := viewrt.Props(w, r, map[string]interface{}{})
+	is.Equal(props["flash"], map[string]interface{}{"error": "unable to create post"})
+}
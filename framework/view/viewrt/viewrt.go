@@ -2,6 +2,7 @@ package viewrt
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
 	"net/http"
@@ -15,6 +16,27 @@ import (
 type Server interface {
 	Middleware(http.Handler) http.Handler
 	Handler(route string, props interface{}) http.Handler
+	Stream(route string, props interface{}) http.Handler
+	Error(route string, err error) http.Handler
+}
+
+// errorProps are the props of an error page. The error is passed under the
+// "bud_error" key, which tells the view to render the closest error page
+// rather than the page itself.
+func errorProps(err error) Map {
+	status := http.StatusInternalServerError
+	var coder interface{ StatusCode() int }
+	if errors.As(err, &coder) {
+		if code := coder.StatusCode(); code >= 400 && code <= 599 {
+			status = code
+		}
+	}
+	return Map{
+		"bud_error": Map{
+			"status":  status,
+			"message": err.Error(),
+		},
+	}
 }
 
 func Proxy(client budclient.Client) *liveServer {
@@ -37,12 +59,41 @@ func (s *liveServer) Middleware(next http.Handler) http.Handler {
 	})
 }
 
+// Handler renders the route. Props with deferred values are streamed.
 func (s *liveServer) Handler(route string, props interface{}) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isNavigate(r) {
+			navigate(w, route, props)
+			return
+		}
+		if deferred := findDeferred(props); len(deferred) > 0 {
+			streamResponse(w, deferred, func(write func(*ssr.Chunk) error) error {
+				return s.client.Stream(route, props, write)
+			})
+			return
+		}
 		s.respond(w, route, props)
 	})
 }
 
+// Stream the route, flushing the layout before the page renders
+func (s *liveServer) Stream(route string, props interface{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isNavigate(r) {
+			navigate(w, route, props)
+			return
+		}
+		streamResponse(w, findDeferred(props), func(write func(*ssr.Chunk) error) error {
+			return s.client.Stream(route, props, write)
+		})
+	})
+}
+
+// Error renders the error page of the route
+func (s *liveServer) Error(route string, err error) http.Handler {
+	return s.Handler(route, errorProps(err))
+}
+
 // Respond is a convenience function for render
 func (s *liveServer) respond(w http.ResponseWriter, path string, props interface{}) {
 	res, err := s.render(path, props)
@@ -125,6 +176,102 @@ func (s *staticServer) render(path string, props interface{}) (*ssr.Response, er
 	return res, nil
 }
 
+func (s *staticServer) stream(path string, props interface{}, write func(*ssr.Chunk) error) error {
+	propBytes, err := json.Marshal(s.wrapProps(path, props))
+	if err != nil {
+		return err
+	}
+	script, err := fs.ReadFile(s.fsys, "bud/view/_ssr.js")
+	if err != nil {
+		return err
+	}
+	// Evaluate the server, writing chunks as they're rendered
+	expr := fmt.Sprintf(`%s; bud.stream(%q, %s)`, script, path, propBytes)
+	return s.vm.Stream("_ssr.js", expr, func(data string) error {
+		chunk := new(ssr.Chunk)
+		if err := json.Unmarshal([]byte(data), chunk); err != nil {
+			return err
+		}
+		return write(chunk)
+	})
+}
+
+// streamResponse writes chunks to the response as they're rendered. Deferred
+// props start resolving right away and are streamed in before the tail of the
+// document.
+func streamResponse(w http.ResponseWriter, deferred []*deferredProp, stream func(write func(*ssr.Chunk) error) error) {
+	for _, prop := range deferred {
+		prop.Deferred.stream()
+	}
+	flusher, _ := w.(http.Flusher)
+	wroteHeader := false
+	err := stream(func(chunk *ssr.Chunk) error {
+		if !wroteHeader {
+			if chunk.Status < 100 || chunk.Status > 999 {
+				return fmt.Errorf("view: invalid status code %d", chunk.Status)
+			}
+			headers := w.Header()
+			for key, value := range chunk.Headers {
+				headers.Set(key, value)
+			}
+			w.WriteHeader(chunk.Status)
+			wroteHeader = true
+		}
+		if chunk.Tail {
+			if err := writeDeferred(w, flusher, deferred); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write([]byte(chunk.Body)); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		// TODO: swap with logger
+		fmt.Println("view: stream error", err)
+		// Once the header is written, the best we can do is end the response
+		if !wroteHeader {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+}
+
+// writeDeferred writes the deferred props in the order they resolve
+func writeDeferred(w http.ResponseWriter, flusher http.Flusher, deferred []*deferredProp) error {
+	resolved := make(chan *deferredProp, len(deferred))
+	for _, prop := range deferred {
+		go func(prop *deferredProp) {
+			<-prop.Deferred.done
+			resolved <- prop
+		}(prop)
+	}
+	for range deferred {
+		prop := <-resolved
+		value := prop.Deferred.value
+		if err := prop.Deferred.err; err != nil {
+			// TODO: swap with logger
+			fmt.Println("view: deferred prop error", err)
+			value = nil
+		}
+		script, err := deferredScript(prop, value)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(script); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	return nil
+}
+
 func isClient(path string) bool {
 	return strings.HasPrefix(path, "/bud/node_modules/") ||
 		strings.HasPrefix(path, "/bud/view/")
@@ -140,13 +287,42 @@ func (s *staticServer) Middleware(next http.Handler) http.Handler {
 	})
 }
 
-// Handler returns a handler for a specific server-side route
+// Handler returns a handler for a specific server-side route. Props with
+// deferred values are streamed.
 func (s *staticServer) Handler(route string, props interface{}) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isNavigate(r) {
+			navigate(w, route, s.wrapProps(route, props))
+			return
+		}
+		if deferred := findDeferred(props); len(deferred) > 0 {
+			streamResponse(w, deferred, func(write func(*ssr.Chunk) error) error {
+				return s.stream(route, props, write)
+			})
+			return
+		}
 		s.respond(w, route, props)
 	})
 }
 
+// Stream the route, flushing the layout before the page renders
+func (s *staticServer) Stream(route string, props interface{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isNavigate(r) {
+			navigate(w, route, s.wrapProps(route, props))
+			return
+		}
+		streamResponse(w, findDeferred(props), func(write func(*ssr.Chunk) error) error {
+			return s.stream(route, props, write)
+		})
+	})
+}
+
+// Error renders the error page of the route
+func (s *staticServer) Error(route string, err error) http.Handler {
+	return s.Handler(route, errorProps(err))
+}
+
 func (s *staticServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
 	file, err := s.hfs.Open(r.URL.Path)
 	if err != nil {
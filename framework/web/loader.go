@@ -3,12 +3,15 @@ package web
 import (
 	"io/fs"
 	"path"
+	"sort"
 	"strings"
 
 	"github.com/livebud/bud/internal/scan"
 
 	"github.com/livebud/bud/internal/bail"
 	"github.com/livebud/bud/internal/imports"
+	"github.com/livebud/bud/internal/signature"
+	"github.com/livebud/bud/internal/slot"
 	"github.com/livebud/bud/package/gomod"
 	"github.com/livebud/bud/package/parser"
 	"github.com/livebud/bud/package/vfs"
@@ -42,6 +45,7 @@ func (l *loader) Load() (state *State, err error) {
 		"bud/internal/app/controller/controller.go",
 		"bud/internal/app/public/public.go",
 		"bud/internal/app/view/view.go",
+		"bud/internal/openapi/openapi.go",
 	)
 	if err != nil {
 		return nil, err
@@ -51,6 +55,9 @@ func (l *loader) Load() (state *State, err error) {
 	l.imports.AddNamed("middleware", "github.com/livebud/bud/package/middleware")
 	l.imports.AddNamed("webrt", "github.com/livebud/bud/framework/web/webrt")
 	l.imports.AddNamed("router", "github.com/livebud/bud/package/router")
+	l.imports.AddNamed("session", "github.com/livebud/bud/package/session")
+	l.imports.AddNamed("head", "github.com/livebud/bud/package/head")
+	l.imports.AddNamed("prerender", "github.com/livebud/bud/package/prerender")
 	// Show the welcome page if we don't have controllers, views or public files
 	if len(exist) == 0 {
 		l.imports.AddNamed("welcome", "github.com/livebud/bud/framework/web/welcome")
@@ -67,9 +74,13 @@ func (l *loader) Load() (state *State, err error) {
 		state.HasView = true
 		l.imports.AddNamed("view", l.module.Import("bud/internal/app/view"))
 	}
+	if exist["bud/internal/openapi/openapi.go"] {
+		state.HasOpenAPI = true
+		l.imports.AddNamed("openapi", l.module.Import("bud/internal/openapi"))
+	}
 	// Load the controllers
 	if exist["bud/internal/app/controller/controller.go"] {
-		state.Actions = l.loadControllerActions()
+		state.Groups, state.Actions = l.loadControllers()
 		if len(state.Actions) > 0 {
 			l.imports.AddNamed("controller", l.module.Import("bud/internal/app/controller"))
 		}
@@ -80,42 +91,210 @@ func (l *loader) Load() (state *State, err error) {
 	return state, nil
 }
 
-func (l *loader) loadControllerActions() (actions []*Action) {
+func (l *loader) loadControllers() (groups []*Group, actions []*Action) {
 	subfs, err := fs.Sub(l.fsys, "controller")
 	if err != nil {
 		l.Bail(err)
 	}
+	var dirs []string
+	structs := map[string]*parser.Struct{}
 	scanner := scan.Controllers(subfs)
 	for scanner.Scan() {
-		actions = append(actions, l.loadActions(scanner.Text())...)
+		dir := scanner.Text()
+		pkg, err := l.parser.Parse(path.Join("controller", dir))
+		if err != nil {
+			l.Bail(err)
+		}
+		stct := pkg.Struct("Controller")
+		if stct == nil {
+			continue
+		}
+		dirs = append(dirs, dir)
+		structs[dir] = stct
 	}
 	if scanner.Err() != nil {
 		l.Bail(err)
 	}
-	return actions
+	groupMap := l.loadGroups(dirs, structs)
+	for _, dir := range dirs {
+		actions = append(actions, l.loadActions(dir, structs, groupMap)...)
+	}
+	// Only add the groups that have routes, starting from the outermost groups
+	sort.Strings(dirs)
+	for _, dir := range dirs {
+		if group, ok := groupMap[dir]; ok && group.used {
+			groups = append(groups, group)
+		}
+	}
+	return groups, actions
 }
 
-func (l *loader) loadActions(dir string) (actions []*Action) {
-	pkg, err := l.parser.Parse(path.Join("controller", dir))
-	if err != nil {
-		l.Bail(err)
+// Load the route groups. Controllers with middleware group the routes of their
+// actions and the actions of their nested controllers under the controller's
+// route, so the middleware of /admin runs for every /admin/* route.
+func (l *loader) loadGroups(dirs []string, structs map[string]*parser.Struct) map[string]*Group {
+	groups := map[string]*Group{}
+	sorted := append([]string{}, dirs...)
+	sort.Strings(sorted)
+	for _, dir := range sorted {
+		if !l.hasMiddleware(structs[dir]) {
+			continue
+		}
+		group := &Group{
+			Name:       groupName(dir),
+			Parent:     "router",
+			Middleware: l.loadActionCallName(toBasePath(dir), "Middleware"),
+		}
+		if dir != "." {
+			route := l.loadControllerRoute(toBasePath(dir))
+			route = l.loadActionConstraints(route, l.loadSlots(dir, structs))
+			group.route = route
+			if parent := findGroup(groups, path.Dir(dir)); parent != nil {
+				group.parent = parent
+				group.Parent = parent.Name
+				group.route = withPrefix(parent.route, route)
+			}
+		}
+		group.Prefix = group.route
+		if group.parent != nil {
+			group.Prefix = strings.TrimPrefix(group.route, group.parent.route)
+		}
+		groups[dir] = group
 	}
-	stct := pkg.Struct("Controller")
-	if stct == nil {
-		return nil
+	return groups
+}
+
+// findGroup finds the group of the controller in dir or the closest parent
+// controller that has a group
+func findGroup(groups map[string]*Group, dir string) *Group {
+	for {
+		if group, ok := groups[dir]; ok {
+			return group
+		}
+		if dir == "." {
+			return nil
+		}
+		dir = path.Dir(dir)
+	}
+}
+
+// groupName is the variable name of the group within the generated server
+func groupName(dir string) string {
+	if dir == "." {
+		return "rootGroup"
+	}
+	return text.Camel(dir) + "Group"
+}
+
+// withPrefix replaces the start of the route with the group's route, so the
+// parent slots are constrained in the same way as the group's
+func withPrefix(prefix, route string) string {
+	if prefix == "" {
+		return route
 	}
+	n := len(strings.Split(prefix, "/"))
+	segments := strings.Split(route, "/")
+	if len(segments) <= n {
+		return prefix
+	}
+	return prefix + "/" + strings.Join(segments[n:], "/")
+}
+
+func (l *loader) hasMiddleware(stct *parser.Struct) bool {
+	for _, method := range stct.PublicMethods() {
+		if l.isMiddleware(method) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *loader) loadActions(dir string, structs map[string]*parser.Struct, groups map[string]*Group) (actions []*Action) {
+	stct := structs[dir]
 	basePath := toBasePath(dir)
+	group := findGroup(groups, dir)
+	slots := l.loadSlots(dir, structs)
+	prerender := ""
 	for _, method := range stct.PublicMethods() {
+		if method.Name() == "Prerender" {
+			prerender = l.loadActionCallName(basePath, "Prerender")
+		}
+	}
+	for _, method := range stct.PublicMethods() {
+		if l.isMiddleware(method) || signature.IsLoader(method) || signature.IsHook(method) || method.Name() == "Prerender" {
+			continue
+		}
 		action := new(Action)
 		actionName := method.Name()
+		action.Key = path.Join(basePath, text.Lower(text.Snake(actionName)))
 		action.Method = l.loadActionMethod(actionName)
+		// WebSocket connections are upgraded from GET requests
+		if action.Socket = l.isSocket(method); action.Socket {
+			action.Method = "Get"
+		}
 		action.Route = l.loadActionRoute(l.loadControllerRoute(basePath), actionName)
+		action.Route = l.loadActionConstraints(action.Route, append(method.Params(), slots...))
+		action.Group = "router"
+		action.GroupRoute = action.Route
+		if group != nil {
+			action.Route = withPrefix(group.route, action.Route)
+			action.Group = group.Name
+			action.GroupRoute = strings.TrimPrefix(action.Route, group.route)
+			if action.GroupRoute == "" {
+				action.GroupRoute = "/"
+			}
+			group.use()
+		}
 		action.CallName = l.loadActionCallName(basePath, actionName)
+		action.Prerender = prerender
 		actions = append(actions, action)
 	}
 	return actions
 }
 
+// Load the params of the parent controllers' Load methods, which constrain the
+// parent slots of the nested routes (e.g. /posts/:post_id|int/comments)
+func (l *loader) loadSlots(dir string, structs map[string]*parser.Struct) (params []*parser.Param) {
+	for dir != "." {
+		dir = path.Dir(dir)
+		stct, ok := structs[dir]
+		if !ok {
+			continue
+		}
+		for _, method := range stct.PublicMethods() {
+			if signature.IsLoader(method) {
+				params = append(params, method.Params()...)
+				break
+			}
+		}
+	}
+	return params
+}
+
+// isMiddleware returns true for methods with the following signature:
+// Middleware(next http.Handler) http.Handler
+func (l *loader) isMiddleware(method *parser.Function) bool {
+	isMiddleware, err := signature.IsMiddleware(method)
+	if err != nil {
+		l.Bail(err)
+	}
+	return isMiddleware
+}
+
+// isSocket returns true for actions that take a *websocket.Conn
+func (l *loader) isSocket(method *parser.Function) bool {
+	for _, param := range method.Params() {
+		isSocket, err := parser.IsImportType(param.Type(), "github.com/livebud/bud/framework/controller/controllerrt/websocket", "Conn")
+		if err != nil {
+			l.Bail(err)
+		}
+		if isSocket {
+			return true
+		}
+	}
+	return false
+}
+
 func toBasePath(dir string) string {
 	if dir == "." {
 		return "/"
@@ -167,6 +346,19 @@ func (l *loader) loadActionRoute(basePath, actionName string) string {
 	}
 }
 
+// Constrain the route's slots by the types of the matching action params, so
+// the router skips requests that wouldn't unmarshal (e.g. /users/:id|int)
+func (l *loader) loadActionConstraints(route string, params []*parser.Param) string {
+	dataTypes := map[string]string{}
+	for _, param := range params {
+		name := text.Lower(text.Snake(param.Name()))
+		if _, ok := dataTypes[name]; !ok {
+			dataTypes[name] = param.Type().String()
+		}
+	}
+	return slot.Constrain(route, dataTypes)
+}
+
 func (l *loader) loadActionCallName(basePath, actionName string) string {
 
 	splitPath := strings.Split(text.Title(basePath), " ")
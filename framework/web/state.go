@@ -5,9 +5,11 @@ import "github.com/livebud/bud/internal/imports"
 type State struct {
 	Imports []*imports.Import
 
-	Actions   []*Action
-	HasPublic bool
-	HasView   bool
+	Groups     []*Group
+	Actions    []*Action
+	HasPublic  bool
+	HasView    bool
+	HasOpenAPI bool
 
 	// Show the welcome page
 	ShowWelcome bool
@@ -18,8 +20,37 @@ type Def struct {
 	Name string
 }
 
+// Group of routes that share the route and middleware of a controller (e.g.
+// /admin/*). Groups nest within the groups of their parent controllers.
+type Group struct {
+	Name       string // Variable name of the group
+	Parent     string // Variable name of the parent group or the router
+	Prefix     string // Prefix relative to the parent group
+	Middleware string // Call name of the controller's middleware
+	route      string // Full prefix of the group
+	parent     *Group
+	used       bool
+}
+
+// use marks the group and its parents as having routes
+func (g *Group) use() {
+	for group := g; group != nil; group = group.parent {
+		group.used = true
+	}
+}
+
 type Action struct {
-	Method   string
-	Route    string
-	CallName string
+	Key        string // Name of the route (e.g. /users/show)
+	Method     string
+	Route      string
+	Group      string // Variable name of the group or the router
+	GroupRoute string // Route relative to the group's prefix
+	CallName   string
+	Socket     bool   // Upgrades GET requests to WebSocket connections
+	Prerender  string // Call name of the controller's Prerender, if any
+}
+
+// Prerenderable is true for GET routes that respond with a page
+func (a *Action) Prerenderable() bool {
+	return a.Method == "Get" && !a.Socket
 }
@@ -2,6 +2,8 @@ package web_test
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/livebud/bud/internal/cli/testcli"
@@ -24,3 +26,106 @@ func TestEmptyBuild(t *testing.T) {
 	// Empty builds generate the web directory
 	is.NoErr(td.Exists("bud/internal/app/web"))
 }
+
+func TestToolRoutes(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/controller.go"] = `
+		package controller
+		type Controller struct {}
+		func (c *Controller) Index() string { return "" }
+	`
+	td.Files["controller/users/controller.go"] = `
+		package users
+		type Controller struct {}
+		func (c *Controller) Show(id int) string { return "" }
+		func (c *Controller) Delete(id int) {}
+	`
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	result, err := cli.Run(ctx, "tool", "routes", "--openapi=/openapi.json")
+	is.NoErr(err)
+	is.Equal(result.Stderr(), "")
+	// Routes are listed from the controllers, along with their names
+	stdout := result.Stdout()
+	is.In(stdout, "GET     /               /index\n")
+	is.In(stdout, "GET     /users/:id|int  /users/show\n")
+	is.In(stdout, "DELETE  /users/:id|int  /users/delete\n")
+	is.In(stdout, "GET     /openapi.json\n")
+	// The app isn't built
+	is.NoErr(td.NotExists("bud/app"))
+}
+
+func TestRouteGroups(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["controller/controller.go"] = `
+		package controller
+		type Controller struct {}
+		func (c *Controller) Index() string { return "home" }
+	`
+	// Controllers without actions can still group the routes of their nested
+	// controllers
+	td.Files["controller/admin/controller.go"] = `
+		package admin
+		import "net/http"
+		type Controller struct {}
+		func (c *Controller) Middleware(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("X-Group", "admin")
+				next.ServeHTTP(w, r)
+			})
+		}
+	`
+	td.Files["controller/admin/users/controller.go"] = `
+		package users
+		import "net/http"
+		type Controller struct {}
+		func (c *Controller) Middleware(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("X-Group", "users")
+				next.ServeHTTP(w, r)
+			})
+		}
+		func (c *Controller) Index() string { return "users" }
+		func (c *Controller) Show(id int) int { return id }
+	`
+	is.NoErr(td.Write(ctx))
+	cli := testcli.New(dir)
+	app, err := cli.Start(ctx, "run")
+	is.NoErr(err)
+	defer app.Close()
+	// The web server registers the actions within nested groups
+	code, err := os.ReadFile(filepath.Join(dir, "bud/internal/app/web/web.go"))
+	is.NoErr(err)
+	is.In(string(code), "adminGroup := router.Group(`/admin`, controller.AdminController.Middleware)")
+	is.In(string(code), "adminUsersGroup := adminGroup.Group(`/users`, controller.AdminController.UsersController.Middleware)")
+	is.In(string(code), "adminUsersGroup.Get(`/`, controller.AdminController.UsersController.Index)")
+	is.In(string(code), "adminUsersGroup.Get(`/:id|int`, controller.AdminController.UsersController.Show)")
+	is.In(string(code), "router.Get(`/`, controller.Index)")
+	// Routes outside of the group don't run its middleware
+	res, err := app.GetJSON("/")
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	is.Equal(res.Header("X-Group"), "")
+	// The outer group's middleware runs first
+	res, err = app.GetJSON("/admin/users")
+	is.NoErr(err)
+	is.Equal(res.Status(), 200)
+	is.Equal(res.Body().String(), `"users"`)
+	res, err = app.GetJSON("/admin/users/10")
+	is.NoErr(err)
+	is.NoErr(res.Diff(`
+		HTTP/1.1 200 OK
+		Content-Type: application/json
+		X-Group: admin
+		X-Group: users
+
+		10
+	`))
+	is.NoErr(app.Close())
+}
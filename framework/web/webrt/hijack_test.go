@@ -0,0 +1,45 @@
+package webrt
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/livebud/bud/internal/is"
+)
+
+func TestHijackAfterClose(t *testing.T) {
+	is := is.New(t)
+	h := newHijacked()
+	ctx := context.WithValue(context.Background(), hijackedKey{}, h)
+	h.close()
+	shutdown, release := Hijack(ctx)
+	select {
+	case <-shutdown:
+	default:
+		is.Fail() // expected the shutdown channel to be closed
+	}
+	// Releasing doesn't close the idle channel again
+	release()
+	waitCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	h.wait(waitCtx)
+	is.NoErr(waitCtx.Err())
+}
+
+func TestHijackBeforeClose(t *testing.T) {
+	is := is.New(t)
+	h := newHijacked()
+	ctx := context.WithValue(context.Background(), hijackedKey{}, h)
+	shutdown, release := Hijack(ctx)
+	h.close()
+	<-shutdown
+	// Waits for the connection to be released
+	waitCtx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	h.wait(waitCtx)
+	is.True(waitCtx.Err() != nil)
+	release()
+	release()
+	h.wait(context.Background())
+}
@@ -7,6 +7,7 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"sync"
 
 	"github.com/livebud/bud/internal/extrafile"
 	"github.com/livebud/bud/internal/sig"
@@ -34,10 +35,18 @@ func Listen(prefix, path string) (socket.Listener, error) {
 
 // Serve the handler at address
 func Serve(ctx context.Context, listener net.Listener, handler http.Handler) error {
+	hijacked := newHijacked()
 	// Create the HTTP server
-	server := &http.Server{Addr: listener.Addr().String(), Handler: handler}
+	server := &http.Server{
+		Addr:    listener.Addr().String(),
+		Handler: handler,
+		BaseContext: func(net.Listener) context.Context {
+			return context.WithValue(context.Background(), hijackedKey{}, hijacked)
+		},
+	}
+	server.RegisterOnShutdown(hijacked.close)
 	// Make the server shutdownable
-	shutdown := shutdown(ctx, server)
+	shutdown := shutdown(ctx, server, hijacked)
 	// Serve requests
 	if err := server.Serve(listener); err != nil {
 		if !errors.Is(err, http.ErrServerClosed) {
@@ -54,7 +63,7 @@ func Serve(ctx context.Context, listener net.Listener, handler http.Handler) err
 }
 
 // Shutdown the server when the context is canceled
-func shutdown(ctx context.Context, server *http.Server) <-chan error {
+func shutdown(ctx context.Context, server *http.Server, hijacked *hijacked) <-chan error {
 	shutdown := make(chan error, 1)
 	go func() {
 		<-ctx.Done()
@@ -63,11 +72,90 @@ func shutdown(ctx context.Context, server *http.Server) <-chan error {
 		if err := server.Shutdown(forceCtx); err != nil {
 			shutdown <- err
 		}
+		// Shutdown doesn't wait for hijacked connections, so wait for them here
+		hijacked.wait(forceCtx)
 		close(shutdown)
 	}()
 	return shutdown
 }
 
+type hijackedKey struct{}
+
+// hijacked tracks long-lived connections that have been taken over from the
+// HTTP server, like WebSockets
+type hijacked struct {
+	mu      sync.Mutex
+	active  int
+	closed  bool
+	closing chan struct{} // closed when the server starts shutting down
+	idle    chan struct{} // closed when shutting down without active connections
+}
+
+func newHijacked() *hijacked {
+	return &hijacked{
+		closing: make(chan struct{}),
+		idle:    make(chan struct{}),
+	}
+}
+
+// add a connection unless the server is already shutting down
+func (h *hijacked) add() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	h.active++
+	return true
+}
+
+func (h *hijacked) done() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.active--
+	if h.closed && h.active == 0 {
+		close(h.idle)
+	}
+}
+
+func (h *hijacked) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	h.closed = true
+	close(h.closing)
+	if h.active == 0 {
+		close(h.idle)
+	}
+}
+
+func (h *hijacked) wait(ctx context.Context) {
+	select {
+	case <-h.idle:
+	case <-ctx.Done():
+	}
+}
+
+// Hijack registers a long-lived connection, like a WebSocket, that outlives
+// the server's graceful shutdown. The returned channel is closed when the
+// server starts shutting down and the server waits for release to be called
+// before Serve returns. Requests that weren't served by Serve get a channel
+// that's never closed. Connections hijacked once the server is shutting down
+// get a closed channel and aren't waited for.
+func Hijack(ctx context.Context) (shutdown <-chan struct{}, release func()) {
+	h, ok := ctx.Value(hijackedKey{}).(*hijacked)
+	if !ok {
+		return nil, func() {}
+	}
+	if !h.add() {
+		return h.closing, func() {}
+	}
+	var once sync.Once
+	return h.closing, func() { once.Do(h.done) }
+}
+
 // Format a listener
 func Format(l net.Listener) string {
 	address := l.Addr().String()
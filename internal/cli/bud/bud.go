@@ -20,9 +20,12 @@ import (
 
 	"github.com/livebud/bud/framework"
 	"github.com/livebud/bud/framework/app"
+	"github.com/livebud/bud/framework/client"
+	"github.com/livebud/bud/framework/client/tsclient"
 	"github.com/livebud/bud/framework/controller"
 	"github.com/livebud/bud/framework/generate"
 	"github.com/livebud/bud/framework/generator"
+	"github.com/livebud/bud/framework/openapi"
 	"github.com/livebud/bud/framework/public"
 	"github.com/livebud/bud/framework/transform/transformrt"
 	"github.com/livebud/bud/framework/view"
@@ -43,6 +46,7 @@ import (
 	"github.com/livebud/bud/package/socket"
 	"github.com/livebud/bud/package/svelte"
 	"github.com/livebud/bud/package/vfs"
+	"github.com/livebud/bud/package/vue"
 )
 
 // Input contains the configuration that gets passed into the commands
@@ -147,11 +151,7 @@ func FileSystem(ctx context.Context, log log.Interface, module *gomod.Module, fl
 	if err != nil {
 		return nil, closer, err
 	}
-	svelteCompiler, err := svelte.Load(vm)
-	if err != nil {
-		return nil, closer, err
-	}
-	transforms, err := transformrt.Load(svelte.NewTransformable(svelteCompiler))
+	transforms, err := Transforms(module, vm)
 	if err != nil {
 		return nil, closer, err
 	}
@@ -160,6 +160,9 @@ func FileSystem(ctx context.Context, log log.Interface, module *gomod.Module, fl
 	genfs.FileGenerator("bud/internal/app/controller/controller.go", controller.New(injector, module, parser))
 	genfs.FileGenerator("bud/internal/app/view/view.go", view.New(module, transforms, flag))
 	genfs.FileGenerator("bud/internal/app/public/public.go", public.New(flag))
+	genfs.FileGenerator("bud/internal/openapi/openapi.go", openapi.New(injector, module, parser, flag))
+	genfs.FileGenerator("bud/client/client.go", client.New(injector, module, parser))
+	genfs.FileGenerator("bud/client/client.ts", tsclient.New(injector, module, parser))
 	genfs.FileGenerator("bud/internal/generate/main.go", generate.New(injector, module))
 	genfs.FileGenerator("bud/internal/generate/generator/generator.go", generator.New(module, parser))
 	// Sync generate now to support custom generators, if any
@@ -213,20 +216,37 @@ func FileServer(log log.Interface, module *gomod.Module, vm js.VM, flag *framewo
 	if err != nil {
 		return nil, err
 	}
-	svelteCompiler, err := svelte.Load(vm)
-	if err != nil {
-		return nil, err
-	}
-	transforms, err := transformrt.Load(svelte.NewTransformable(svelteCompiler))
+	transforms, err := Transforms(module, vm)
 	if err != nil {
 		return nil, err
 	}
 	servefs.FileGenerator("bud/view/_ssr.js", ssr.New(module, transforms.SSR))
+	servefs.FileGenerator("bud/view/_client.js", tsclient.NewCompiler())
 	servefs.FileServer("bud/view", dom.New(module, transforms.DOM))
 	servefs.FileServer("bud/node_modules", dom.NodeModules(module))
 	return servefs, nil
 }
 
+// Transforms loads the view compilers. Vue is compiled with the application's
+// own compiler, so it's only supported when Vue is installed.
+func Transforms(module *gomod.Module, vm js.VM) (*transformrt.Map, error) {
+	svelteCompiler, err := svelte.Load(vm)
+	if err != nil {
+		return nil, err
+	}
+	transformables := []*transformrt.Transformable{
+		svelte.NewTransformable(svelteCompiler),
+	}
+	if err := vfs.Exist(module, "node_modules/vue/package.json"); nil == err {
+		vueCompiler, err := vue.Load(vm, module)
+		if err != nil {
+			return nil, err
+		}
+		transformables = append(transformables, vue.NewTransformable(vueCompiler))
+	}
+	return transformrt.Load(transformables...)
+}
+
 // EnsureVersionAlignment ensures that the CLI and runtime versions are aligned.
 // If they're not aligned, the CLI will correct the go.mod file to align them.
 func EnsureVersionAlignment(ctx context.Context, module *gomod.Module, budVersion string) error {
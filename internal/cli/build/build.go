@@ -2,6 +2,11 @@ package build
 
 import (
 	"context"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
 
 	"github.com/livebud/bud/framework"
 	"github.com/livebud/bud/internal/cli/bud"
@@ -23,6 +28,8 @@ type Command struct {
 	bud  *bud.Command
 	in   *bud.Input
 	Flag *framework.Flag
+	// Prerender pages into this directory, if set
+	Prerender string
 }
 
 // Run the build command
@@ -49,6 +56,54 @@ func (c *Command) Run(ctx context.Context) error {
 	if err := genfs.Sync("bud/internal"); err != nil {
 		return err
 	}
+	// Generate the client for other services
+	if err := genfs.Sync("bud/client"); err != nil {
+		return err
+	}
 	builder := gobuild.New(module)
-	return builder.Build(ctx, "bud/internal/app/main.go", "bud/app")
+	if err := builder.Build(ctx, "bud/internal/app/main.go", "bud/app"); err != nil {
+		return err
+	}
+	if c.Prerender == "" {
+		return nil
+	}
+	return c.prerender(ctx, module.Directory())
+}
+
+// prerender the pages by running the app's prerender command. Public files
+// are prerendered alongside the pages, even when the pages don't link to them.
+func (c *Command) prerender(ctx context.Context, dir string) error {
+	args := []string{"prerender"}
+	publicPaths, err := listPublic(os.DirFS(dir))
+	if err != nil {
+		return err
+	}
+	for _, publicPath := range publicPaths {
+		args = append(args, "--path", publicPath)
+	}
+	args = append(args, c.Prerender)
+	cmd := exec.CommandContext(ctx, filepath.Join(dir, "bud", "app"), args...)
+	cmd.Dir = dir
+	cmd.Stdin = c.in.Stdin
+	cmd.Stdout = c.in.Stdout
+	cmd.Stderr = c.in.Stderr
+	cmd.Env = c.in.Env
+	return cmd.Run()
+}
+
+// listPublic lists the URL paths of the public files
+func listPublic(fsys fs.FS) (paths []string, err error) {
+	err = fs.WalkDir(fsys, "public", func(filePath string, de fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		} else if de.IsDir() {
+			return nil
+		}
+		paths = append(paths, path.Join("/", path.Clean(filePath[len("public"):])))
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return paths, nil
 }
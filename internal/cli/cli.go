@@ -5,6 +5,7 @@ import (
 	"errors"
 	"runtime"
 
+	"github.com/livebud/bud/framework"
 	"github.com/livebud/bud/internal/cli/bud"
 	"github.com/livebud/bud/internal/cli/build"
 	"github.com/livebud/bud/internal/cli/create"
@@ -16,6 +17,7 @@ import (
 	"github.com/livebud/bud/internal/cli/toolfscat"
 	"github.com/livebud/bud/internal/cli/toolfsls"
 	"github.com/livebud/bud/internal/cli/toolfstxtar"
+	"github.com/livebud/bud/internal/cli/toolroutes"
 	"github.com/livebud/bud/internal/cli/toolv8"
 	"github.com/livebud/bud/internal/cli/version"
 	"github.com/livebud/bud/internal/versions"
@@ -62,6 +64,7 @@ func (c *CLI) Run(ctx context.Context, args ...string) error {
 		cli.Flag("hot", "hot reloading").Bool(&cmd.Flag.Hot).Default(true)
 		cli.Flag("minify", "minify assets").Bool(&cmd.Flag.Minify).Default(false)
 		cli.Flag("listen", "address to listen to").String(&cmd.Listen).Default(":3000")
+		openapiFlag(cli, cmd.Flag)
 		cli.Run(cmd.Run)
 	}
 
@@ -70,6 +73,8 @@ func (c *CLI) Run(ctx context.Context, args ...string) error {
 		cli := cli.Command("build", "build your app into a single binary")
 		cli.Flag("embed", "embed assets").Bool(&cmd.Flag.Embed).Default(true)
 		cli.Flag("minify", "minify assets").Bool(&cmd.Flag.Minify).Default(true)
+		openapiFlag(cli, cmd.Flag)
+		cli.Flag("prerender", "prerender pages into a directory").String(&cmd.Prerender).Default("")
 		cli.Run(cmd.Run)
 	}
 
@@ -95,6 +100,7 @@ func (c *CLI) Run(ctx context.Context, args ...string) error {
 			cli.Flag("embed", "embed assets").Bool(&cmd.Flag.Embed).Default(false)
 			cli.Flag("hot", "hot reloading").Bool(&cmd.Flag.Hot).Default(true)
 			cli.Flag("minify", "minify assets").Bool(&cmd.Flag.Minify).Default(false)
+			openapiFlag(cli, cmd.Flag)
 			cli.Run(cmd.Run)
 		}
 
@@ -119,6 +125,7 @@ func (c *CLI) Run(ctx context.Context, args ...string) error {
 				cli.Flag("embed", "embed assets").Bool(&cmd.Flag.Embed).Default(false)
 				cli.Flag("hot", "hot reloading").Bool(&cmd.Flag.Hot).Default(true)
 				cli.Flag("minify", "minify assets").Bool(&cmd.Flag.Minify).Default(false)
+				openapiFlag(cli, cmd.Flag)
 				cli.Arg("dir").String(&cmd.Dir).Default(".")
 				cli.Run(cmd.Run)
 			}
@@ -130,6 +137,7 @@ func (c *CLI) Run(ctx context.Context, args ...string) error {
 				cli.Flag("embed", "embed assets").Bool(&cmd.Flag.Embed).Default(false)
 				cli.Flag("hot", "hot reloading").Bool(&cmd.Flag.Hot).Default(true)
 				cli.Flag("minify", "minify assets").Bool(&cmd.Flag.Minify).Default(false)
+				openapiFlag(cli, cmd.Flag)
 				cli.Arg("path").String(&cmd.Path)
 				cli.Run(cmd.Run)
 			}
@@ -141,10 +149,18 @@ func (c *CLI) Run(ctx context.Context, args ...string) error {
 				cli.Flag("embed", "embed assets").Bool(&cmd.Flag.Embed).Default(false)
 				cli.Flag("hot", "hot reloading").Bool(&cmd.Flag.Hot).Default(true)
 				cli.Flag("minify", "minify assets").Bool(&cmd.Flag.Minify).Default(false)
+				openapiFlag(cli, cmd.Flag)
 				cli.Run(cmd.Run)
 			}
 		}
 
+		{ // $ bud tool routes
+			cmd := toolroutes.New(cmd, c.in)
+			cli := cli.Command("routes", "list the app's routes")
+			openapiFlag(cli, cmd.Flag)
+			cli.Run(cmd.Run)
+		}
+
 		{ // $ bud tool v8
 			cmd := toolv8.New(c.in.Stdin, c.in.Stdout)
 			cli := cli.Command("v8", "execute Javascript with V8 from stdin")
@@ -179,3 +195,9 @@ func (c *CLI) Run(ctx context.Context, args ...string) error {
 	}
 	return nil
 }
+
+// openapiFlag serves the OpenAPI document from a path. Serving the document is
+// opt-in.
+func openapiFlag(cli commander.Command, flag *framework.Flag) {
+	cli.Flag("openapi", "serve the OpenAPI document from this path").String(&flag.OpenAPI).Default("")
+}
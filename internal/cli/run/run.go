@@ -195,6 +195,12 @@ func (a *appServer) Run(ctx context.Context) error {
 		a.log.Debug("run: published event", "event", "app:error")
 		return err
 	}
+	// Generate the client for other services
+	if err := a.genfs.Sync("bud/client"); err != nil {
+		a.bus.Publish("app:error", []byte(err.Error()))
+		a.log.Debug("run: published event", "event", "app:error")
+		return err
+	}
 	// Build the app
 	if err := a.builder.Build(ctx, "bud/internal/app/main.go", "bud/app"); err != nil {
 		a.bus.Publish("app:error", []byte(err.Error()))
@@ -237,6 +243,9 @@ func (a *appServer) Run(ctx context.Context) error {
 		if err := a.genfs.Sync("bud/internal"); err != nil {
 			return err
 		}
+		if err := a.genfs.Sync("bud/client"); err != nil {
+			return err
+		}
 		// Build the app
 		if err := a.builder.Build(ctx, "bud/internal/app/main.go", "bud/app"); err != nil {
 			return err
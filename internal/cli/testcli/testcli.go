@@ -26,6 +26,7 @@ import (
 	"github.com/livebud/bud/package/log/testlog"
 	"github.com/livebud/bud/package/socket"
 
+	"golang.org/x/net/websocket"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/livebud/bud/internal/cli"
@@ -197,6 +198,24 @@ func (c *Client) Hot(path string) (*hot.Stream, error) {
 	return hot.DialWith(c.hotc, c.log, getURL(path))
 }
 
+// Socket connects to a WebSocket endpoint
+func (c *Client) Socket(path string) (*websocket.Conn, error) {
+	transport, ok := c.webc.Transport.(*http.Transport)
+	if !ok {
+		return nil, fmt.Errorf("testcli: unable to dial websockets with %T", c.webc.Transport)
+	}
+	conn, err := transport.DialContext(context.Background(), "tcp", "host:80")
+	if err != nil {
+		return nil, err
+	}
+	config, err := websocket.NewConfig("ws://host"+path, "http://host")
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return websocket.NewClient(config, conn)
+}
+
 func bufferHeaders(res *http.Response, body []byte) ([]byte, error) {
 	// Coerce mime types before buffering the header
 	if err := coerceMimes(res); err != nil {
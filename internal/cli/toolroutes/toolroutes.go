@@ -0,0 +1,59 @@
+package toolroutes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/livebud/bud/framework"
+	"github.com/livebud/bud/framework/web"
+	"github.com/livebud/bud/internal/cli/bud"
+	"github.com/livebud/bud/package/parser"
+)
+
+func New(bud *bud.Command, in *bud.Input) *Command {
+	return &Command{
+		bud:  bud,
+		in:   in,
+		Flag: new(framework.Flag),
+	}
+}
+
+type Command struct {
+	bud  *bud.Command
+	in   *bud.Input
+	Flag *framework.Flag
+}
+
+// Run lists the routes in the order the generated web server adds them to its
+// router. The routes are loaded from the app's controllers, so the app isn't
+// built or run.
+func (c *Command) Run(ctx context.Context) error {
+	log, err := bud.Log(c.in.Stderr, c.bud.Log)
+	if err != nil {
+		return err
+	}
+	module, err := bud.Module(c.bud.Dir)
+	if err != nil {
+		return err
+	}
+	fsys, close, err := bud.FileSystem(ctx, log, module, c.Flag, c.in)
+	if err != nil {
+		return err
+	}
+	defer close()
+	state, err := web.Load(fsys, module, parser.New(fsys, module))
+	if err != nil {
+		return err
+	}
+	// Print out the route table
+	tw := tabwriter.NewWriter(c.in.Stdout, 0, 0, 2, ' ', 0)
+	for _, action := range state.Actions {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", strings.ToUpper(action.Method), action.Route, action.Key)
+	}
+	if state.HasOpenAPI {
+		fmt.Fprintf(tw, "GET\t%s\n", c.Flag.OpenAPI)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,91 @@
+package entrypoint
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/livebud/bud/internal/valid"
+)
+
+// islandExt is the extension of islands (e.g. view/Counter.island.svelte)
+const islandExt = ".island.svelte"
+
+// Island is an interactive component within an otherwise static page. Pages
+// that render islands only hydrate their islands.
+type Island struct {
+	Component Path   // Path to the component
+	Type      string // View extension
+	Client    string // Path to the island's client-side entry
+}
+
+// IsIsland returns true if the file is an island
+func IsIsland(name string) bool {
+	return strings.HasSuffix(name, islandExt)
+}
+
+// ListIslands lists the islands within the directory and its subdirectories
+func ListIslands(fsys fs.FS, paths ...string) (islands []*Island, err error) {
+	dir := path.Clean(path.Join(paths...))
+	err = fs.WalkDir(fsys, dir, func(fullpath string, de fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := de.Name()
+		if de.IsDir() {
+			if fullpath != dir && !valid.Dir(name) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !IsIsland(name) || name[0] == '_' || name[0] == '.' {
+			return nil
+		}
+		islands = append(islands, &Island{
+			Component: Path(fullpath),
+			Type:      strings.TrimPrefix(path.Ext(name), "."),
+			Client:    client(fullpath),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(islands, func(i, j int) bool {
+		return islands[i].Component < islands[j].Component
+	})
+	return islands, nil
+}
+
+// FindIslandByClient finds the island by the path to its client-side entry
+func FindIslandByClient(fsys fs.FS, client string) (*Island, error) {
+	islands, err := ListIslands(fsys, "view")
+	if err != nil {
+		return nil, err
+	}
+	client = filepath.Clean(client)
+	for _, island := range islands {
+		if island.Client == client {
+			return island, nil
+		}
+	}
+	return nil, fmt.Errorf("unable to find island by client path %q", client)
+}
+
+// FindIsland finds the island by the path to its component
+func FindIsland(fsys fs.FS, component string) (*Island, error) {
+	islands, err := ListIslands(fsys, "view")
+	if err != nil {
+		return nil, err
+	}
+	component = path.Clean(component)
+	for _, island := range islands {
+		if string(island.Component) == component {
+			return island, nil
+		}
+	}
+	return nil, fmt.Errorf("unable to find island %q", component)
+}
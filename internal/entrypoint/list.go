@@ -21,7 +21,7 @@ func List(fsys fs.FS, paths ...string) ([]*View, error) {
 		return nil, err
 	}
 	// Turn the tree of views into a list of views
-	views, err := listViews(fsys, tree, path.Clean(path.Join(paths...)))
+	views, err := listViews(fsys, tree, path.Clean(path.Join(paths...)), "")
 	if err != nil {
 		return nil, err
 	}
@@ -59,17 +59,28 @@ func buildTree(fsys fs.FS, dir string) (*tree, error) {
 		}
 		ext := path.Ext(name)
 		switch extless(name) {
-		case "Error":
+		case "Error", "error":
 			tree.error[ext] = Path(fullpath)
-		case "Layout":
+		case "Layout", "layout":
 			tree.layout[ext] = Path(fullpath)
-		case "Frame":
+		case "Frame", "frame":
 			tree.frame[ext] = Path(fullpath)
 		}
 	}
 	return tree, nil
 }
 
+// isReserved returns true for views that wrap the pages rather than being
+// pages themselves (e.g. view/layout.svelte)
+func isReserved(name string) bool {
+	switch extless(name) {
+	case "error", "layout", "frame":
+		return true
+	default:
+		return false
+	}
+}
+
 // reservedTree of reserved views
 type tree struct {
 	error   map[string]Path
@@ -123,7 +134,9 @@ func splitRoot(dir string) (root, rest string) {
 	return parts[0], parts[1]
 }
 
-func listViews(fsys fs.FS, tree *tree, dir string) (views []*View, err error) {
+// listViews lists the views in dir. The reserved views are looked up in the
+// tree by rel, the path of dir relative to the tree's root.
+func listViews(fsys fs.FS, tree *tree, dir, rel string) (views []*View, err error) {
 	fis, err := fs.ReadDir(fsys, dir)
 	if err != nil {
 		return nil, err
@@ -135,28 +148,27 @@ func listViews(fsys fs.FS, tree *tree, dir string) (views []*View, err error) {
 			if !valid.Dir(name) {
 				continue
 			}
-			subviews, err := listViews(fsys, tree, fullpath)
+			subviews, err := listViews(fsys, tree, fullpath, path.Join(rel, name))
 			if err != nil {
 				return nil, err
 			}
 			views = append(views, subviews...)
 			continue
 		}
-		if !valid.ViewEntry(name) {
+		if !valid.ViewEntry(name) || isReserved(name) || IsIsland(name) {
 			continue
 		}
 		ext := path.Ext(name)
-		// TODO: remove this constraint after we have sufficient testing
-		if ext != ".svelte" {
+		if !isViewExt(ext) {
 			continue
 		}
 		views = append(views, &View{
 			Page:   Path(fullpath),
 			Client: client(fullpath),
 			Route:  route(dir, name),
-			Frames: tree.Frames(dir, ext),
-			Layout: tree.Layout(dir, ext),
-			Error:  tree.Error(dir, ext),
+			Frames: tree.Frames(rel, ext),
+			Layout: tree.Layout(rel, ext),
+			Error:  tree.Error(rel, ext),
 			Type:   strings.TrimPrefix(ext, "."),
 			Hot:    ":35729", // TODO: configurable
 		})
@@ -164,6 +176,16 @@ func listViews(fsys fs.FS, tree *tree, dir string) (views []*View, err error) {
 	return views, nil
 }
 
+// isViewExt returns true for the extensions that pages can have
+func isViewExt(ext string) bool {
+	switch ext {
+	case ".svelte", ".jsx", ".tsx", ".vue":
+		return true
+	default:
+		return false
+	}
+}
+
 // Generate the IDs for a nested route
 // TODO: consolidate with the function in internal/generator/action/loader.go.
 func routeDir(dir string) string {
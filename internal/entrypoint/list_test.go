@@ -37,50 +37,60 @@ func TestList(t *testing.T) {
 	}
 	views, err := entrypoint.List(fsys)
 	is.NoErr(err)
-	is.Equal(len(views), 6)
-	// index.svelte
-	is.Equal(views[0].Page, entrypoint.Path("view/index.svelte"))
+	is.Equal(len(views), 7)
+	// about.jsx
+	is.Equal(views[0].Page, entrypoint.Path("view/about.jsx"))
 	is.Equal(len(views[0].Frames), 1)
-	is.Equal(views[0].Frames[0], entrypoint.Path("view/Frame.svelte"))
-	is.Equal(views[0].Layout, entrypoint.Path("view/Layout.svelte"))
-	is.Equal(views[0].Error, entrypoint.Path("view/Error.svelte"))
-	is.Equal(views[0].Type, "svelte")
-	is.Equal(views[0].Route, "/")
-	is.Equal(views[0].Client, "bud/view/_index.svelte.js")
-	is.Equal(views[0].Hot, ":35729")
-	// user/edit.svelte
-	is.Equal(views[1].Page, entrypoint.Path("view/user/edit.svelte"))
-	is.Equal(len(views[1].Frames), 2)
+	is.Equal(views[0].Frames[0], entrypoint.Path("view/Frame.jsx"))
+	is.Equal(views[0].Layout, entrypoint.Path("view/Layout.jsx"))
+	is.Equal(views[0].Error, entrypoint.Path(""))
+	is.Equal(views[0].Type, "jsx")
+	is.Equal(views[0].Runtime(), "jsx")
+	is.Equal(views[0].Route, "/about")
+	is.Equal(views[0].Client, "bud/view/_about.jsx.js")
+	// index.svelte
+	is.Equal(views[1].Page, entrypoint.Path("view/index.svelte"))
+	is.Equal(len(views[1].Frames), 1)
 	is.Equal(views[1].Frames[0], entrypoint.Path("view/Frame.svelte"))
-	is.Equal(views[1].Frames[1], entrypoint.Path("view/user/Frame.svelte"))
 	is.Equal(views[1].Layout, entrypoint.Path("view/Layout.svelte"))
-	is.Equal(views[1].Error, entrypoint.Path("view/user/Error.svelte"))
+	is.Equal(views[1].Error, entrypoint.Path("view/Error.svelte"))
 	is.Equal(views[1].Type, "svelte")
-	is.Equal(views[1].Route, "/user/:id/edit")
-	is.Equal(views[1].Client, "bud/view/user/_edit.svelte.js")
+	is.Equal(views[1].Route, "/")
+	is.Equal(views[1].Client, "bud/view/_index.svelte.js")
 	is.Equal(views[1].Hot, ":35729")
-	// user/index.svelte
-	is.Equal(views[2].Page, entrypoint.Path("view/user/index.svelte"))
+	// user/edit.svelte
+	is.Equal(views[2].Page, entrypoint.Path("view/user/edit.svelte"))
 	is.Equal(len(views[2].Frames), 2)
 	is.Equal(views[2].Frames[0], entrypoint.Path("view/Frame.svelte"))
 	is.Equal(views[2].Frames[1], entrypoint.Path("view/user/Frame.svelte"))
 	is.Equal(views[2].Layout, entrypoint.Path("view/Layout.svelte"))
 	is.Equal(views[2].Error, entrypoint.Path("view/user/Error.svelte"))
 	is.Equal(views[2].Type, "svelte")
-	is.Equal(views[2].Route, "/user")
-	is.Equal(views[2].Client, "bud/view/user/_index.svelte.js")
+	is.Equal(views[2].Route, "/user/:id/edit")
+	is.Equal(views[2].Client, "bud/view/user/_edit.svelte.js")
 	is.Equal(views[2].Hot, ":35729")
-	// visitor/comments/index.svelte
-	is.Equal(views[3].Page, entrypoint.Path("view/visitor/comments/edit.svelte"))
+	// user/index.svelte
+	is.Equal(views[3].Page, entrypoint.Path("view/user/index.svelte"))
 	is.Equal(len(views[3].Frames), 2)
 	is.Equal(views[3].Frames[0], entrypoint.Path("view/Frame.svelte"))
-	is.Equal(views[3].Frames[1], entrypoint.Path("view/visitor/comments/Frame.svelte"))
-	is.Equal(views[3].Layout, entrypoint.Path("view/visitor/comments/Layout.svelte"))
-	is.Equal(views[3].Error, entrypoint.Path("view/visitor/comments/Error.svelte"))
+	is.Equal(views[3].Frames[1], entrypoint.Path("view/user/Frame.svelte"))
+	is.Equal(views[3].Layout, entrypoint.Path("view/Layout.svelte"))
+	is.Equal(views[3].Error, entrypoint.Path("view/user/Error.svelte"))
 	is.Equal(views[3].Type, "svelte")
-	is.Equal(views[3].Route, "/visitor/:visitor_id/comments/:id/edit")
-	is.Equal(views[3].Client, "bud/view/visitor/comments/_edit.svelte.js")
+	is.Equal(views[3].Route, "/user")
+	is.Equal(views[3].Client, "bud/view/user/_index.svelte.js")
 	is.Equal(views[3].Hot, ":35729")
+	// visitor/comments/index.svelte
+	is.Equal(views[4].Page, entrypoint.Path("view/visitor/comments/edit.svelte"))
+	is.Equal(len(views[4].Frames), 2)
+	is.Equal(views[4].Frames[0], entrypoint.Path("view/Frame.svelte"))
+	is.Equal(views[4].Frames[1], entrypoint.Path("view/visitor/comments/Frame.svelte"))
+	is.Equal(views[4].Layout, entrypoint.Path("view/visitor/comments/Layout.svelte"))
+	is.Equal(views[4].Error, entrypoint.Path("view/visitor/comments/Error.svelte"))
+	is.Equal(views[4].Type, "svelte")
+	is.Equal(views[4].Route, "/visitor/:visitor_id/comments/:id/edit")
+	is.Equal(views[4].Client, "bud/view/visitor/comments/_edit.svelte.js")
+	is.Equal(views[4].Hot, ":35729")
 }
 
 func TestListUnderscore(t *testing.T) {
@@ -112,3 +122,116 @@ func TestListUnderscore(t *testing.T) {
 	is.Equal(views[1].Client, "bud/_vip_users.svelte.js")
 	is.Equal(views[1].Hot, ":35729")
 }
+
+func TestListLowercase(t *testing.T) {
+	is := is.New(t)
+	fsys := vfs.Map{
+		"view/layout.svelte":               []byte(""),
+		"view/frame.svelte":                []byte(""),
+		"view/error.svelte":                []byte(""),
+		"view/index.svelte":                []byte(""),
+		"view/posts/frame.svelte":          []byte(""),
+		"view/posts/show.svelte":           []byte(""),
+		"view/posts/comments/error.svelte": []byte(""),
+		"view/posts/comments/frame.svelte": []byte(""),
+		"view/posts/comments/index.svelte": []byte(""),
+	}
+	views, err := entrypoint.List(fsys, "view")
+	is.NoErr(err)
+	// Layouts, frames and errors aren't pages
+	is.Equal(len(views), 3)
+	is.Equal(views[0].Page, entrypoint.Path("view/index.svelte"))
+	is.Equal(len(views[0].Frames), 1)
+	is.Equal(views[0].Frames[0], entrypoint.Path("view/frame.svelte"))
+	is.Equal(views[0].Layout, entrypoint.Path("view/layout.svelte"))
+	is.Equal(views[0].Error, entrypoint.Path("view/error.svelte"))
+	is.Equal(views[1].Page, entrypoint.Path("view/posts/comments/index.svelte"))
+	is.Equal(len(views[1].Frames), 3)
+	is.Equal(views[1].Frames[0], entrypoint.Path("view/frame.svelte"))
+	is.Equal(views[1].Frames[1], entrypoint.Path("view/posts/frame.svelte"))
+	is.Equal(views[1].Frames[2], entrypoint.Path("view/posts/comments/frame.svelte"))
+	is.Equal(views[1].Layout, entrypoint.Path("view/layout.svelte"))
+	is.Equal(views[1].Error, entrypoint.Path("view/posts/comments/error.svelte"))
+	is.Equal(views[1].Route, "/posts/:post_id/comments")
+	is.Equal(views[2].Page, entrypoint.Path("view/posts/show.svelte"))
+	is.Equal(len(views[2].Frames), 2)
+	is.Equal(views[2].Error, entrypoint.Path("view/error.svelte"))
+}
+
+func TestListTSX(t *testing.T) {
+	is := is.New(t)
+	fsys := vfs.Map{
+		"view/layout.tsx":      []byte(""),
+		"view/frame.tsx":       []byte(""),
+		"view/frame.svelte":    []byte(""),
+		"view/posts/index.tsx": []byte(""),
+		"view/posts/Card.tsx":  []byte(""),
+		"view/posts/notes.md":  []byte(""),
+	}
+	views, err := entrypoint.List(fsys, "view")
+	is.NoErr(err)
+	is.Equal(len(views), 1)
+	is.Equal(views[0].Page, entrypoint.Path("view/posts/index.tsx"))
+	// Frames and layouts only wrap pages of the same type
+	is.Equal(len(views[0].Frames), 1)
+	is.Equal(views[0].Frames[0], entrypoint.Path("view/frame.tsx"))
+	is.Equal(views[0].Layout, entrypoint.Path("view/layout.tsx"))
+	is.Equal(views[0].Type, "tsx")
+	is.Equal(views[0].Runtime(), "jsx")
+	is.Equal(views[0].Route, "/posts")
+	is.Equal(views[0].Client, "bud/view/posts/_index.tsx.js")
+}
+
+func TestListVue(t *testing.T) {
+	is := is.New(t)
+	fsys := vfs.Map{
+		"view/layout.vue":     []byte(""),
+		"view/error.vue":      []byte(""),
+		"view/posts/show.vue": []byte(""),
+		"view/posts/Card.vue": []byte(""),
+	}
+	views, err := entrypoint.List(fsys, "view")
+	is.NoErr(err)
+	is.Equal(len(views), 1)
+	is.Equal(views[0].Page, entrypoint.Path("view/posts/show.vue"))
+	is.Equal(views[0].Layout, entrypoint.Path("view/layout.vue"))
+	is.Equal(views[0].Error, entrypoint.Path("view/error.vue"))
+	is.Equal(views[0].Type, "vue")
+	is.Equal(views[0].Runtime(), "vue")
+	is.Equal(views[0].Route, "/posts/:id")
+	is.Equal(views[0].Client, "bud/view/posts/_show.vue.js")
+}
+
+func TestListIslands(t *testing.T) {
+	is := is.New(t)
+	fsys := vfs.Map{
+		"view/index.svelte":                []byte(""),
+		"view/Counter.island.svelte":       []byte(""),
+		"view/_Hidden.island.svelte":       []byte(""),
+		"view/posts/show.svelte":           []byte(""),
+		"view/posts/Like.island.svelte":    []byte(""),
+		"view/_partials/Nav.island.svelte": []byte(""),
+	}
+	// Islands aren't pages
+	views, err := entrypoint.List(fsys, "view")
+	is.NoErr(err)
+	is.Equal(len(views), 2)
+	is.Equal(views[0].Page, entrypoint.Path("view/index.svelte"))
+	is.Equal(views[1].Page, entrypoint.Path("view/posts/show.svelte"))
+	islands, err := entrypoint.ListIslands(fsys, "view")
+	is.NoErr(err)
+	is.Equal(len(islands), 2)
+	is.Equal(islands[0].Component, entrypoint.Path("view/Counter.island.svelte"))
+	is.Equal(islands[0].Type, "svelte")
+	is.Equal(islands[0].Client, "bud/view/_Counter.island.svelte.js")
+	is.Equal(islands[1].Component, entrypoint.Path("view/posts/Like.island.svelte"))
+	is.Equal(islands[1].Client, "bud/view/posts/_Like.island.svelte.js")
+	island, err := entrypoint.FindIslandByClient(fsys, "bud/view/posts/_Like.island.svelte.js")
+	is.NoErr(err)
+	is.Equal(island.Component, entrypoint.Path("view/posts/Like.island.svelte"))
+	island, err = entrypoint.FindIsland(fsys, "view/Counter.island.svelte")
+	is.NoErr(err)
+	is.Equal(island.Client, "bud/view/_Counter.island.svelte.js")
+	_, err = entrypoint.FindIsland(fsys, "view/_Hidden.island.svelte")
+	is.True(err != nil)
+}
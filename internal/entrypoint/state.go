@@ -22,6 +22,17 @@ type View struct {
 	Hot    string
 }
 
+// Runtime is the livebud/runtime package that hydrates the view. JSX and TSX
+// views share the same runtime.
+func (v *View) Runtime() string {
+	switch v.Type {
+	case "jsx", "tsx":
+		return "jsx"
+	default:
+		return v.Type
+	}
+}
+
 func (v *View) ServerImports() (imports []Path) {
 	imports = append(imports, v.Page)
 	imports = append(imports, v.Frames...)
@@ -0,0 +1,55 @@
+// Package signature recognizes the special controller methods by their
+// signatures. It's shared by the generators that read controllers.
+package signature
+
+import "github.com/livebud/bud/package/parser"
+
+// IsMiddleware returns true for methods with the following signature:
+// Middleware(next http.Handler) http.Handler
+func IsMiddleware(method *parser.Function) (bool, error) {
+	if method.Name() != "Middleware" {
+		return false, nil
+	}
+	params, results := method.Params(), method.Results()
+	if len(params) != 1 || len(results) != 1 {
+		return false, nil
+	}
+	isParam, err := parser.IsImportType(params[0].Type(), "net/http", "Handler")
+	if err != nil {
+		return false, err
+	}
+	isResult, err := parser.IsImportType(results[0].Type(), "net/http", "Handler")
+	if err != nil {
+		return false, err
+	}
+	return isParam && isResult, nil
+}
+
+// IsLoader returns true for methods with the following signatures:
+// Load(...) T
+// Load(...) (T, error)
+func IsLoader(method *parser.Function) bool {
+	if method.Name() != "Load" {
+		return false
+	}
+	results := method.Results()
+	switch len(results) {
+	case 1:
+		return results[0].Type().String() != "error"
+	case 2:
+		return results[0].Type().String() != "error" && results[1].Type().String() == "error"
+	default:
+		return false
+	}
+}
+
+// IsHook returns true for the Before, After and Around hooks, which run with
+// the controller's actions
+func IsHook(method *parser.Function) bool {
+	switch method.Name() {
+	case "Before", "After", "Around":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,62 @@
+// Package slot constrains the slots of generated routes by the data types of
+// the params they're unmarshaled into.
+package slot
+
+import (
+	"strings"
+
+	"github.com/livebud/bud/package/router/lex"
+)
+
+// Constrain the route's slots by their data types, so the router skips
+// requests that wouldn't unmarshal (e.g. /users/:id|int). Data types are keyed
+// by slot name.
+func Constrain(route string, dataTypes map[string]string) string {
+	segments := strings.Split(route, "/")
+	for i, segment := range segments {
+		if !strings.HasPrefix(segment, ":") {
+			continue
+		}
+		if constraint := Constraint(dataTypes[segment[1:]]); constraint != "" {
+			segments[i] = segment + "|" + constraint
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// Constraint returns the router constraint for a slot's data type. Sized
+// integers are constrained by their size, so values that would overflow don't
+// match.
+func Constraint(dataType string) string {
+	switch dataType {
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64":
+		return dataType
+	default:
+		return ""
+	}
+}
+
+// Strip the constraints from the route's slots
+// e.g. /users/:id|int/edit => /users/:id/edit
+func Strip(route string) string {
+	lexer := lex.New(route)
+	out := new(strings.Builder)
+	for {
+		token := lexer.Next()
+		switch token.Type {
+		case lex.ErrorToken:
+			return route
+		case lex.EndToken:
+			return out.String()
+		case lex.SlotToken:
+			key, _ := token.Slot()
+			out.WriteString(":" + key)
+		case lex.QuestionToken:
+			key, _ := token.Slot()
+			out.WriteString(":" + key + "?")
+		default:
+			out.WriteString(token.Value)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package slot_test
+
+import (
+	"testing"
+
+	"github.com/livebud/bud/internal/is"
+	"github.com/livebud/bud/internal/slot"
+)
+
+func TestConstrain(t *testing.T) {
+	is := is.New(t)
+	dataTypes := map[string]string{
+		"post_id": "int8",
+		"id":      "uint",
+		"slug":    "string",
+	}
+	is.Equal(slot.Constrain("/posts/:post_id/comments/:id", dataTypes), "/posts/:post_id|int8/comments/:id|uint")
+	is.Equal(slot.Constrain("/posts/:slug/edit", dataTypes), "/posts/:slug/edit")
+	is.Equal(slot.Constrain("/users/:user_id", dataTypes), "/users/:user_id")
+}
+
+func TestStrip(t *testing.T) {
+	is := is.New(t)
+	is.Equal(slot.Strip("/users/:id|int/edit"), "/users/:id/edit")
+	is.Equal(slot.Strip("/posts/:post_id|int8/comments/:id|uint"), "/posts/:post_id/comments/:id")
+	is.Equal(slot.Strip("/:id|int.:format?"), "/:id.:format?")
+	is.Equal(slot.Strip("/:id|uuid?"), "/:id?")
+	is.Equal(slot.Strip("/tags/:tag|[a-z-]+"), "/tags/:tag")
+	is.Equal(slot.Strip("/docs/:path*"), "/docs/:path*")
+	is.Equal(slot.Strip("/users"), "/users")
+}
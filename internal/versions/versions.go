@@ -7,5 +7,7 @@ var Bud = "latest"
 const Svelte = "3.47.0"
 
 // React version used and tested across bud.
-// Currently not fully baked in.
 const React = "18.0.0"
+
+// Vue version used and tested across bud.
+const Vue = "3.2.37"
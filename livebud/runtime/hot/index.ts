@@ -6,11 +6,19 @@ import { parse } from "../../url"
 
 export default class Hot {
   private subs: Array<() => void> = []
-  private sse: EventSource
+  private sse?: EventSource
   private queue = new Queue()
 
-  constructor(path: string, private readonly components: Record<string, any>) {
-    this.sse = new EventSource(path)
+  constructor(private readonly path: string, private readonly components: Record<string, any>) {
+    this.open()
+  }
+
+  // Open the connection to the hot reload server. The router closes the
+  // connections of the pages it navigates away from and opens them again when
+  // it navigates back.
+  open() {
+    if (this.sse) return
+    this.sse = new EventSource(this.path)
     this.sse.addEventListener("message", this.onmessage)
   }
 
@@ -42,8 +50,10 @@ export default class Hot {
   }
 
   close() {
+    if (!this.sse) return
     this.sse.removeEventListener("message", this.onmessage)
     this.sse.close()
+    this.sse = undefined
   }
 }
 
@@ -1,4 +1,5 @@
 import Hot from "./hot"
+import Router from "./router"
 
 export type HydrateInput<Props = Record<string, any>> = {
   page: any
@@ -8,7 +9,14 @@ export type HydrateInput<Props = Record<string, any>> = {
   target: HTMLElement | null
 }
 
-type Hydrate<Props = Record<string, any>> = (input: HydrateInput<Props>) => void
+// View that's been rendered. Views that can update swap in the next page,
+// keeping the frames that both pages share mounted. Otherwise the view is
+// created again.
+export type View = {
+  update?: (input: HydrateInput) => void
+} | void
+
+type Hydrate<Props = Record<string, any>> = (input: HydrateInput<Props>) => View
 
 /**
  * Mount function
@@ -22,28 +30,158 @@ type MountInput = {
   error?: string
   createView: Hydrate
   hot?: Hot
+  // Path to the client entry of this page
+  client: string
+  // Client entries of the pages the router can navigate to, keyed by route
+  routes: Record<string, string>
 }
 
+// Page that's mounted
+type Page = {
+  input: MountInput
+  frames: any[]
+  props: Record<string, any>
+}
+
+// Current page, its view and the function that created the view
+let current: Page | undefined
+let view: View
+let createdBy: Hydrate | undefined
+
+// Mounted inputs, keyed by their client entry
+const mounted: Record<string, MountInput> = {}
+
+// Router that navigates between pages
+let router: Router | undefined
+
 export function mount(input: MountInput): void {
-  const props = getProps(document.getElementById("bud_props"))
-  input.createView({
-    page: input.components[input.page],
-    frames: input.frames.map((frame) => input.components[frame]),
-    error: input.error ? input.components[input.error] : undefined,
-    target: input.target,
-    props: props,
-  })
+  mounted[input.client] = input
   if (input.hot) {
     input.hot.listen(() => {
-      input.createView({
-        page: input.components[input.page],
-        frames: input.frames.map((frame) => input.components[frame]),
-        error: input.error ? input.components[input.error] : undefined,
-        target: input.target,
-        props: props,
-      })
+      if (!current || current.input !== input) return
+      current.frames = frames(input)
+      render(current)
     })
   }
+  // Pages loaded by the router are rendered by the router
+  if (current) {
+    return
+  }
+  const page: Page = {
+    input,
+    frames: frames(input),
+    props: getProps(document.getElementById("bud_props")),
+  }
+  // Apply the deferred props that were streamed in before the page loaded
+  const deferred: Deferred[] = (self as any).bud_deferred || []
+  for (let prop of deferred) {
+    setPath(page.props, prop.path, prop.value)
+  }
+  current = page
+  render(page)
+  // Re-render as the remaining deferred props stream in
+  ;(self as any).bud_deferred = {
+    push(prop: Deferred) {
+      setPath(page.props, prop.path, prop.value)
+      if (current === page) render(page)
+    },
+  }
+  router = new Router(visit, page.props.bud_head)
+  router.listen()
+}
+
+// navigate to the URL without a full page reload. Falls back to a full page
+// load before the page has mounted.
+export function navigate(href: string): Promise<void> {
+  if (!router) {
+    location.assign(href)
+    return Promise.resolve()
+  }
+  return router.navigate(href)
+}
+
+// submit the form without a full page reload. Falls back to submitting the
+// form as usual before the page has mounted.
+export function submit(form: HTMLFormElement, submitter?: HTMLElement | null): Promise<void> {
+  if (!router) {
+    form.submit()
+    return Promise.resolve()
+  }
+  return router.submit(form, submitter)
+}
+
+// render creates the page's view
+function render(page: Page) {
+  createdBy = page.input.createView
+  view = createdBy(hydrateInput(page))
+}
+
+// visit the page of the route with its props. Returns false when the route
+// doesn't have a page in the browser.
+async function visit(route: string, props: Record<string, any>): Promise<boolean> {
+  if (!current) return false
+  const client = current.input.routes[route]
+  if (!client) return false
+  // Loading the client entry mounts its input
+  if (!mounted[client]) await import(client)
+  const input = mounted[client]
+  if (!input) return false
+  // Only listen for hot reloads of the current page
+  if (current.input !== input) {
+    if (current.input.hot) current.input.hot.close()
+    if (input.hot) input.hot.open()
+  }
+  // Each client entry bundles its own frames, so the frames that both pages
+  // share keep the current page's components to stay mounted
+  const shared = current
+  const page: Page = {
+    input,
+    frames: input.frames.map((frame, i) =>
+      shared.input.frames[i] === frame ? shared.frames[i] : input.components[frame]
+    ),
+    props,
+  }
+  current = page
+  if (view && view.update && input.createView === createdBy) {
+    view.update(hydrateInput(page))
+    return true
+  }
+  render(page)
+  return true
+}
+
+function hydrateInput(page: Page): HydrateInput {
+  const input = page.input
+  return {
+    page: input.components[input.page],
+    frames: page.frames,
+    error: input.error ? input.components[input.error] : undefined,
+    target: input.target,
+    props: page.props,
+  }
+}
+
+// frames returns the input's frame components
+function frames(input: MountInput): any[] {
+  return input.frames.map((frame) => input.components[frame])
+}
+
+// Deferred prop streamed in from the server once it resolves
+type Deferred = {
+  path: (string | number)[]
+  value: any
+}
+
+// setPath sets the value at the path within the props
+function setPath(props: Record<string, any>, path: (string | number)[], value: any) {
+  let object = props
+  for (let i = 0; i < path.length - 1; i++) {
+    if (object[path[i]] == null) {
+      object[path[i]] = typeof path[i + 1] === "number" ? [] : {}
+    }
+    object = object[path[i]]
+  }
+  object[path[path.length - 1]] = value
 }
 
 function getProps(node: HTMLElement | null) {
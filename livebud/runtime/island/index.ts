@@ -0,0 +1,49 @@
+/**
+ * Islands are the interactive components within otherwise static pages. The
+ * server renders each island within a <bud-island> element along with its
+ * props. The island's client entry then hydrates each of these elements.
+ */
+
+export type IslandInput<Props = Record<string, any>> = {
+  component: any
+  props: Props
+  target: HTMLElement
+}
+
+type CreateIsland = (input: IslandInput) => void
+
+type HydrateInput = {
+  // Path to the client entry of this island
+  client: string
+  component: any
+  createIsland: CreateIsland
+}
+
+// Elements that have already been hydrated
+const hydrated = new WeakSet<Element>()
+
+export function hydrate(input: HydrateInput): void {
+  const elements = document.querySelectorAll("bud-island")
+  for (let element of Array.from(elements)) {
+    if (element.getAttribute("client") !== input.client) continue
+    if (hydrated.has(element)) continue
+    hydrated.add(element)
+    input.createIsland({
+      component: input.component,
+      props: getProps(element),
+      target: element as HTMLElement,
+    })
+  }
+}
+
+function getProps(element: Element): Record<string, any> {
+  const props = element.getAttribute("props")
+  if (!props) {
+    return {}
+  }
+  try {
+    return JSON.parse(props)
+  } catch (err) {
+    return {}
+  }
+}
@@ -2,10 +2,55 @@ import { HydrateInput } from ".."
 import ReactDOM from "react-dom"
 import React from "react"
 
+// Targets that have been hydrated. Rendering into them again, after a live
+// reload for example, updates what's already there.
+const hydrated = new WeakSet<HTMLElement>()
+
 export default function createView(input: HydrateInput) {
-  let component = React.createElement(input.page, input.props)
-  for (let frame of input.frames) {
-    component = React.createElement(frame, input.props, component)
+  render(input)
+  return {
+    // Update the view with the next page. React keeps the frames that both
+    // pages share mounted.
+    update(next: HydrateInput) {
+      render(next)
+    },
+  }
+}
+
+function render(input: HydrateInput) {
+  const target = input.target
+  if (target == null) return
+  const element = createElement(input)
+  if (hydrated.has(target)) {
+    ReactDOM.render(element, target)
+    return
+  }
+  hydrated.add(target)
+  ReactDOM.hydrate(element, target)
+}
+
+// Errors render the closest error page in place of the page and its frames.
+// Otherwise the page is nested within its frames, from the innermost frame
+// out.
+function createElement(input: HydrateInput) {
+  const error = input.props.bud_error
+  if (error) {
+    return React.createElement(input.error || DefaultError, { ...input.props, error })
+  }
+  let element = React.createElement(input.page, input.props)
+  for (let i = input.frames.length - 1; i >= 0; i--) {
+    element = React.createElement(input.frames[i], input.props, element)
   }
-  ReactDOM.hydrate(component, input.target)
+  return element
+}
+
+// Default error page when there's no error.jsx. This needs to match the
+// default error page rendered by the server.
+function DefaultError(props: { error: { status: number; message: string } }) {
+  return React.createElement(
+    React.Fragment,
+    null,
+    React.createElement("h1", null, props.error.status),
+    React.createElement("p", null, props.error.message)
+  )
 }
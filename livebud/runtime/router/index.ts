@@ -0,0 +1,307 @@
+/**
+ * Client-side router
+ *
+ * Links and forms opt into client-side navigation with the data-bud-navigate
+ * attribute, either on themselves or on one of their parents (e.g. <body>).
+ * Setting data-bud-navigate="false" opts them back out.
+ *
+ * Navigating fetches the route and props of the next page from the server,
+ * then visits the page in the browser. Pages that can't be visited fall back
+ * to a full page load.
+ *
+ * Forms are submitted with fetch like the browser would submit them, so they
+ * work with the "_method" field of middleware.MethodOverride. The page that
+ * the server redirects to is then visited, including redirects back to the
+ * form with validation errors. Without JavaScript, forms are submitted as
+ * usual.
+ */
+
+// Visit renders the page of the route with its props. Returns false when the
+// page can't be rendered in the browser.
+type Visit = (route: string, props: Record<string, any>) => Promise<boolean>
+
+// Navigation is the server's response to the router
+type Navigation = {
+  route: string
+  props: Record<string, any>
+}
+
+// Head set by the controller
+type Head = {
+  title?: string
+  meta?: { name?: string; property?: string; content: string }[]
+  links?: { rel: string; href: string; type?: string; hreflang?: string }[]
+}
+
+// State of the history entries created by the router
+type State = {
+  bud: true
+  scroll: number
+}
+
+export default class Router {
+  // Path and query of the current page
+  private url = pathOf(location.href)
+  // Head of the current page
+  private head: Head | undefined
+  // Increments with each navigation, so slower navigations are dropped
+  private id = 0
+
+  constructor(private readonly visit: Visit, head?: Head) {
+    this.head = head
+  }
+
+  listen() {
+    history.replaceState(this.state(), "")
+    document.addEventListener("click", this.onclick)
+    document.addEventListener("submit", this.onsubmit)
+    window.addEventListener("popstate", this.onpopstate)
+  }
+
+  close() {
+    document.removeEventListener("click", this.onclick)
+    document.removeEventListener("submit", this.onsubmit)
+    window.removeEventListener("popstate", this.onpopstate)
+  }
+
+  // Navigate to the URL, adding it to the history unless it's a back or
+  // forward navigation
+  async navigate(href: string, push = true): Promise<void> {
+    const url = new URL(href, location.href)
+    await this.load(url, {}, push, () => location.assign(url.href))
+  }
+
+  // Submit the form, along with the button that submitted it
+  async submit(form: HTMLFormElement, submitter?: HTMLElement | null): Promise<void> {
+    const method = (
+      attribute(submitter, "formmethod") ||
+      form.getAttribute("method") ||
+      "get"
+    ).toLowerCase()
+    const url = new URL(
+      attribute(submitter, "formaction") || form.getAttribute("action") || location.href,
+      location.href
+    )
+    const data = new FormData(form)
+    const button = submitter as HTMLButtonElement | null | undefined
+    if (button && button.name) {
+      data.append(button.name, button.value)
+    }
+    if (method === "get") {
+      url.search = new URLSearchParams(data as any).toString()
+      return this.navigate(url.href)
+    }
+    const enctype = attribute(submitter, "formenctype") || form.getAttribute("enctype")
+    const init: RequestInit =
+      enctype === "multipart/form-data"
+        ? { method: "POST", body: data }
+        : {
+            method: "POST",
+            body: new URLSearchParams(data as any).toString(),
+            headers: { "Content-Type": "application/x-www-form-urlencoded" },
+          }
+    await this.load(url, init, true, () => submitNatively(form, button))
+  }
+
+  // load the page from the server and visit it. Responses that aren't pages
+  // fall back to the browser.
+  private async load(url: URL, init: RequestInit, push: boolean, fallback: () => void) {
+    const id = ++this.id
+    let navigation: Navigation
+    try {
+      const res = await fetch(url.href, {
+        ...init,
+        headers: {
+          ...init.headers,
+          Accept: "text/html",
+          "X-Bud-Navigate": "true",
+        },
+        credentials: "same-origin",
+      })
+      const contentType = res.headers.get("Content-Type") || ""
+      if (!contentType.startsWith("application/json")) {
+        // The response isn't a page the router can render
+        if (res.redirected) location.assign(res.url)
+        else fallback()
+        return
+      }
+      // Follow redirects, keeping the hash that's not sent to the server
+      if (res.redirected) {
+        const hash = url.hash
+        url = new URL(res.url)
+        url.hash = hash
+      }
+      navigation = await res.json()
+    } catch (err) {
+      fallback()
+      return
+    }
+    // Another navigation started while this one was loading
+    if (id !== this.id) return
+    // Submitting a form may redirect back to the same page
+    const changed = pathOf(url.href) !== this.url
+    if (push) {
+      history.replaceState(this.state(), "")
+      if (changed) history.pushState({ bud: true, scroll: 0 }, "", url.href)
+    }
+    this.url = pathOf(url.href)
+    let visited = false
+    try {
+      visited = await this.visit(navigation.route, navigation.props || {})
+    } catch (err) {
+      console.error(err)
+    }
+    if (!visited) {
+      location.replace(url.href)
+      return
+    }
+    updateHead(this.head, navigation.props.bud_head)
+    this.head = navigation.props.bud_head
+    if (!push) {
+      window.scrollTo(0, (history.state && history.state.scroll) || 0)
+    } else if (changed) {
+      scroll(url)
+    }
+  }
+
+  private state(): State {
+    return { ...history.state, bud: true, scroll: window.scrollY }
+  }
+
+  private onclick = (e: MouseEvent) => {
+    if (e.defaultPrevented || e.button !== 0) return
+    if (e.metaKey || e.ctrlKey || e.shiftKey || e.altKey) return
+    const target = e.target as Element | null
+    const link = target && target.closest ? target.closest("a[href]") : null
+    if (!(link instanceof HTMLAnchorElement) || !optedIn(link)) return
+    if (link.target && link.target !== "_self") return
+    if (link.hasAttribute("download") || link.getAttribute("rel") === "external") return
+    const url = new URL(link.href)
+    if (url.origin !== location.origin) return
+    // Let the browser jump to anchors within the page
+    if (url.hash && pathOf(url.href) === this.url) return
+    e.preventDefault()
+    this.navigate(url.href)
+  }
+
+  private onsubmit = (e: Event) => {
+    if (e.defaultPrevented) return
+    const form = e.target
+    if (!(form instanceof HTMLFormElement) || !optedIn(form)) return
+    const submitter: HTMLElement | null = (e as any).submitter || null
+    const method = attribute(submitter, "formmethod") || form.getAttribute("method")
+    if (method && method.toLowerCase() === "dialog") return
+    const target = attribute(submitter, "formtarget") || form.getAttribute("target")
+    if (target && target !== "_self") return
+    const action = attribute(submitter, "formaction") || form.getAttribute("action")
+    const url = new URL(action || location.href, location.href)
+    if (url.origin !== location.origin) return
+    e.preventDefault()
+    this.submit(form, submitter)
+  }
+
+  private onpopstate = (e: PopStateEvent) => {
+    if (!e.state || !e.state.bud) return
+    // Anchors within the page are handled by the browser
+    if (pathOf(location.href) === this.url) return
+    this.navigate(location.href, false)
+  }
+}
+
+// optedIn is true when the link or form or one of its parents has opted into
+// client-side navigation
+function optedIn(element: Element): boolean {
+  const optIn = element.closest("[data-bud-navigate]")
+  return optIn != null && optIn.getAttribute("data-bud-navigate") !== "false"
+}
+
+// pathOf returns the path and query of the URL
+function pathOf(href: string): string {
+  const url = new URL(href, location.href)
+  return url.pathname + url.search
+}
+
+// scroll to the anchor of the URL or the top of the page
+function scroll(url: URL) {
+  if (url.hash) {
+    const element = document.getElementById(decodeURIComponent(url.hash.slice(1)))
+    if (element) {
+      element.scrollIntoView()
+      return
+    }
+  }
+  window.scrollTo(0, 0)
+}
+
+// attribute of an element that may not exist
+function attribute(element: Element | null | undefined, name: string): string | null {
+  return element ? element.getAttribute(name) : null
+}
+
+// submitNatively submits the form with a full page load, including the value
+// of the button that submitted it
+function submitNatively(form: HTMLFormElement, button?: HTMLButtonElement | null) {
+  if (button && button.name) {
+    const input = document.createElement("input")
+    input.type = "hidden"
+    input.name = button.name
+    input.value = button.value
+    form.appendChild(input)
+  }
+  // Call the prototype's submit in case an input is named "submit"
+  HTMLFormElement.prototype.submit.call(form)
+}
+
+// updateHead replaces the tags set by the previous page's controller with the
+// tags set by the next page's controller
+function updateHead(prev: Head | undefined, next: Head | undefined) {
+  if (prev) {
+    for (let meta of prev.meta || []) {
+      const element = findMeta(meta)
+      if (element) element.remove()
+    }
+    for (let link of prev.links || []) {
+      const element = findLink(link)
+      if (element) element.remove()
+    }
+  }
+  if (!next) return
+  if (next.title) {
+    document.title = next.title
+  }
+  for (let meta of next.meta || []) {
+    const element = findMeta(meta) || document.createElement("meta")
+    if (meta.property) element.setAttribute("property", meta.property)
+    else element.setAttribute("name", meta.name || "")
+    element.setAttribute("content", meta.content)
+    document.head.appendChild(element)
+  }
+  for (let link of next.links || []) {
+    const element = document.createElement("link")
+    element.setAttribute("rel", link.rel)
+    element.setAttribute("href", link.href)
+    if (link.type) element.setAttribute("type", link.type)
+    if (link.hreflang) element.setAttribute("hreflang", link.hreflang)
+    document.head.appendChild(element)
+  }
+}
+
+function findMeta(meta: { name?: string; property?: string }): Element | null {
+  for (let element of Array.from(document.head.querySelectorAll("meta"))) {
+    if (meta.property) {
+      if (element.getAttribute("property") === meta.property) return element
+    } else if (element.getAttribute("name") === meta.name) {
+      return element
+    }
+  }
+  return null
+}
+
+function findLink(link: { rel: string; href: string }): Element | null {
+  for (let element of Array.from(document.head.querySelectorAll("link"))) {
+    if (element.getAttribute("rel") === link.rel && element.getAttribute("href") === link.href) {
+      return element
+    }
+  }
+  return null
+}
@@ -1,8 +1,6 @@
 import { HydrateInput } from ".."
+import { IslandInput } from "../island"
 
-// TODO:
-// - Support frames
-// - Handle errors
 export default function createView(input: HydrateInput) {
   if (input.target != null) {
     // TODO: for some reason Svelte isn't able to re-hydrate over itself during
@@ -12,9 +10,163 @@ export default function createView(input: HydrateInput) {
     // For now, we'll clear the DOM in our target before hydrating.
     input.target.innerHTML = ""
   }
-  new input.page({
+  let mounted = mount(input, true)
+  return {
+    // Update the view with the next page, keeping the frames that both pages
+    // share mounted
+    update(next: HydrateInput) {
+      mounted = update(mounted, next)
+    },
+  }
+}
+
+// Hydrate an island over the HTML rendered by the server
+export function createIsland(input: IslandInput) {
+  new input.component({
     target: input.target,
     props: input.props,
     hydrate: true,
   })
 }
+
+// Mounted view. Components are the frames from the outermost in, followed by
+// the page. Each frame has a slot that the next component is mounted in.
+type Mounted = {
+  components: any[]
+  root?: any
+  slots: Slot[]
+  error: boolean
+}
+
+function mount(input: HydrateInput, hydrate: boolean): Mounted {
+  // Errors render the closest error page in place of the page and its frames
+  const error = input.props.bud_error
+  if (error) {
+    // The default error page is static
+    if (!input.error) return { components: [], slots: [], error: true }
+    const root = new input.error({
+      target: input.target,
+      props: { ...input.props, error },
+      hydrate: hydrate,
+    })
+    return { components: [input.error], root, slots: [], error: true }
+  }
+  const components = [...input.frames, input.page]
+  const nested = nest(components, input.props)
+  const root = new nested.Component({
+    target: input.target,
+    props: nested.props,
+    hydrate: hydrate,
+  })
+  return { components, root, slots: nested.slots, error: false }
+}
+
+// update the mounted view with the next page. Components that both pages share
+// are updated with the next page's props. The rest are swapped out within the
+// slot of the last frame they share.
+function update(mounted: Mounted, input: HydrateInput): Mounted {
+  const components = [...input.frames, input.page]
+  let shared = 0
+  if (!mounted.error && !input.props.bud_error) {
+    while (
+      shared < components.length &&
+      shared < mounted.components.length &&
+      components[shared] === mounted.components[shared]
+    ) {
+      shared++
+    }
+  }
+  // Nothing changed but the props
+  if (shared === components.length && shared === mounted.components.length) {
+    mounted.root.$set(input.props)
+    for (let slot of mounted.slots) {
+      slot.set(input.props)
+    }
+    return mounted
+  }
+  // Pages that don't share a frame are mounted from scratch
+  if (shared === 0 || shared >= components.length || shared >= mounted.components.length) {
+    if (mounted.root) mounted.root.$destroy()
+    if (input.target != null) input.target.innerHTML = ""
+    return mount(input, false)
+  }
+  // Update the shared frames, then swap out the rest within the last one
+  mounted.root.$set(input.props)
+  for (let i = 0; i < shared - 1; i++) {
+    mounted.slots[i].set(input.props)
+  }
+  const nested = nest(components.slice(shared), input.props)
+  mounted.slots[shared - 1].swap(nested.Component, nested.props)
+  return {
+    components,
+    root: mounted.root,
+    slots: [...mounted.slots.slice(0, shared), ...nested.slots],
+    error: false,
+  }
+}
+
+// nest the components within each other, from the innermost component out
+function nest(components: any[], props: Record<string, any>) {
+  let Component = components[components.length - 1]
+  let nestedProps = props
+  const slots: Slot[] = []
+  for (let i = components.length - 2; i >= 0; i--) {
+    const slot = new Slot(Component, nestedProps)
+    slots.unshift(slot)
+    nestedProps = {
+      ...props,
+      $$slots: { default: [slot.block()] },
+      $$scope: {},
+    }
+    Component = components[i]
+  }
+  return { Component, props: nestedProps, slots }
+}
+
+// Slot is the default slot of a frame, which mounts the nested component.
+// Slots remember where they're mounted, so the nested component can be
+// swapped out while the frame stays mounted.
+class Slot {
+  private component: any
+  private target: Node | undefined
+  private anchor: Node | null = null
+
+  constructor(private Component: any, private props: Record<string, any>) {}
+
+  block() {
+    const slot = this
+    return function () {
+      return {
+        c() {},
+        l() {},
+        m(target: Node, anchor: Node | null) {
+          slot.target = target
+          slot.anchor = anchor
+          slot.component = new slot.Component({ target, anchor, props: slot.props })
+        },
+        d() {
+          if (slot.component) slot.component.$destroy()
+          slot.component = undefined
+        },
+      }
+    }
+  }
+
+  // set the props of the nested component
+  set(props: Record<string, any>) {
+    if (this.component) this.component.$set(props)
+  }
+
+  // swap the nested component for another
+  swap(Component: any, props: Record<string, any>) {
+    if (this.component) this.component.$destroy()
+    this.Component = Component
+    this.props = props
+    if (!this.target) return
+    this.component = new Component({
+      target: this.target,
+      anchor: this.anchor,
+      props: props,
+    })
+  }
+}
@@ -0,0 +1,66 @@
+import { HydrateInput } from ".."
+import { App, createApp, createSSRApp, h, shallowReactive } from "vue"
+
+// Apps mounted on a target. Rendering into a target again, after a live reload
+// for example, mounts the view over what's already there.
+const apps = new WeakMap<HTMLElement, App>()
+
+export default function createView(input: HydrateInput) {
+  const target = input.target
+  if (target == null) return
+  // The app renders whatever input is current, so updating the input swaps in
+  // the next page. Vue keeps the frames that both pages share mounted.
+  const state = shallowReactive({ input })
+  const previous = apps.get(target)
+  if (previous) previous.unmount()
+  const root = { render: () => render(state.input) }
+  const app = previous ? createApp(root) : createSSRApp(root)
+  app.mount(target)
+  apps.set(target, app)
+  return {
+    update(next: HydrateInput) {
+      state.input = next
+    },
+  }
+}
+
+// Errors render the closest error page in place of the page and its frames.
+// Otherwise the page is rendered within the default slot of its frames, from
+// the innermost frame out.
+function render(input: HydrateInput) {
+  const error = input.props.bud_error
+  if (error) {
+    const component = input.error || DefaultError
+    return h(component, propsOf(component, { ...input.props, error }))
+  }
+  let render = () => h(input.page, propsOf(input.page, input.props))
+  for (let i = input.frames.length - 1; i >= 0; i--) {
+    const inner = render
+    const frame = input.frames[i]
+    render = () => h(frame, propsOf(frame, input.props), { default: inner })
+  }
+  return render()
+}
+
+// propsOf picks the props that the component declares. This needs to match
+// the props picked by the server, so hydration lines up.
+function propsOf(component: any, props: Record<string, any>): Record<string, any> {
+  const declared = component.props
+  if (!declared) return {}
+  const keys = Array.isArray(declared) ? declared : Object.keys(declared)
+  const picked: Record<string, any> = {}
+  for (let key of keys) {
+    if (key in props) picked[key] = props[key]
+  }
+  return picked
+}
+
+// Default error page when there's no error.vue. This needs to match the
+// default error page rendered by the server.
+const DefaultError = {
+  props: ["error"],
+  render() {
+    const error = (this as any).error
+    return [h("h1", String(error.status)), h("p", error.message)]
+  },
+}
@@ -1,8 +1,10 @@
 package budclient
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -15,6 +17,7 @@ import (
 
 type Client interface {
 	Render(route string, props interface{}) (*ssr.Response, error)
+	Stream(route string, props interface{}, write func(chunk *ssr.Chunk) error) error
 	Proxy(w http.ResponseWriter, r *http.Request)
 	Publish(topic string, data []byte) error
 }
@@ -82,6 +85,47 @@ func (c *client) Render(route string, props interface{}) (*ssr.Response, error)
 	return out, nil
 }
 
+// Stream a path with props from the dev server, calling write for each chunk
+func (c *client) Stream(route string, props interface{}, write func(chunk *ssr.Chunk) error) error {
+	body, err := json.Marshal(props)
+	if err != nil {
+		return err
+	}
+	url := strings.TrimSuffix(c.baseURL+"/bud/stream"+route, "/")
+	res, err := c.httpClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		resBody, err := io.ReadAll(res.Body)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("budclient: stream returned unexpected %d. %s", res.StatusCode, resBody)
+	}
+	// Chunks are newline-delimited JSON
+	reader := bufio.NewReader(res.Body)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			chunk := new(ssr.Chunk)
+			if err := json.Unmarshal(line, chunk); err != nil {
+				return err
+			}
+			if err := write(chunk); err != nil {
+				return err
+			}
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
 func (c *client) Proxy(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
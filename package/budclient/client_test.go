@@ -110,6 +110,44 @@ func TestRenderNested(t *testing.T) {
 	is.In(res.Body, `<h1>Hello, marshmallow!</h1>`)
 }
 
+func TestStream(t *testing.T) {
+	ctx := context.Background()
+	is := is.New(t)
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["view/index.svelte"] = `
+		<script>
+			export let _string = "cupcake"
+		</script>
+		<h1>Hello, {_string}!</h1>
+	`
+	td.NodeModules["svelte"] = versions.Svelte
+	is.NoErr(td.Write(ctx))
+	ps := pubsub.New()
+	server, err := loadServer(ps, dir)
+	is.NoErr(err)
+	defer server.Close()
+	client, err := budclient.Load(server.URL)
+	is.NoErr(err)
+	var chunks []*ssr.Chunk
+	err = client.Stream("/", map[string]interface{}{
+		"_string": "marshmallow",
+	}, func(chunk *ssr.Chunk) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	is.NoErr(err)
+	is.Equal(len(chunks), 3)
+	is.Equal(chunks[0].Status, 200)
+	is.Equal(chunks[0].Headers["Content-Type"], "text/html")
+	is.In(chunks[0].Body, `<script id="bud_props" type="text/template" defer>{"_string":"marshmallow"}</script>`)
+	is.NotIn(chunks[0].Body, `<h1>`)
+	is.In(chunks[1].Body, `<div id="bud_target">`)
+	is.In(chunks[1].Body, `<h1>Hello, marshmallow!</h1>`)
+	is.True(chunks[2].Tail)
+	is.In(chunks[2].Body, `</body>`)
+}
+
 func TestProxyFile(t *testing.T) {
 	ctx := context.Background()
 	is := is.New(t)
@@ -17,6 +17,10 @@ func (discard) Render(route string, props interface{}) (*ssr.Response, error) {
 	return nil, fmt.Errorf("budclient: discard client does not support render")
 }
 
+func (discard) Stream(route string, props interface{}, write func(chunk *ssr.Chunk) error) error {
+	return fmt.Errorf("budclient: discard client does not support stream")
+}
+
 func (discard) Proxy(w http.ResponseWriter, r *http.Request) {
 	http.Error(w, "budclient: discard client does not support proxy", http.StatusInternalServerError)
 }
@@ -31,6 +31,7 @@ func New(fsys fs.FS, bus pubsub.Client, log log.Interface, vm js.VM) *Server {
 	}
 	// Routes that are proxied to from the browser through the app to bud
 	router.Post("/bud/view/:route*", http.HandlerFunc(server.render))
+	router.Post("/bud/stream/:route*", http.HandlerFunc(server.stream))
 	router.Get("/bud/view/:path*", http.HandlerFunc(server.serve))
 	router.Get("/bud/node_modules/:path*", http.HandlerFunc(server.serve))
 	// Routes that are directly requested by the browser to
@@ -79,6 +80,53 @@ func (s *Server) render(w http.ResponseWriter, r *http.Request) {
 	w.Write([]byte(result))
 }
 
+// stream renders the view in chunks, flushing each chunk as newline-delimited
+// JSON as soon as it's written
+func (s *Server) stream(w http.ResponseWriter, r *http.Request) {
+	// Read the body
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	// Load the props
+	var props map[string]interface{}
+	if err := json.Unmarshal(body, &props); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	script, err := fs.ReadFile(s.fsys, "bud/view/_ssr.js")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	route := "/" + r.URL.Query().Get("route")
+	expr := fmt.Sprintf(`%s; bud.stream(%q, %s)`, script, route, body)
+	flusher, _ := w.(http.Flusher)
+	wrote := false
+	err = s.vm.Stream("_ssr.js", expr, func(chunk string) error {
+		if !wrote {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			wrote = true
+		}
+		if _, err := io.WriteString(w, chunk+"\n"); err != nil {
+			return err
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		s.log.Error("budserver: unable to stream view", "route", route, "err", err)
+		// The status has already been sent, so the client sees a truncated stream
+		if !wrote {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+}
+
 func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
 	s.log.Debug("devserver: serving", "file", r.URL.Path)
 	file, err := s.hfs.Open(r.URL.Path)
@@ -97,7 +145,10 @@ func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
 	}
 	// Maintain support to resolve and run "/bud/node_modules/livebud/runtime".
 	if strings.HasPrefix(r.URL.Path, "/bud/node_modules/") ||
-		strings.HasSuffix(r.URL.Path, ".svelte") {
+		strings.HasSuffix(r.URL.Path, ".svelte") ||
+		strings.HasSuffix(r.URL.Path, ".jsx") ||
+		strings.HasSuffix(r.URL.Path, ".tsx") ||
+		strings.HasSuffix(r.URL.Path, ".vue") {
 		w.Header().Set("Content-Type", "application/javascript")
 	}
 	http.ServeContent(w, r, r.URL.Path, stat.ModTime(), file)
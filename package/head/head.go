@@ -0,0 +1,112 @@
+package head
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/livebud/bud/package/middleware"
+)
+
+// Head of the page. Controllers set the head and it's rendered into the
+// layout's head, taking precedence over matching tags in <svelte:head>.
+type Head struct {
+	Title string  `json:"title,omitempty"`
+	Meta  []*Meta `json:"meta,omitempty"`
+	Links []*Link `json:"links,omitempty"`
+}
+
+// Meta tag. Set either the name or the property (e.g. og:title).
+type Meta struct {
+	Name     string `json:"name,omitempty"`
+	Property string `json:"property,omitempty"`
+	Content  string `json:"content"`
+}
+
+// Link tag
+type Link struct {
+	Rel      string `json:"rel"`
+	Href     string `json:"href"`
+	Type     string `json:"type,omitempty"`
+	Hreflang string `json:"hreflang,omitempty"`
+}
+
+type contextKey struct{}
+
+// Middleware shares a single head across the request
+func Middleware() middleware.Middleware {
+	return middleware.Function(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), contextKey{}, &Head{})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+}
+
+// From loads the head of the request. Requests that didn't go through the
+// middleware get a head that isn't rendered.
+func From(ctx context.Context) *Head {
+	if head, ok := ctx.Value(contextKey{}).(*Head); ok {
+		return head
+	}
+	return &Head{}
+}
+
+// SetTitle sets the page title
+func (h *Head) SetTitle(title string) {
+	h.Title = title
+}
+
+// SetMeta sets a meta tag by name (e.g. description), replacing any existing
+// tag with the same name
+func (h *Head) SetMeta(name, content string) {
+	for _, meta := range h.Meta {
+		if meta.Name == name {
+			meta.Content = content
+			return
+		}
+	}
+	h.Meta = append(h.Meta, &Meta{Name: name, Content: content})
+}
+
+// SetProperty sets a meta tag by property (e.g. og:image), replacing any
+// existing tag with the same property
+func (h *Head) SetProperty(property, content string) {
+	for _, meta := range h.Meta {
+		if meta.Property == property {
+			meta.Content = content
+			return
+		}
+	}
+	h.Meta = append(h.Meta, &Meta{Property: property, Content: content})
+}
+
+// SetCanonical sets the canonical URL of the page
+func (h *Head) SetCanonical(href string) {
+	for _, link := range h.Links {
+		if link.Rel == "canonical" {
+			link.Href = href
+			return
+		}
+	}
+	h.Links = append(h.Links, &Link{Rel: "canonical", Href: href})
+}
+
+// AddLink adds a link tag
+func (h *Head) AddLink(link *Link) {
+	h.Links = append(h.Links, link)
+}
+
+// isEmpty is true when nothing has been set
+func (h *Head) isEmpty() bool {
+	return h.Title == "" && len(h.Meta) == 0 && len(h.Links) == 0
+}
+
+// Props adds the head to the view props under the "bud_head" key
+func Props(r *http.Request, props map[string]interface{}) map[string]interface{} {
+	head, ok := r.Context().Value(contextKey{}).(*Head)
+	if !ok || head.isEmpty() {
+		return props
+	}
+	props["bud_head"] = head
+	return props
+}
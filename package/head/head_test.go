@@ -0,0 +1,50 @@
+package head_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/livebud/bud/internal/is"
+	"github.com/livebud/bud/package/head"
+)
+
+func TestMiddlewareProps(t *testing.T) {
+	is := is.New(t)
+	var props map[string]interface{}
+	handler := head.Middleware().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := head.From(r.Context())
+		h.SetTitle("Hello")
+		h.SetMeta("description", "first")
+		h.SetMeta("description", "second")
+		h.SetProperty("og:title", "Hello")
+		h.SetCanonical("/first")
+		h.SetCanonical("/posts/1")
+		h.AddLink(&head.Link{Rel: "alternate", Href: "/fr/posts/1", Hreflang: "fr"})
+		props = head.Props(r, map[string]interface{}{})
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	data, err := json.Marshal(props)
+	is.NoErr(err)
+	is.Equal(string(data), `{"bud_head":{"title":"Hello","meta":[{"name":"description","content":"second"},{"property":"og:title","content":"Hello"}],"links":[{"rel":"canonical","href":"/posts/1"},{"rel":"alternate","href":"/fr/posts/1","hreflang":"fr"}]}}`)
+}
+
+func TestEmptyProps(t *testing.T) {
+	is := is.New(t)
+	var props map[string]interface{}
+	handler := head.Middleware().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		props = head.Props(r, map[string]interface{}{})
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	is.Equal(len(props), 0)
+}
+
+func TestWithoutMiddleware(t *testing.T) {
+	is := is.New(t)
+	head.From(context.Background()).SetTitle("Hello")
+	r := httptest.NewRequest("GET", "/", nil)
+	props := head.Props(r, map[string]interface{}{})
+	is.Equal(len(props), 0)
+}
@@ -107,9 +107,12 @@ func (e *Event) Format() *bytes.Buffer {
 		b.WriteString("event: " + e.Type + "\n")
 	}
 	if len(e.Data) > 0 {
-		b.WriteString("data: ")
-		b.Write(e.Data)
-		b.WriteByte('\n')
+		// Each line of data needs its own field
+		for _, line := range bytes.Split(e.Data, []byte{'\n'}) {
+			b.WriteString("data: ")
+			b.Write(line)
+			b.WriteByte('\n')
+		}
 	}
 	if e.Retry > 0 {
 		b.WriteString("retry: " + strconv.Itoa(e.Retry) + "\n")
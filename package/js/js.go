@@ -4,4 +4,7 @@ package js
 type VM interface {
 	Script(path, script string) error
 	Eval(path, expression string) (string, error)
+	// Stream evaluates an expression that writes chunks with the global
+	// bud_write(chunk) function. Chunks are passed to write as they're written.
+	Stream(path, expression string, write func(chunk string) error) error
 }
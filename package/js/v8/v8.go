@@ -1,7 +1,9 @@
 package v8
 
 import (
+	"errors"
 	"os"
+	"sync"
 
 	"github.com/livebud/bud/package/js"
 	"go.kuoruan.net/v8go-polyfills/console"
@@ -65,10 +67,7 @@ func Load() (*VM, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &VM{
-		isolate: isolate,
-		context: context,
-	}, nil
+	return newVM(isolate, context)
 }
 
 func Compile(path, code string) (*VM, error) {
@@ -76,29 +75,45 @@ func Compile(path, code string) (*VM, error) {
 	if err != nil {
 		return nil, err
 	}
-	script, err := isolate.CompileUnboundScript(code, path, v8go.CompileOptions{})
+	vm, err := newVM(isolate, context)
 	if err != nil {
 		return nil, err
 	}
-	// Bind to the context
-	if _, err := script.Run(context); err != nil {
+	if err := vm.Script(path, code); err != nil {
 		return nil, err
 	}
-	return &VM{
+	return vm, nil
+}
+
+func newVM(isolate *v8go.Isolate, context *v8go.Context) (*VM, error) {
+	vm := &VM{
 		isolate: isolate,
 		context: context,
-	}, nil
+	}
+	// Streaming support
+	write := v8go.NewFunctionTemplate(isolate, vm.writeChunk)
+	if err := context.Global().Set("bud_write", write.GetFunction(context)); err != nil {
+		vm.Close()
+		return nil, err
+	}
+	return vm, nil
 }
 
+// VM is safe for concurrent use. Scripts run one at a time, so each stream
+// writes to its own writer.
 type VM struct {
+	mu      sync.Mutex
 	isolate *v8go.Isolate
 	context *v8go.Context
+	write   func(chunk string) error // Guarded by mu
 }
 
 var _ js.VM = (*VM)(nil)
 
 // Compile a script into the context
 func (vm *VM) Script(path, code string) error {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
 	script, err := vm.isolate.CompileUnboundScript(code, path, v8go.CompileOptions{})
 	if err != nil {
 		return err
@@ -111,6 +126,12 @@ func (vm *VM) Script(path, code string) error {
 }
 
 func (vm *VM) Eval(path, expr string) (string, error) {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	return vm.eval(path, expr)
+}
+
+func (vm *VM) eval(path, expr string) (string, error) {
 	value, err := vm.context.RunScript(expr, path)
 	if err != nil {
 		return "", err
@@ -125,11 +146,40 @@ func (vm *VM) Eval(path, expr string) (string, error) {
 		for prom.State() == v8go.Pending {
 			continue
 		}
+		// Views that render asynchronously reject with their errors
+		if prom.State() == v8go.Rejected {
+			return "", errors.New(prom.Result().String())
+		}
 		return prom.Result().String(), nil
 	}
 	return value.String(), nil
 }
 
+// Stream evaluates an expression that writes chunks with the global
+// bud_write(chunk) function. Chunks are passed to write as they're written.
+func (vm *VM) Stream(path, expr string, write func(chunk string) error) error {
+	vm.mu.Lock()
+	defer vm.mu.Unlock()
+	vm.write = write
+	defer func() { vm.write = nil }()
+	_, err := vm.eval(path, expr)
+	return err
+}
+
+// writeChunk is called by bud_write(chunk). Write errors are thrown back into
+// the script to stop the stream.
+func (vm *VM) writeChunk(info *v8go.FunctionCallbackInfo) *v8go.Value {
+	args := info.Args()
+	if vm.write == nil || len(args) == 0 {
+		return nil
+	}
+	if err := vm.write(args[0].String()); err != nil {
+		message, _ := v8go.NewValue(vm.isolate, err.Error())
+		return vm.isolate.ThrowException(message)
+	}
+	return nil
+}
+
 func (vm *VM) Close() {
 	vm.context.Close()
 	vm.isolate.TerminateExecution()
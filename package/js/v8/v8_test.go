@@ -1,6 +1,7 @@
 package v8_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/livebud/bud/internal/is"
@@ -17,6 +18,34 @@ func TestCompile(t *testing.T) {
 	is.Equal("6", value)
 }
 
+func TestStream(t *testing.T) {
+	is := is.New(t)
+	vm, err := v8.Load()
+	is.NoErr(err)
+	defer vm.Close()
+	var chunks []string
+	err = vm.Stream("stream.js", `bud_write("a"); bud_write("b"); "done"`, func(chunk string) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	is.NoErr(err)
+	is.Equal(len(chunks), 2)
+	is.Equal(chunks[0], "a")
+	is.Equal(chunks[1], "b")
+}
+
+func TestStreamWriteError(t *testing.T) {
+	is := is.New(t)
+	vm, err := v8.Load()
+	is.NoErr(err)
+	defer vm.Close()
+	err = vm.Stream("stream.js", `bud_write("a"); bud_write("b")`, func(chunk string) error {
+		return errors.New("connection closed")
+	})
+	is.True(err != nil)
+	is.In(err.Error(), "connection closed")
+}
+
 func TestEval(t *testing.T) {
 	is := is.New(t)
 	result, err := v8.Eval("TestEval.js", "2*5")
@@ -1,7 +1,12 @@
 package middleware
 
 import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"strings"
 )
 
@@ -12,11 +17,20 @@ var eligible = map[string]struct{}{
 	http.MethodPatch:  {},
 }
 
-const formType = "application/x-www-form-urlencoded"
+const (
+	formType      = "application/x-www-form-urlencoded"
+	multipartType = "multipart/form-data"
+	// Number of bytes of the body that are read while looking for the _method
+	// field
+	maxScan = 64 << 10
+)
 
 // MethodOverride allows HTML <form method="post">'s to dispatch PATCH, PUT and
 // DELETE requests by overriding the request method using a hidden "_method"
-// field in the form body.
+// field in the form body. Both urlencoded and multipart forms are supported.
+//
+// Only the start of the body is read to find the _method field, so the handler
+// can parse the form within its own body limits.
 func MethodOverride() Middleware {
 	return Function(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -26,18 +40,25 @@ func MethodOverride() Middleware {
 				return
 			}
 			// Must have a request body and set the content-type to
-			// application/x-www-form-urlencoded.
-			if r.Body == nil || r.Header.Get("Content-Type") != formType {
+			// application/x-www-form-urlencoded or multipart/form-data. Forms
+			// submitted with fetch may include the charset.
+			if r.Body == nil {
 				next.ServeHTTP(w, r)
 				return
 			}
-			// Try parsing the request form
-			if err := r.ParseForm(); err != nil {
-				http.Error(w, err.Error(), http.StatusBadRequest)
+			mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+			// Try reading the _method field from the request form
+			var override string
+			switch mediaType {
+			case formType:
+				override = formMethod(r)
+			case multipartType:
+				override = multipartMethod(r, params["boundary"])
+			default:
+				next.ServeHTTP(w, r)
 				return
 			}
-			// Check if the _method form value is set
-			override := strings.ToUpper(r.Form.Get("_method"))
+			override = strings.ToUpper(override)
 			// Ensure the method is eligible for overriding
 			if _, ok := eligible[override]; !ok {
 				next.ServeHTTP(w, r)
@@ -49,3 +70,61 @@ func MethodOverride() Middleware {
 		})
 	})
 }
+
+// formMethod reads the _method field from the start of a urlencoded form
+func formMethod(r *http.Request) string {
+	prefix, eof := peek(r, maxScan)
+	// The last pair may be cut off unless the whole form was read
+	pairs := strings.Split(string(prefix), "&")
+	if !eof {
+		pairs = pairs[:len(pairs)-1]
+	}
+	for _, pair := range pairs {
+		values, err := url.ParseQuery(pair)
+		if err != nil {
+			continue
+		}
+		if value, ok := values["_method"]; ok {
+			return value[0]
+		}
+	}
+	return ""
+}
+
+// multipartMethod reads the _method field from the start of a multipart form.
+// Reading stops at the first file.
+func multipartMethod(r *http.Request, boundary string) string {
+	if boundary == "" {
+		return ""
+	}
+	prefix, _ := peek(r, maxScan)
+	reader := multipart.NewReader(bytes.NewReader(prefix), boundary)
+	for {
+		part, err := reader.NextPart()
+		if err != nil || part.FileName() != "" {
+			return ""
+		}
+		if part.FormName() != "_method" {
+			continue
+		}
+		value, err := io.ReadAll(part)
+		if err != nil {
+			return ""
+		}
+		return string(value)
+	}
+}
+
+// peek reads up to n bytes from the start of the request body and puts them
+// back, so the body can be read again from the start
+func peek(r *http.Request, n int64) (prefix []byte, eof bool) {
+	prefix, _ = io.ReadAll(io.LimitReader(r.Body, n))
+	r.Body = &peekedBody{io.MultiReader(bytes.NewReader(prefix), r.Body), r.Body}
+	return prefix, int64(len(prefix)) < n
+}
+
+// peekedBody reads the peeked bytes before the rest of the body
+type peekedBody struct {
+	io.Reader
+	io.Closer
+}
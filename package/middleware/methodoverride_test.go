@@ -2,6 +2,8 @@ package middleware_test
 
 import (
 	"bytes"
+	"errors"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -18,7 +20,7 @@ func ok() http.Handler {
 	})
 }
 
-func TestNoMethod404(t *testing.T) {
+func TestNoMethod405(t *testing.T) {
 	is := is.New(t)
 	values := url.Values{}
 	req, err := http.NewRequest(http.MethodPost, "/", bytes.NewBufferString(values.Encode()))
@@ -29,7 +31,7 @@ func TestNoMethod404(t *testing.T) {
 	router.Patch("/", ok())
 	middleware.MethodOverride().Middleware(router).ServeHTTP(w, req)
 	res := w.Result()
-	is.Equal(res.StatusCode, 404)
+	is.Equal(res.StatusCode, 405)
 }
 
 func TestPatch200(t *testing.T) {
@@ -47,7 +49,7 @@ func TestPatch200(t *testing.T) {
 	is.Equal(res.StatusCode, 200)
 }
 
-func TestPatchNoBody404(t *testing.T) {
+func TestPatchNoBody405(t *testing.T) {
 	is := is.New(t)
 	req, err := http.NewRequest(http.MethodPost, "/", nil)
 	is.NoErr(err)
@@ -57,10 +59,10 @@ func TestPatchNoBody404(t *testing.T) {
 	router.Patch("/", ok())
 	middleware.MethodOverride().Middleware(router).ServeHTTP(w, req)
 	res := w.Result()
-	is.Equal(res.StatusCode, 404)
+	is.Equal(res.StatusCode, 405)
 }
 
-func TestPatchNoType404(t *testing.T) {
+func TestPatchNoType405(t *testing.T) {
 	is := is.New(t)
 	values := url.Values{}
 	values.Set("_method", http.MethodPatch)
@@ -71,7 +73,7 @@ func TestPatchNoType404(t *testing.T) {
 	router.Patch("/", ok())
 	middleware.MethodOverride().Middleware(router).ServeHTTP(w, req)
 	res := w.Result()
-	is.Equal(res.StatusCode, 404)
+	is.Equal(res.StatusCode, 405)
 }
 
 func TestPatchInsensitive200(t *testing.T) {
@@ -119,7 +121,7 @@ func TestPut200(t *testing.T) {
 	is.Equal(res.StatusCode, 200)
 }
 
-func TestGet404(t *testing.T) {
+func TestGet405(t *testing.T) {
 	is := is.New(t)
 	values := url.Values{}
 	values.Set("_method", "get")
@@ -131,5 +133,95 @@ func TestGet404(t *testing.T) {
 	router.Get("/", ok())
 	middleware.MethodOverride().Middleware(router).ServeHTTP(w, req)
 	res := w.Result()
-	is.Equal(res.StatusCode, 404)
+	is.Equal(res.StatusCode, 405)
+}
+
+func TestPatchCharset200(t *testing.T) {
+	is := is.New(t)
+	values := url.Values{}
+	values.Set("_method", http.MethodPatch)
+	req, err := http.NewRequest(http.MethodPost, "/", bytes.NewBufferString(values.Encode()))
+	is.NoErr(err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=UTF-8")
+	w := httptest.NewRecorder()
+	router := router.New()
+	router.Patch("/", ok())
+	middleware.MethodOverride().Middleware(router).ServeHTTP(w, req)
+	res := w.Result()
+	is.Equal(res.StatusCode, 200)
+}
+
+func TestPatchMultipart200(t *testing.T) {
+	is := is.New(t)
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	is.NoErr(writer.WriteField("_method", http.MethodPatch))
+	is.NoErr(writer.WriteField("title", "hello"))
+	is.NoErr(writer.Close())
+	req, err := http.NewRequest(http.MethodPost, "/", body)
+	is.NoErr(err)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	router := router.New()
+	router.Patch("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The form is still readable after the override
+		w.Write([]byte(r.FormValue("title")))
+	}))
+	middleware.MethodOverride().Middleware(router).ServeHTTP(w, req)
+	res := w.Result()
+	is.Equal(res.StatusCode, 200)
+	is.Equal(w.Body.String(), "hello")
+}
+
+func TestPatchMultipartTooLarge(t *testing.T) {
+	is := is.New(t)
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	is.NoErr(writer.WriteField("_method", http.MethodPatch))
+	file, err := writer.CreateFormFile("avatar", "avatar.png")
+	is.NoErr(err)
+	_, err = file.Write(bytes.Repeat([]byte("a"), 1<<20))
+	is.NoErr(err)
+	is.NoErr(writer.Close())
+	req, err := http.NewRequest(http.MethodPost, "/", body)
+	is.NoErr(err)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	router := router.New()
+	router.Patch("/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The upload is still limited by the handler after the override
+		r.Body = http.MaxBytesReader(w, r.Body, 1<<10)
+		if err := r.ParseMultipartForm(1 << 10); err != nil {
+			var maxBytes *http.MaxBytesError
+			is.True(errors.As(err, &maxBytes))
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	middleware.MethodOverride().Middleware(router).ServeHTTP(w, req)
+	res := w.Result()
+	is.Equal(res.StatusCode, http.StatusRequestEntityTooLarge)
+}
+
+func TestPatchMultipartAfterFile405(t *testing.T) {
+	is := is.New(t)
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	file, err := writer.CreateFormFile("avatar", "avatar.png")
+	is.NoErr(err)
+	_, err = file.Write([]byte("avatar"))
+	is.NoErr(err)
+	is.NoErr(writer.WriteField("_method", http.MethodPatch))
+	is.NoErr(writer.Close())
+	req, err := http.NewRequest(http.MethodPost, "/", body)
+	is.NoErr(err)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+	router := router.New()
+	router.Patch("/", ok())
+	middleware.MethodOverride().Middleware(router).ServeHTTP(w, req)
+	res := w.Result()
+	// The _method field needs to come before the files
+	is.Equal(res.StatusCode, 405)
 }
@@ -16,20 +16,38 @@ func (pkg *Package) definition(name string) (decl Declaration, err error) {
 	err = fmt.Errorf("parser: unable to find declaration for %q in %q", name, pkg.Name())
 	var ts *ast.TypeSpec
 	for _, file := range pkg.Files() {
+		if decl != nil {
+			break
+		}
 		file := file
-		ast.Inspect(pkg.node, func(node ast.Node) bool {
+		ast.Inspect(file.node, func(node ast.Node) bool {
 			switch n := node.(type) {
 			case *ast.TypeSpec:
-				// TODO: handle type declarations (e.g. type A string)
 				if n.Assign == 0 {
 					ts = n
+					// Structs and interfaces are handled below
+					switch n.Type.(type) {
+					case *ast.StructType, *ast.InterfaceType:
+						return true
+					}
+				}
+				if n.Name.Name != name {
 					return true
 				}
-				decl = &Alias{
+				err = nil
+				// Aliases (e.g. type A = B)
+				if n.Assign != 0 {
+					decl = &Alias{
+						file: file,
+						ts:   n,
+					}
+					return false
+				}
+				// Other type definitions (e.g. type A []B)
+				decl = &TypeDef{
 					file: file,
 					ts:   n,
 				}
-				err = nil
 				return false
 			case *ast.StructType:
 				if ts == nil || ts.Name.Name != name {
@@ -102,6 +102,8 @@ func (k Kind) String() string {
 		return "interface"
 	case 4:
 		return "alias"
+	case 5:
+		return "typedef"
 	default:
 		return "unknown"
 	}
@@ -112,6 +114,7 @@ const (
 	KindStruct
 	KindInterface
 	KindAlias
+	KindTypeDef
 )
 
 // Declaration interface
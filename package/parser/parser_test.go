@@ -148,6 +148,132 @@ func TestAliasLookup(t *testing.T) {
 	is.Equal(method.Name(), "Middleware")
 }
 
+func TestTypeDefinition(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["app.go"] = `
+		package app
+
+		type User struct {
+			Name string
+		}
+
+		type Users []*User
+
+		type A struct {
+			Users Users
+		}
+	`
+	err := td.Write(ctx)
+	is.NoErr(err)
+	module, err := gomod.Find(dir)
+	is.NoErr(err)
+	p := parser.New(module, module)
+	pkg, err := p.Parse(".")
+	is.NoErr(err)
+	stct := pkg.Struct("A")
+	is.True(stct != nil)
+	field := stct.Field("Users")
+	is.True(field != nil)
+	def, err := field.Definition()
+	is.NoErr(err)
+	is.Equal(def.Name(), "Users")
+	is.Equal(def.Kind(), parser.KindTypeDef)
+	typeDef, ok := def.(*parser.TypeDef)
+	is.True(ok)
+	is.Equal(typeDef.Type().String(), "[]*User")
+	def, err = typeDef.Definition()
+	is.NoErr(err)
+	is.Equal(def.Name(), "User")
+	is.Equal(def.Kind(), parser.KindStruct)
+}
+
+func TestAliasDefinition(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["app.go"] = `
+		package app
+
+		type User struct {
+			Name string
+		}
+
+		type Person = User
+
+		type Admin = User
+
+		type A struct {
+			Admin Admin
+		}
+	`
+	err := td.Write(ctx)
+	is.NoErr(err)
+	module, err := gomod.Find(dir)
+	is.NoErr(err)
+	p := parser.New(module, module)
+	pkg, err := p.Parse(".")
+	is.NoErr(err)
+	stct := pkg.Struct("A")
+	is.True(stct != nil)
+	def, err := stct.Field("Admin").Definition()
+	is.NoErr(err)
+	// Aliases are found by name
+	is.Equal(def.Name(), "Admin")
+	is.Equal(def.Kind(), parser.KindAlias)
+	alias, ok := def.(*parser.Alias)
+	is.True(ok)
+	def, err = alias.Definition()
+	is.NoErr(err)
+	is.Equal(def.Name(), "User")
+	is.Equal(def.Kind(), parser.KindStruct)
+}
+
+func TestDefinitionInOtherFile(t *testing.T) {
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.Files["user.go"] = `
+		package app
+
+		import "time"
+
+		type User struct {
+			CreatedAt time.Time
+		}
+	`
+	td.Files["users.go"] = `
+		package app
+
+		type A struct {
+			User *User
+		}
+	`
+	err := td.Write(ctx)
+	is.NoErr(err)
+	module, err := gomod.Find(dir)
+	is.NoErr(err)
+	p := parser.New(module, module)
+	pkg, err := p.Parse(".")
+	is.NoErr(err)
+	stct := pkg.Struct("A")
+	is.True(stct != nil)
+	def, err := parser.Definition(stct.Field("User").Type())
+	is.NoErr(err)
+	user, ok := def.(*parser.Struct)
+	is.True(ok)
+	// Imports are resolved from the file that declares the struct
+	selector, ok := user.Field("CreatedAt").Type().(*parser.SelectorType)
+	is.True(ok)
+	importPath, err := selector.ImportPath()
+	is.NoErr(err)
+	is.Equal(importPath, "time")
+}
+
 func TestNetHTTP(t *testing.T) {
 	is := is.New(t)
 	ctx := context.Background()
@@ -215,12 +215,11 @@ func (f *Field) Name() string {
 	return f.name
 }
 
-// Embedded is true if the field is embedded
-// TODO: all fields should have a name, we should have and additional boolean to
-// determine if it's an embedded field or not.
-// func (f *Field) Embedded() bool {
-// 	return f.embedded
-// }
+// Embedded is true if the field is embedded. Embedded fields are named after
+// their type.
+func (f *Field) Embedded() bool {
+	return f.embedded
+}
 
 // Private returns true if the field is private
 func (f *Field) Private() bool {
@@ -502,6 +502,34 @@ func (t *FuncType) node() ast.Expr {
 	return t.n
 }
 
+// Params returns the types of the function's parameters
+func (t *FuncType) Params() (params []Type) {
+	return fieldTypes(t.f, t.n.Params)
+}
+
+// Results returns the types of the function's results
+func (t *FuncType) Results() (results []Type) {
+	return fieldTypes(t.f, t.n.Results)
+}
+
+// fieldTypes lists the type of each field, repeating the type of grouped
+// fields (e.g. a, b int)
+func fieldTypes(f Fielder, fields *ast.FieldList) (types []Type) {
+	if fields == nil {
+		return types
+	}
+	for _, field := range fields.List {
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			types = append(types, getType(f, field.Type))
+		}
+	}
+	return types
+}
+
 // InterfaceType struct
 type InterfaceType struct {
 	f Fielder
@@ -556,6 +584,16 @@ func (t *MapType) node() ast.Expr {
 	return t.n
 }
 
+// Key type of the map
+func (t *MapType) Key() Type {
+	return getType(t.f, t.n.Key)
+}
+
+// Value type of the map
+func (t *MapType) Value() Type {
+	return getType(t.f, t.n.Value)
+}
+
 // ChanType struct
 type ChanType struct {
 	f Fielder
@@ -574,6 +612,11 @@ func (t *ChanType) node() ast.Expr {
 	return t.n
 }
 
+// Value type sent over the channel
+func (t *ChanType) Value() Type {
+	return getType(t.f, t.n.Value)
+}
+
 // Ellipsis struct
 type EllipsisType struct {
 	f Fielder
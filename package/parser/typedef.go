@@ -0,0 +1,46 @@
+package parser
+
+import (
+	"go/ast"
+)
+
+// TypeDef is a type definition other than a struct or an interface (e.g. type
+// Users []*User). Unlike an alias, it declares a new type with the same
+// underlying type.
+type TypeDef struct {
+	file *File
+	ts   *ast.TypeSpec
+}
+
+var _ Declaration = (*TypeDef)(nil)
+
+func (t *TypeDef) File() *File {
+	return t.file
+}
+
+func (t *TypeDef) Name() string {
+	return t.ts.Name.Name
+}
+
+func (t *TypeDef) Kind() Kind {
+	return KindTypeDef
+}
+
+// Private returns true if the type is private
+func (t *TypeDef) Private() bool {
+	return isPrivate(t.ts.Name.Name)
+}
+
+func (t *TypeDef) Package() *Package {
+	return t.file.Package()
+}
+
+// Type is the underlying type
+func (t *TypeDef) Type() Type {
+	return getType(t, t.ts.Type)
+}
+
+// Definition goes to the definition of the underlying type
+func (t *TypeDef) Definition() (Declaration, error) {
+	return Definition(t.Type())
+}
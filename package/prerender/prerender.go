@@ -0,0 +1,203 @@
+package prerender
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/livebud/bud/package/router"
+)
+
+// Params of a page to prerender, keyed by the slots in its route. For example,
+// {"id": "1"} prerenders /posts/1 from /posts/:id.
+type Params map[string]string
+
+// Route to prerender. Routes with slots are prerendered once for each of
+// their params.
+type Route struct {
+	Path   string
+	Params func(ctx context.Context) ([]Params, error)
+}
+
+// New prerenderer that crawls the handler in-process
+func New(handler http.Handler, routes ...*Route) *Prerenderer {
+	return &Prerenderer{handler, routes}
+}
+
+// Prerenderer crawls the routes of an app and writes the responses to a
+// directory, following the links within each page.
+type Prerenderer struct {
+	handler http.Handler
+	routes  []*Route
+}
+
+// Write the pages and the files they link to into dir. Additional paths (e.g.
+// public files that aren't linked to) are crawled along with the routes.
+func (p *Prerenderer) Write(ctx context.Context, dir string, paths ...string) error {
+	queue, err := p.paths(ctx)
+	if err != nil {
+		return err
+	}
+	queue = append(queue, paths...)
+	seen := map[string]bool{}
+	for len(queue) > 0 {
+		urlPath := queue[0]
+		queue = queue[1:]
+		if seen[urlPath] {
+			continue
+		}
+		seen[urlPath] = true
+		links, err := p.write(ctx, dir, urlPath)
+		if err != nil {
+			return err
+		}
+		queue = append(queue, links...)
+	}
+	return nil
+}
+
+// paths lists the paths of the routes, expanding the routes with slots
+func (p *Prerenderer) paths(ctx context.Context) (paths []string, err error) {
+	for _, route := range p.routes {
+		if !hasSlots(route.Path) {
+			paths = append(paths, route.Path)
+			continue
+		}
+		// Routes with slots are only found through links
+		if route.Params == nil {
+			continue
+		}
+		list, err := route.Params(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("prerender: unable to list the params of %q. %w", route.Path, err)
+		}
+		for _, params := range list {
+			urlPath, err := fill(route.Path, params)
+			if err != nil {
+				return nil, err
+			}
+			paths = append(paths, urlPath)
+		}
+	}
+	return paths, nil
+}
+
+// write a single path into the directory, returning its links
+func (p *Prerenderer) write(ctx context.Context, dir, urlPath string) (links []string, err error) {
+	r := httptest.NewRequest(http.MethodGet, urlPath, nil).WithContext(ctx)
+	r.Header.Set("Accept", "text/html, */*")
+	w := httptest.NewRecorder()
+	p.handler.ServeHTTP(w, r)
+	switch {
+	case w.Code >= 500:
+		return nil, fmt.Errorf("prerender: GET %s returned %d. %s", urlPath, w.Code, strings.TrimSpace(w.Body.String()))
+	case w.Code != http.StatusOK:
+		// Skip redirects and missing pages
+		return nil, nil
+	}
+	mediaType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	switch mediaType {
+	case "text/html":
+		links = findLinks(urlPath, htmlLink, body)
+	case "text/javascript", "application/javascript":
+		links = findLinks(urlPath, importLink, body)
+		body = importLink.ReplaceAllStringFunc(body, func(match string) string {
+			quote := match[:1]
+			return quote + toModule(match[1:len(match)-1]) + quote
+		})
+	default:
+		// Skip responses like JSON that a static file server couldn't serve
+		// from their path
+		if path.Ext(urlPath) == "" {
+			return nil, nil
+		}
+	}
+	// Static file servers look up the unescaped path
+	name, err := url.PathUnescape(urlPath)
+	if err != nil {
+		return nil, err
+	}
+	filePath := filepath.Join(dir, filepath.FromSlash(toFile(name, mediaType)))
+	// Don't write links like /%2e%2e/x.txt outside of the directory
+	if rel, err := filepath.Rel(dir, filePath); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("prerender: unable to write %s outside of %q", urlPath, dir)
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filePath, []byte(body), 0644); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// toFile returns the file path of a URL path. Pages are written to index.html
+// files, so they can be served from their URL by a static file server.
+func toFile(urlPath, mediaType string) string {
+	switch mediaType {
+	case "text/html":
+		if path.Ext(urlPath) == ".html" {
+			return urlPath
+		}
+		return path.Join(urlPath, "index.html")
+	case "text/javascript", "application/javascript":
+		return toModule(urlPath)
+	default:
+		return urlPath
+	}
+}
+
+// toModule adds the .js extension to modules like /bud/node_modules/livebud,
+// which would otherwise conflict with modules nested within them like
+// /bud/node_modules/livebud/runtime.
+func toModule(urlPath string) string {
+	if path.Ext(urlPath) != "" {
+		return urlPath
+	}
+	return urlPath + ".js"
+}
+
+var (
+	htmlLink   = regexp.MustCompile(`(?:href|src)="([^"]+)"`)
+	importLink = regexp.MustCompile(`["'](/bud/[^"']+)["']`)
+	slot       = regexp.MustCompile(`:\w+`)
+)
+
+// findLinks finds the local links in the content, relative to the page
+func findLinks(urlPath string, pattern *regexp.Regexp, content string) (links []string) {
+	base := &url.URL{Path: urlPath}
+	for _, match := range pattern.FindAllStringSubmatch(content, -1) {
+		link, err := url.Parse(match[1])
+		if err != nil || link.Scheme != "" || link.Host != "" || link.Path == "" {
+			continue
+		}
+		links = append(links, base.ResolveReference(link).EscapedPath())
+	}
+	return links
+}
+
+func hasSlots(route string) bool {
+	return slot.MatchString(route)
+}
+
+// fill the slots in the route with the params
+func fill(route string, params Params) (string, error) {
+	values := make(map[string]interface{}, len(params))
+	for key, value := range params {
+		values[key] = value
+	}
+	urlPath, err := (&router.Route{Route: route}).URL(values)
+	if err != nil {
+		return "", fmt.Errorf("prerender: unable to fill the params of %q. %w", route, err)
+	}
+	return urlPath, nil
+}
@@ -0,0 +1,142 @@
+package prerender_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/livebud/bud/internal/is"
+	"github.com/livebud/bud/package/prerender"
+)
+
+func handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<a href="/about">about</a><a href="https://example.com">x</a><script src="/bud/view/_index.svelte.js"></script>`)
+	})
+	mux.HandleFunc("/about", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<a href="missing">missing</a>about`)
+	})
+	mux.HandleFunc("/posts/", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, "post %s", filepath.Base(r.URL.Path))
+	})
+	mux.HandleFunc("/posts", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[]`)
+	})
+	mux.HandleFunc("/bud/view/_index.svelte.js", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/javascript")
+		fmt.Fprint(w, `import "/bud/node_modules/livebud"`)
+	})
+	mux.HandleFunc("/bud/node_modules/livebud", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/javascript")
+		fmt.Fprint(w, `export default 1`)
+	})
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(w, `User-agent: *`)
+	})
+	return mux
+}
+
+func TestWrite(t *testing.T) {
+	is := is.New(t)
+	dir := t.TempDir()
+	p := prerender.New(handler(),
+		&prerender.Route{Path: "/"},
+		&prerender.Route{Path: "/posts"},
+		&prerender.Route{Path: "/posts/:id", Params: func(ctx context.Context) ([]prerender.Params, error) {
+			return []prerender.Params{{"id": "1"}, {"id": "hello world"}}, nil
+		}},
+		&prerender.Route{Path: "/users/:id"},
+	)
+	is.NoErr(p.Write(context.Background(), dir, "/robots.txt"))
+	files := map[string]string{
+		"index.html":                   `<a href="/about">about</a><a href="https://example.com">x</a><script src="/bud/view/_index.svelte.js"></script>`,
+		"about/index.html":             `<a href="missing">missing</a>about`,
+		"posts/1/index.html":           "post 1",
+		"posts/hello world/index.html": "post hello world",
+		"bud/view/_index.svelte.js":    `import "/bud/node_modules/livebud.js"`,
+		"bud/node_modules/livebud.js":  `export default 1`,
+		"robots.txt":                   `User-agent: *`,
+	}
+	for name, expect := range files {
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		is.NoErr(err)
+		is.Equal(string(data), expect)
+	}
+	// JSON responses aren't written
+	_, err := os.Stat(filepath.Join(dir, "posts", "index.html"))
+	is.True(os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(dir, "posts.json"))
+	is.True(os.IsNotExist(err))
+}
+
+func TestMissingParam(t *testing.T) {
+	is := is.New(t)
+	p := prerender.New(handler(), &prerender.Route{Path: "/posts/:id", Params: func(ctx context.Context) ([]prerender.Params, error) {
+		return []prerender.Params{{"slug": "1"}}, nil
+	}})
+	err := p.Write(context.Background(), t.TempDir())
+	is.True(err != nil)
+	is.Equal(err.Error(), `prerender: unable to fill the params of "/posts/:id". router: missing "id" parameter for "/posts/:id"`)
+}
+
+func TestConstraint(t *testing.T) {
+	is := is.New(t)
+	dir := t.TempDir()
+	p := prerender.New(handler(), &prerender.Route{Path: "/posts/:slug|[a-z-]+", Params: func(ctx context.Context) ([]prerender.Params, error) {
+		return []prerender.Params{{"slug": "hello-world"}}, nil
+	}})
+	is.NoErr(p.Write(context.Background(), dir))
+	data, err := os.ReadFile(filepath.Join(dir, "posts", "hello-world", "index.html"))
+	is.NoErr(err)
+	is.Equal(string(data), "post hello-world")
+	// Params that the route wouldn't match aren't prerendered
+	p = prerender.New(handler(), &prerender.Route{Path: "/posts/:slug|[a-z-]+", Params: func(ctx context.Context) ([]prerender.Params, error) {
+		return []prerender.Params{{"slug": "Hello World"}}, nil
+	}})
+	err = p.Write(context.Background(), t.TempDir())
+	is.True(err != nil)
+	is.Equal(err.Error(), `prerender: unable to fill the params of "/posts/:slug|[a-z-]+". router: "slug" parameter "Hello World" doesn't satisfy the "[a-z-]+" constraint of "/posts/:slug|[a-z-]+"`)
+}
+
+func TestOutsideDir(t *testing.T) {
+	is := is.New(t)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprint(w, "x")
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<a href="/%2e%2e/%2e%2e/x.txt">x</a>`)
+	})
+	root := t.TempDir()
+	dir := filepath.Join(root, "a", "b")
+	err := prerender.New(h, &prerender.Route{Path: "/"}).Write(context.Background(), dir)
+	is.True(err != nil)
+	is.Equal(err.Error(), fmt.Sprintf("prerender: unable to write /%%2e%%2e/%%2e%%2e/x.txt outside of %q", dir))
+	_, err = os.Stat(filepath.Join(root, "x.txt"))
+	is.True(os.IsNotExist(err))
+}
+
+func TestServerError(t *testing.T) {
+	is := is.New(t)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "oops", http.StatusInternalServerError)
+	})
+	err := prerender.New(h, &prerender.Route{Path: "/"}).Write(context.Background(), t.TempDir())
+	is.True(err != nil)
+	is.Equal(err.Error(), "prerender: GET / returned 500. oops")
+}
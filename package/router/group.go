@@ -0,0 +1,83 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/livebud/bud/package/middleware"
+)
+
+// Group routes under a common prefix. Each route in the group is wrapped in the
+// group's middleware, so /admin/* can require authentication without affecting
+// the rest of the routes.
+func (rt *Router) Group(prefix string, stack ...middleware.Middleware) *Group {
+	return &Group{rt, strings.TrimSuffix(prefix, "/"), stack}
+}
+
+// Group of routes that share a prefix and middleware
+type Group struct {
+	router *Router
+	prefix string
+	stack  middleware.Stack
+}
+
+// Group creates a subgroup. The subgroup's prefix is appended to this group's
+// prefix and its middleware runs after this group's middleware.
+func (g *Group) Group(prefix string, stack ...middleware.Middleware) *Group {
+	return &Group{
+		router: g.router,
+		prefix: g.prefix + strings.TrimSuffix(prefix, "/"),
+		stack:  append(append(middleware.Stack{}, g.stack...), stack...),
+	}
+}
+
+// Add a handler to a route within the group
+func (g *Group) Add(method, route string, handler http.Handler) error {
+	if !isMethod(method) {
+		return fmt.Errorf("router: %q is not a valid HTTP method", method)
+	}
+	return g.add(method, route, handler)
+}
+
+func (g *Group) add(method, route string, handler http.Handler) error {
+	// Keep the name of the original handler for generating URLs
+	name := nameOf(handler)
+	return g.router.insert(method, g.route(route), name, g.stack.Middleware(handler))
+}
+
+// Join the prefix with the route (e.g. /admin + / => /admin)
+func (g *Group) route(route string) string {
+	if g.prefix == "" {
+		return route
+	}
+	if route == "/" {
+		return g.prefix
+	}
+	return g.prefix + route
+}
+
+// Get route
+func (g *Group) Get(route string, handler http.Handler) error {
+	return g.add(http.MethodGet, route, handler)
+}
+
+// Post route
+func (g *Group) Post(route string, handler http.Handler) error {
+	return g.add(http.MethodPost, route, handler)
+}
+
+// Put route
+func (g *Group) Put(route string, handler http.Handler) error {
+	return g.add(http.MethodPut, route, handler)
+}
+
+// Patch route
+func (g *Group) Patch(route string, handler http.Handler) error {
+	return g.add(http.MethodPatch, route, handler)
+}
+
+// Delete route
+func (g *Group) Delete(route string, handler http.Handler) error {
+	return g.add(http.MethodDelete, route, handler)
+}
@@ -208,6 +208,9 @@ func lexSlotRest(l *lexer) stateFn {
 		// Support wildcard modifiers
 		l.emit(StarToken)
 		return lexStar
+	case '|':
+		// Support slot constraints
+		return lexConstraint
 	case '.', '/', end:
 		// Valid post-slot values
 		// TODO: There are probably some other characters that should be allowed.
@@ -220,6 +223,69 @@ func lexSlotRest(l *lexer) stateFn {
 	}
 }
 
+// Constraints are either named (e.g. :id|int) or a regular expression (e.g.
+// :slug|[a-z-]+). Named constraints are lowercase Latin letters and digits,
+// starting with a letter, and may be followed by the same characters as a
+// slot. Regular expressions extend to the end of the path segment.
+func lexConstraint(l *lexer) stateFn {
+	switch r := l.step(); {
+	case r == end || r == '/':
+		return l.errorf(`route %q: missing constraint after "|"`, l.input)
+	case isSlotFirst(r):
+		return lexNamedConstraint
+	default:
+		l.backup()
+		return lexPatternConstraint
+	}
+}
+
+func lexNamedConstraint(l *lexer) stateFn {
+	r := l.step()
+	for isSlotFirst(r) || ('0' <= r && r <= '9') {
+		r = l.step()
+	}
+	switch r {
+	case '?':
+		l.emit(QuestionToken)
+		return lexQuestion
+	case '*':
+		return l.errorf(`route %q: wildcard "*" can't have a constraint`, l.input)
+	case '.', '/', end:
+		l.backup()
+		l.emit(SlotToken)
+		return lexText
+	default:
+		return l.errorf(`route %q: invalid constraint character %q`, l.input, string(r))
+	}
+}
+
+func lexPatternConstraint(l *lexer) stateFn {
+	depth := 0
+	for {
+		switch r := l.step(); r {
+		case '\\':
+			// Skip over the escaped character
+			if l.step() == end {
+				return l.errorf(`route %q: unterminated escape in constraint`, l.input)
+			}
+		case '[', '(':
+			depth++
+		case ']', ')':
+			depth--
+		case '/', end:
+			if depth > 0 {
+				if r == end {
+					return l.errorf(`route %q: unterminated constraint`, l.input)
+				}
+				continue
+			}
+			l.backup()
+			l.emit(SlotToken)
+			return lexText
+		}
+	}
+}
+
 // The character after the slot can't be a character that could be in the slot
 func lexAfterSlot(l *lexer) stateFn {
 	switch r := l.peek(); r {
@@ -84,6 +84,20 @@ var tests = []struct {
 	{input: "/:id/:path*", expect: `slash:"/" slot:":id" slash:"/" star:":path*"`},
 	{input: "/v.:version*", expect: `slash:"/" path:"v." star:":version*"`},
 	{input: "/explore", expect: `slash:"/" path:"explore"`},
+	// Constraints
+	{input: "/:id|int", expect: `slash:"/" slot:":id|int"`},
+	{input: "/users/:id|int/edit", expect: `slash:"/" path:"users" slash:"/" slot:":id|int" slash:"/" path:"edit"`},
+	{input: "/users/:id|int.:format?", expect: `slash:"/" path:"users" slash:"/" slot:":id|int" path:"." question:":format?"`},
+	{input: "/:id|uuid?", expect: `slash:"/" question:":id|uuid?"`},
+	{input: "/:id|int8/edit", expect: `slash:"/" slot:":id|int8" slash:"/" path:"edit"`},
+	{input: "/:slug|[a-z-]+", expect: `slash:"/" slot:":slug|[a-z-]+"`},
+	{input: "/:slug|[a-z-]+/edit", expect: `slash:"/" slot:":slug|[a-z-]+" slash:"/" path:"edit"`},
+	{input: "/:file|[^/]+[.](js|css)", expect: `slash:"/" slot:":file|[^/]+[.](js|css)"`},
+	{input: "/:id|", err: `route "/:id|": missing constraint after "|"`},
+	{input: "/:id|/edit", err: `route "/:id|/edit": missing constraint after "|"`},
+	{input: "/:id|int*", err: `route "/:id|int*": wildcard "*" can't have a constraint`},
+	{input: "/:id|int-", err: `route "/:id|int-": invalid constraint character "-"`},
+	{input: "/:id|[0-9", err: `route "/:id|[0-9": unterminated constraint`},
 	// Must be lowercase
 	{input: "/Explore", err: `route "/Explore": uppercase letters are not allowed "E"`},
 	{input: "/eXPLORE", err: `route "/eXPLORE": uppercase letters are not allowed "X"`},
@@ -33,6 +33,20 @@ func (t Token) String() string {
 	return fmt.Sprintf("%s:%q", t.Type, t.Value)
 }
 
+// Slot returns the key and constraint of slot, question and star tokens.
+// e.g. ":id|int?" => "id", "int"
+func (t Token) Slot() (key, constraint string) {
+	value := strings.TrimPrefix(t.Value, ":")
+	if t.Type == QuestionToken || t.Type == StarToken {
+		value = value[:len(value)-1]
+	}
+	parts := strings.SplitN(value, "|", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
 // Tokens is a list of tokens
 type Tokens []Token
 
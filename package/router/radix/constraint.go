@@ -0,0 +1,98 @@
+package radix
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/livebud/bud/package/router/lex"
+)
+
+// constraintFn checks that a slot value satisfies a constraint
+type constraintFn func(value string) bool
+
+// Named constraints (e.g. /:id|int). The sized integer constraints only match
+// values that fit within their bit size (e.g. /:id|int8).
+var constraints = map[string]constraintFn{
+	"int":    intConstraint(strconv.IntSize),
+	"int8":   intConstraint(8),
+	"int16":  intConstraint(16),
+	"int32":  intConstraint(32),
+	"int64":  intConstraint(64),
+	"uint":   uintConstraint(strconv.IntSize),
+	"uint8":  uintConstraint(8),
+	"uint16": uintConstraint(16),
+	"uint32": uintConstraint(32),
+	"uint64": uintConstraint(64),
+	"uuid":   regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`).MatchString,
+}
+
+func intConstraint(bitSize int) constraintFn {
+	return func(value string) bool {
+		_, err := strconv.ParseInt(value, 10, bitSize)
+		return err == nil
+	}
+}
+
+func uintConstraint(bitSize int) constraintFn {
+	return func(value string) bool {
+		_, err := strconv.ParseUint(value, 10, bitSize)
+		return err == nil
+	}
+}
+
+// compileConstraint turns the constraint expression into a constraint function.
+// Lowercase names are looked up in the named constraints, everything else is
+// treated as a regular expression that must match the whole slot value.
+func compileConstraint(expr string) (constraintFn, error) {
+	if expr == "" {
+		return nil, nil
+	}
+	if isName(expr) {
+		fn, ok := constraints[expr]
+		if !ok {
+			return nil, fmt.Errorf("unknown constraint %q", expr)
+		}
+		return fn, nil
+	}
+	re, err := regexp.Compile(`^(?:` + expr + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid constraint %q. %w", expr, err)
+	}
+	return re.MatchString, nil
+}
+
+// Satisfies returns true if the slot value satisfies the constraint. Slots
+// without a constraint are satisfied by any value.
+func Satisfies(constraint, value string) (bool, error) {
+	satisfies, err := compileConstraint(constraint)
+	if err != nil {
+		return false, err
+	} else if satisfies == nil {
+		return true, nil
+	}
+	return satisfies(value), nil
+}
+
+// Validate the slot's constraint, if any
+func validateConstraint(route string, token lex.Token) error {
+	switch token.Type {
+	case lex.SlotToken, lex.QuestionToken:
+		_, constraint := token.Slot()
+		if _, err := compileConstraint(constraint); err != nil {
+			return fmt.Errorf("radix: route %q has an %w", route, err)
+		}
+	}
+	return nil
+}
+
+// isName returns true for named constraints, which are lowercase Latin letters
+// and digits, starting with a letter
+func isName(expr string) bool {
+	for i, r := range expr {
+		if (r < 'a' || r > 'z') && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
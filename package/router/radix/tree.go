@@ -59,6 +59,20 @@ func (n *node) isWild() bool {
 	}
 }
 
+// isConstrained returns true if the node starts with a constrained slot
+func (n *node) isConstrained() bool {
+	return n.constraint() != ""
+}
+
+// constraint of the slot that the node starts with, if any
+func (n *node) constraint() string {
+	if !n.isWild() {
+		return ""
+	}
+	_, constraint := n.tokens[0].Slot()
+	return constraint
+}
+
 // Priority of the node
 func (n *node) priority() (priority int) {
 	for _, token := range n.tokens {
@@ -79,6 +93,9 @@ func (t *tree) Insert(route string, handler http.Handler) error {
 	var tokens lex.Tokens
 	for {
 		token := lexer.Next()
+		if err := validateConstraint(route, token); err != nil {
+			return err
+		}
 		switch token.Type {
 		case lex.QuestionToken:
 			// Each optional tokens insert two routes
@@ -87,7 +104,7 @@ func (t *tree) Insert(route string, handler http.Handler) error {
 			}
 			// Make the optional token required
 			tokens = append(tokens, lex.Token{
-				Value: strings.TrimRight(token.Value, "?"),
+				Value: strings.TrimSuffix(token.Value, "?"),
 				Type:  lex.SlotToken,
 			})
 		case lex.StarToken:
@@ -256,8 +273,18 @@ func insertWild(parent *node, child *node) error {
 			parent.wilds = append(parent.wilds[:i], append([]*node{child}, parent.wilds[i:]...)...)
 			return nil
 		}
-		// Don't allow /:id and /:hi on the same level.
-		if childp == wildp {
+		if childp < wildp {
+			continue
+		}
+		// Try constrained slots before unconstrained slots, so /:id|int can
+		// fall through to /:slug.
+		childc, wildc := child.isConstrained(), wild.isConstrained()
+		if childc && !wildc {
+			parent.wilds = append(parent.wilds[:i], append([]*node{child}, parent.wilds[i:]...)...)
+			return nil
+		}
+		// Don't allow /:id and /:hi or /:a|int and /:b|int on the same level.
+		if child.constraint() == wild.constraint() {
 			return fmt.Errorf("radix: ambiguous routes %q and %q", child.route, wild.route)
 		}
 	}
@@ -327,11 +354,15 @@ func matchExact(token lex.Token) matchFn {
 
 // Match a slot (/:id)
 func matchSlot(token lex.Token) matchFn {
-	slotKey := token.Value[1:]
+	slotKey, constraint := token.Slot()
+	// Constraints have already been validated during insert
+	satisfies, _ := compileConstraint(constraint)
+	// Regular expression constraints span the whole path segment
+	spansSegment := constraint != "" && !isName(constraint)
 	return func(path string) (index int, slots Slots) {
 		lpath := len(path)
 		for i := 0; i < lpath; i++ {
-			if path[i] == '.' || path[i] == '/' {
+			if path[i] == '/' || (path[i] == '.' && !spansSegment) {
 				break
 			}
 			index++
@@ -339,6 +370,9 @@ func matchSlot(token lex.Token) matchFn {
 		if index == 0 {
 			return -1, nil
 		}
+		if satisfies != nil && !satisfies(path[:index]) {
+			return -1, nil
+		}
 		return index, Slots{{
 			Key:   slotKey,
 			Value: path[:index],
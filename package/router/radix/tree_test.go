@@ -182,6 +182,19 @@ func TestAmbiguous(t *testing.T) {
 	})
 }
 
+func TestAmbiguousConstraint(t *testing.T) {
+	ok(t, &test{
+		inserts: []*insert{
+			{route: "/:a|int"},
+			{route: "/:b|int", err: `radix: ambiguous routes "/:b|int" and "/:a|int"`},
+			{route: "/:c|uint"},
+		},
+		requests: []*request{
+			{path: "/-1", route: "/:a|int", slots: "a=-1"},
+		},
+	})
+}
+
 func TestMatch(t *testing.T) {
 	ok(t, &test{
 		inserts: []*insert{
@@ -356,6 +369,70 @@ func TestWildcard(t *testing.T) {
 	})
 }
 
+func TestConstraints(t *testing.T) {
+	okp(t, &test{
+		inserts: []*insert{
+			{route: "/users/:id|int"},
+			{route: "/users/:slug"},
+			{route: "/users/:id|int/edit"},
+			{route: "/posts/:uuid|uuid"},
+			{route: "/tags/:tag|[a-z-]+"},
+			{route: "/v.:major|uint.:minor|uint"},
+		},
+		requests: []*request{
+			{path: "/users/10", route: "/users/:id|int", slots: `id=10`},
+			{path: "/users/-10", route: "/users/:id|int", slots: `id=-10`},
+			{path: "/users/alice", route: "/users/:slug", slots: `slug=alice`},
+			{path: "/users/10/edit", route: "/users/:id|int/edit", slots: `id=10`},
+			{path: "/users/alice/edit", nomatch: true},
+			{path: "/posts/6ba7b810-9dad-11d1-80b4-00c04fd430c8", route: "/posts/:uuid|uuid", slots: `uuid=6ba7b810-9dad-11d1-80b4-00c04fd430c8`},
+			{path: "/posts/10", nomatch: true},
+			{path: "/tags/some-tag", route: "/tags/:tag|[a-z-]+", slots: `tag=some-tag`},
+			{path: "/tags/some_tag", nomatch: true},
+			{path: "/v.1.2", route: "/v.:major|uint.:minor|uint", slots: `major=1&minor=2`},
+			{path: "/v.1.x", nomatch: true},
+		},
+	})
+	ok(t, &test{
+		inserts: []*insert{
+			{route: "/:id|int.:format?"},
+		},
+		requests: []*request{
+			{path: "/10", route: "/:id|int.:format?", slots: `id=10`},
+			{path: "/10.json", route: "/:id|int.:format?", slots: `format=json&id=10`},
+			{path: "/ten.json", nomatch: true},
+		},
+	})
+	ok(t, &test{
+		inserts: []*insert{
+			{route: "/posts/:id|int8"},
+			{route: "/users/:id|uint16"},
+		},
+		requests: []*request{
+			{path: "/posts/127", route: "/posts/:id|int8", slots: `id=127`},
+			{path: "/posts/-128", route: "/posts/:id|int8", slots: `id=-128`},
+			{path: "/posts/128", nomatch: true},
+			{path: "/users/65535", route: "/users/:id|uint16", slots: `id=65535`},
+			{path: "/users/65536", nomatch: true},
+			{path: "/users/-1", nomatch: true},
+		},
+	})
+	ok(t, &test{
+		inserts: []*insert{
+			{route: "/:id|int"},
+			{route: "/:id|uuid"},
+			{route: "/:id|float", err: `radix: route "/:id|float" has an unknown constraint "float"`},
+			{route: "/:id|(*a)", err: "radix: route \"/:id|(*a)\" has an invalid constraint \"(*a)\". error parsing regexp: missing argument to repetition operator: `*`"},
+			{route: "/:id|int", err: `radix: "/:id|int" is already in the tree`},
+		},
+		requests: []*request{
+			{path: "/10", route: "/:id|int", slots: `id=10`},
+			{path: "/6ba7b810-9dad-11d1-80b4-00c04fd430c8", route: "/:id|uuid", slots: `id=6ba7b810-9dad-11d1-80b4-00c04fd430c8`},
+			{path: "/ten", nomatch: true},
+		},
+	})
+}
+
 func TestNoRoutes(t *testing.T) {
 	ok(t, &test{
 		requests: []*request{
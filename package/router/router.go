@@ -3,6 +3,7 @@ package router
 import (
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 
 	"github.com/livebud/bud/package/router/radix"
@@ -12,12 +13,38 @@ import (
 func New() *Router {
 	return &Router{
 		methods: map[string]radix.Tree{},
+		names:   map[string]*Route{},
 	}
 }
 
 // Router struct
 type Router struct {
-	methods map[string]radix.Tree
+	methods   map[string]radix.Tree
+	routes    []*Route
+	names     map[string]*Route
+	preflight http.Handler
+}
+
+// Route that's been added to the router
+type Route struct {
+	Method  string
+	Route   string
+	Name    string
+	Handler http.Handler
+}
+
+// keyer is implemented by the generated controller actions. Routes are named
+// after their action's key (e.g. /users/show).
+type keyer interface {
+	Key() string
+}
+
+// nameOf returns the name of the handler, if it has one
+func nameOf(handler http.Handler) string {
+	if keyer, ok := handler.(keyer); ok {
+		return keyer.Key()
+	}
+	return ""
 }
 
 var _ http.Handler = (*Router)(nil)
@@ -31,10 +58,45 @@ func (rt *Router) Add(method, route string, handler http.Handler) error {
 }
 
 func (rt *Router) add(method, route string, handler http.Handler) error {
+	return rt.insert(method, route, nameOf(handler), handler)
+}
+
+func (rt *Router) insert(method, route, name string, handler http.Handler) error {
 	if _, ok := rt.methods[method]; !ok {
 		rt.methods[method] = radix.New()
 	}
-	return rt.methods[method].Insert(route, handler)
+	if err := rt.methods[method].Insert(route, handler); err != nil {
+		return err
+	}
+	r := &Route{
+		Method:  method,
+		Route:   route,
+		Name:    name,
+		Handler: handler,
+	}
+	rt.routes = append(rt.routes, r)
+	// The first route with a given name is used to generate URLs
+	if _, ok := rt.names[r.Name]; r.Name != "" && !ok {
+		rt.names[r.Name] = r
+	}
+	return nil
+}
+
+// Routes returns a list of routes in the order they were added
+func (rt *Router) Routes() []*Route {
+	routes := make([]*Route, len(rt.routes))
+	copy(routes, rt.routes)
+	return routes
+}
+
+// URL generates a URL from a named route and parameters. Parameters that
+// aren't part of the route are added to the query string.
+func (rt *Router) URL(name string, params map[string]interface{}) (string, error) {
+	route, ok := rt.names[name]
+	if !ok {
+		return "", fmt.Errorf("router: no route named %q", name)
+	}
+	return route.URL(params)
 }
 
 // Get route
@@ -70,22 +132,21 @@ func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 // Middleware implements the router middleware
 func (rt *Router) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		tree, ok := rt.methods[r.Method]
-		if !ok {
-			next.ServeHTTP(w, r)
+		// Normalize the path once, so matching and the Allow header agree
+		urlPath := normalize(r.URL.Path)
+		if !rt.hasMethod(r.Method) {
+			rt.unmatched(w, r, urlPath, next)
 			return
 		}
-		urlPath := r.URL.Path
 		// Strip any trailing slash (e.g. /users/ => /users)
-		if hasTrailingSlash(urlPath) {
-			urlPath = strings.TrimRight(urlPath, "/")
+		if urlPath != r.URL.Path {
 			http.Redirect(w, r, urlPath, http.StatusPermanentRedirect)
 			return
 		}
 		// Match the path
-		match, ok := tree.Match(urlPath)
+		match, ok := rt.match(r.Method, urlPath)
 		if !ok {
-			next.ServeHTTP(w, r)
+			rt.unmatched(w, r, urlPath, next)
 			return
 		}
 		// Add the slots
@@ -101,8 +162,101 @@ func (rt *Router) Middleware(next http.Handler) http.Handler {
 	})
 }
 
-func hasTrailingSlash(path string) bool {
-	return path != "/" && strings.HasSuffix(path, "/")
+// Preflight sets the handler that's called for CORS preflight requests to
+// routes without their own OPTIONS handler. The Allow header has already been
+// set when the handler is called.
+func (rt *Router) Preflight(handler http.Handler) {
+	rt.preflight = handler
+}
+
+// hasMethod returns true if there are routes for the method. HEAD requests
+// fall back to GET routes.
+func (rt *Router) hasMethod(method string) bool {
+	if _, ok := rt.methods[method]; ok {
+		return true
+	}
+	if method == http.MethodHead {
+		return rt.hasMethod(http.MethodGet)
+	}
+	return false
+}
+
+// match the path against the routes for the method. HEAD requests fall back to
+// GET routes.
+func (rt *Router) match(method, urlPath string) (*radix.Match, bool) {
+	if tree, ok := rt.methods[method]; ok {
+		if match, ok := tree.Match(urlPath); ok {
+			return match, true
+		}
+	}
+	if method == http.MethodHead {
+		return rt.match(http.MethodGet, urlPath)
+	}
+	return nil, false
+}
+
+// unmatched handles requests that don't have a route for their method. If the
+// path matches under another method, OPTIONS requests are answered
+// automatically and every other method gets a 405 Method Not Allowed.
+// Otherwise the request is passed through to the next handler. The path has
+// already been normalized.
+func (rt *Router) unmatched(w http.ResponseWriter, r *http.Request, urlPath string, next http.Handler) {
+	allow := rt.allow(urlPath)
+	if len(allow) == 0 {
+		next.ServeHTTP(w, r)
+		return
+	}
+	w.Header().Set("Allow", strings.Join(allow, ", "))
+	if r.Method != http.MethodOptions {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	if rt.preflight != nil && isPreflight(r) {
+		rt.preflight.ServeHTTP(w, r)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// allow returns a sorted list of methods that have a route matching the path
+func (rt *Router) allow(urlPath string) (methods []string) {
+	allowed := map[string]bool{}
+	for method, tree := range rt.methods {
+		if _, ok := tree.Match(urlPath); !ok {
+			continue
+		}
+		allowed[method] = true
+		if method == http.MethodGet {
+			allowed[http.MethodHead] = true
+		}
+	}
+	if len(allowed) == 0 {
+		return nil
+	}
+	allowed[http.MethodOptions] = true
+	for method := range allowed {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// isPreflight returns true for CORS preflight requests
+func isPreflight(r *http.Request) bool {
+	return r.Method == http.MethodOptions &&
+		r.Header.Get("Origin") != "" &&
+		r.Header.Get("Access-Control-Request-Method") != ""
+}
+
+// normalize the path by stripping any trailing slashes (e.g. /users/ => /users)
+func normalize(path string) string {
+	if path == "/" || !strings.HasSuffix(path, "/") {
+		return path
+	}
+	if path = strings.TrimRight(path, "/"); path == "" {
+		return "/"
+	}
+	return path
 }
 
 // isMethod returns true if method is a valid HTTP method
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/livebud/bud/internal/is"
+	"github.com/livebud/bud/package/middleware"
 	"github.com/livebud/bud/package/router"
 )
 
@@ -29,6 +30,7 @@ type request struct {
 	// response
 	status   int
 	location string
+	allow    string
 	body     string
 }
 
@@ -82,6 +84,9 @@ func ok(t testing.TB, test *test) {
 			fmt.Println("location", url.Path)
 			is.Equal(request.location, url.Path)
 		}
+		if request.allow != "" {
+			is.Equal(request.allow, res.Header.Get("Allow"))
+		}
 		body, err := ioutil.ReadAll(res.Body)
 		is.NoErr(err)
 		is.Equal(request.body, string(body))
@@ -178,6 +183,7 @@ func TestTrailingSlash(t *testing.T) {
 			{method: "GET", path: "/", status: 200},
 			{method: "GET", path: "/hi/", status: 308, location: "/hi", body: "<a href=\"/hi\">Permanent Redirect</a>.\n\n"},
 			{method: "GET", path: "/hi///", status: 308, location: "/hi", body: "<a href=\"/hi\">Permanent Redirect</a>.\n\n"},
+			{method: "GET", path: "//", status: 308, location: "/", body: "<a href=\"/\">Permanent Redirect</a>.\n\n"},
 		},
 	})
 }
@@ -196,6 +202,109 @@ func TestInsensitive(t *testing.T) {
 	})
 }
 
+func TestConstraints(t *testing.T) {
+	ok(t, &test{
+		routes: []*route{
+			{method: "GET", route: "/users/:id|int"},
+			{method: "GET", route: "/users/:id|int/edit"},
+			{method: "GET", route: "/users/:slug|[a-z-]+"},
+			{method: "GET", route: "/users/:id|nope", err: `radix: route "/users/:id|nope" has an unknown constraint "nope"`},
+		},
+		requests: []*request{
+			{method: "GET", path: "/users/10", status: 200, body: "id=10"},
+			{method: "GET", path: "/users/10/edit", status: 200, body: "id=10"},
+			{method: "GET", path: "/users/some-user", status: 200, body: "slug=some-user"},
+			{method: "GET", path: "/users/some_user", status: 404, body: "404 page not found\n"},
+			{method: "GET", path: "/users/ten/edit", status: 404, body: "404 page not found\n"},
+		},
+	})
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	ok(t, &test{
+		routes: []*route{
+			{method: "GET", route: "/"},
+			{method: "GET", route: "/users/:id"},
+			{method: "PATCH", route: "/users/:id"},
+			{method: "DELETE", route: "/users/:id"},
+			{method: "POST", route: "/users"},
+		},
+		requests: []*request{
+			{method: "POST", path: "/", status: 405, allow: "GET, HEAD, OPTIONS", body: "Method Not Allowed\n"},
+			{method: "PUT", path: "/users/10", status: 405, allow: "DELETE, GET, HEAD, OPTIONS, PATCH", body: "Method Not Allowed\n"},
+			{method: "GET", path: "/users", status: 405, allow: "OPTIONS, POST", body: "Method Not Allowed\n"},
+			{method: "POST", path: "/posts", status: 404, body: "404 page not found\n"},
+			{method: "PUT", path: "/posts", status: 404, body: "404 page not found\n"},
+			// The Allow header is computed from the normalized path
+			{method: "PUT", path: "/users/10/", status: 405, allow: "DELETE, GET, HEAD, OPTIONS, PATCH", body: "Method Not Allowed\n"},
+			{method: "OPTIONS", path: "/users/10///", status: 204, allow: "DELETE, GET, HEAD, OPTIONS, PATCH"},
+			{method: "OPTIONS", path: "//", status: 204, allow: "GET, HEAD, OPTIONS"},
+			{method: "POST", path: "/users/10/", status: 308, location: "/users/10"},
+		},
+	})
+}
+
+func TestHead(t *testing.T) {
+	ok(t, &test{
+		routes: []*route{
+			{method: "GET", route: "/users/:id"},
+			{method: "HEAD", route: "/posts/:id"},
+			{method: "POST", route: "/comments"},
+		},
+		requests: []*request{
+			{method: "HEAD", path: "/users/10", status: 200, body: "id=10"},
+			{method: "HEAD", path: "/posts/10", status: 200, body: "id=10"},
+			{method: "HEAD", path: "/comments", status: 405, allow: "OPTIONS, POST", body: "Method Not Allowed\n"},
+			{method: "HEAD", path: "/", status: 404, body: "404 page not found\n"},
+		},
+	})
+}
+
+func TestOptions(t *testing.T) {
+	ok(t, &test{
+		routes: []*route{
+			{method: "GET", route: "/users/:id"},
+			{method: "PATCH", route: "/users/:id"},
+			{method: "OPTIONS", route: "/posts"},
+		},
+		requests: []*request{
+			{method: "OPTIONS", path: "/users/10", status: 204, allow: "GET, HEAD, OPTIONS, PATCH"},
+			{method: "OPTIONS", path: "/posts", status: 200},
+			{method: "OPTIONS", path: "/comments", status: 404, body: "404 page not found\n"},
+		},
+	})
+}
+
+func TestPreflight(t *testing.T) {
+	is := is.New(t)
+	router := router.New()
+	is.NoErr(router.Get("/users/:id", handler("/users/:id")))
+	is.NoErr(router.Patch("/users/:id", handler("/users/:id")))
+	router.Preflight(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", r.Header.Get("Origin"))
+		w.Header().Set("Access-Control-Allow-Methods", w.Header().Get("Allow"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	// Preflight request
+	req := httptest.NewRequest(http.MethodOptions, "/users/10", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPatch)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	res := rec.Result()
+	is.Equal(res.StatusCode, 204)
+	is.Equal(res.Header.Get("Access-Control-Allow-Origin"), "https://example.com")
+	is.Equal(res.Header.Get("Access-Control-Allow-Methods"), "GET, HEAD, OPTIONS, PATCH")
+	// Regular OPTIONS request
+	req = httptest.NewRequest(http.MethodOptions, "/users/10", nil)
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	res = rec.Result()
+	is.Equal(res.StatusCode, 204)
+	is.Equal(res.Header.Get("Access-Control-Allow-Origin"), "")
+	is.Equal(res.Header.Get("Allow"), "GET, HEAD, OPTIONS, PATCH")
+}
+
 func TestPut(t *testing.T) {
 	is := is.New(t)
 	router := router.New()
@@ -223,3 +332,141 @@ func TestAdd(t *testing.T) {
 	is.NoErr(err)
 	is.Equal("id=10", string(body))
 }
+
+type action struct {
+	http.Handler
+	key string
+}
+
+func (a *action) Key() string {
+	return a.key
+}
+
+func TestRoutes(t *testing.T) {
+	is := is.New(t)
+	router := router.New()
+	is.NoErr(router.Get("/", handler("/")))
+	is.NoErr(router.Get("/users/:id|int", &action{handler("/users/:id|int"), "/users/show"}))
+	is.NoErr(router.Patch("/users/:id", &action{handler("/users/:id"), "/users/update"}))
+	is.True(router.Get("/users/:id|int", handler("/users/:id|int")) != nil)
+	routes := router.Routes()
+	is.Equal(len(routes), 3)
+	is.Equal(routes[0].Method, http.MethodGet)
+	is.Equal(routes[0].Route, "/")
+	is.Equal(routes[0].Name, "")
+	is.Equal(routes[1].Method, http.MethodGet)
+	is.Equal(routes[1].Route, "/users/:id|int")
+	is.Equal(routes[1].Name, "/users/show")
+	is.Equal(routes[2].Method, http.MethodPatch)
+	is.Equal(routes[2].Route, "/users/:id")
+	is.Equal(routes[2].Name, "/users/update")
+}
+
+func TestURL(t *testing.T) {
+	is := is.New(t)
+	router := router.New()
+	is.NoErr(router.Get("/", &action{handler("/"), "/index"}))
+	is.NoErr(router.Get("/users/:id|int.:format?", &action{handler("/users/:id|int.:format?"), "/users/show"}))
+	is.NoErr(router.Get("/posts/:post_id/comments/:id/edit", &action{handler("/posts/:post_id/comments/:id/edit"), "/posts/comments/edit"}))
+	is.NoErr(router.Get("/files/:path*", &action{handler("/files/:path*"), "/files/show"}))
+	is.NoErr(router.Get("/api/v.:version?", &action{handler("/api/v.:version?"), "/api"}))
+	url, err := router.URL("/index", nil)
+	is.NoErr(err)
+	is.Equal(url, "/")
+	url, err = router.URL("/users/show", map[string]interface{}{"id": 10})
+	is.NoErr(err)
+	is.Equal(url, "/users/10")
+	url, err = router.URL("/users/show", map[string]interface{}{"id": 10, "format": "json"})
+	is.NoErr(err)
+	is.Equal(url, "/users/10.json")
+	url, err = router.URL("/users/show", map[string]interface{}{"id": 10, "page": 2, "order": "desc"})
+	is.NoErr(err)
+	is.Equal(url, "/users/10?order=desc&page=2")
+	url, err = router.URL("/posts/comments/edit", map[string]interface{}{"post_id": "a b", "id": 2})
+	is.NoErr(err)
+	is.Equal(url, "/posts/a%20b/comments/2/edit")
+	url, err = router.URL("/files/show", map[string]interface{}{"path": "a b/c.txt"})
+	is.NoErr(err)
+	is.Equal(url, "/files/a%20b/c.txt")
+	url, err = router.URL("/files/show", nil)
+	is.NoErr(err)
+	is.Equal(url, "/files")
+	url, err = router.URL("/api", nil)
+	is.NoErr(err)
+	is.Equal(url, "/api")
+	url, err = router.URL("/api", map[string]interface{}{"version": 2})
+	is.NoErr(err)
+	is.Equal(url, "/api/v.2")
+	_, err = router.URL("/posts/comments/edit", map[string]interface{}{"id": 2})
+	is.True(err != nil)
+	is.Equal(err.Error(), `router: missing "post_id" parameter for "/posts/:post_id/comments/:id/edit"`)
+	_, err = router.URL("/users/show", map[string]interface{}{"id": "abc"})
+	is.True(err != nil)
+	is.Equal(err.Error(), `router: "id" parameter "abc" doesn't satisfy the "int" constraint of "/users/:id|int.:format?"`)
+	_, err = router.URL("/unknown", nil)
+	is.True(err != nil)
+	is.Equal(err.Error(), `router: no route named "/unknown"`)
+}
+
+// Header middleware appends a value to the X-Middleware header
+func header(value string) middleware.Middleware {
+	return middleware.Function(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Middleware", value)
+			next.ServeHTTP(w, r)
+		})
+	})
+}
+
+func TestGroup(t *testing.T) {
+	is := is.New(t)
+	rt := router.New()
+	is.NoErr(rt.Get("/", handler("/")))
+	admin := rt.Group("/admin", header("admin"))
+	is.NoErr(admin.Get("/", handler("/admin")))
+	is.NoErr(admin.Get("/users/:id", &action{handler("/admin/users/:id"), "/admin/users/show"}))
+	is.True(admin.Get("/", handler("/admin")) != nil)
+	is.True(admin.Add("NOPE", "/", handler("/admin")) != nil)
+	posts := admin.Group("/posts/", header("posts"))
+	is.NoErr(posts.Post("/", handler("/admin/posts")))
+	api := rt.Group("", header("api"))
+	is.NoErr(api.Delete("/:id", handler("/:id")))
+	// Routes are prefixed
+	routes := rt.Routes()
+	is.Equal(len(routes), 5)
+	is.Equal(routes[1].Route, "/admin")
+	is.Equal(routes[2].Route, "/admin/users/:id")
+	is.Equal(routes[2].Name, "/admin/users/show")
+	is.Equal(routes[3].Route, "/admin/posts")
+	is.Equal(routes[4].Route, "/:id")
+	url, err := rt.URL("/admin/users/show", map[string]interface{}{"id": 1})
+	is.NoErr(err)
+	is.Equal(url, "/admin/users/1")
+	// Middleware only runs for routes within the group
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		values []string
+	}{
+		{http.MethodGet, "/", "", nil},
+		{http.MethodGet, "/admin", "", []string{"admin"}},
+		{http.MethodGet, "/admin/users/10", "id=10", []string{"admin"}},
+		{http.MethodPost, "/admin/posts", "", []string{"admin", "posts"}},
+		{http.MethodDelete, "/10", "id=10", []string{"api"}},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(test.method, test.path, nil)
+		rec := httptest.NewRecorder()
+		rt.ServeHTTP(rec, req)
+		res := rec.Result()
+		is.Equal(res.StatusCode, 200)
+		is.Equal(len(res.Header.Values("X-Middleware")), len(test.values))
+		for i, value := range res.Header.Values("X-Middleware") {
+			is.Equal(value, test.values[i])
+		}
+		body, err := ioutil.ReadAll(res.Body)
+		is.NoErr(err)
+		is.Equal(string(body), test.body)
+	}
+}
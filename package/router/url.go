@@ -0,0 +1,117 @@
+package router
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/livebud/bud/package/router/lex"
+	"github.com/livebud/bud/package/router/radix"
+)
+
+// URL generates a URL from the route and parameters. Parameters that aren't
+// part of the route are added to the query string.
+func (r *Route) URL(params map[string]interface{}) (string, error) {
+	routeTokens, err := lexRoute(r.Route)
+	if err != nil {
+		return "", err
+	}
+	used := map[string]bool{}
+	var tokens lex.Tokens
+	for _, token := range routeTokens {
+		switch token.Type {
+		case lex.SlotToken, lex.QuestionToken, lex.StarToken:
+			key, constraint := token.Slot()
+			value, ok := params[key]
+			if !ok {
+				if token.Type == lex.SlotToken {
+					return "", fmt.Errorf("router: missing %q parameter for %q", key, r.Route)
+				}
+				// Optional and wildcard parameters may be left out
+				tokens = stripTrail(tokens)
+				continue
+			}
+			used[key] = true
+			slot := fmt.Sprint(value)
+			// Don't generate URLs that the route wouldn't match
+			if satisfies, err := radix.Satisfies(constraint, slot); err != nil {
+				return "", fmt.Errorf("router: route %q has an %w", r.Route, err)
+			} else if !satisfies {
+				return "", fmt.Errorf("router: %q parameter %q doesn't satisfy the %q constraint of %q", key, slot, constraint, r.Route)
+			}
+			tokens = append(tokens, lex.Token{Type: token.Type, Value: escape(token, slot)})
+		default:
+			tokens = append(tokens, token)
+		}
+	}
+	return join(tokens, params, used), nil
+}
+
+func lexRoute(route string) (tokens lex.Tokens, err error) {
+	lexer := lex.New(route)
+	for {
+		token := lexer.Next()
+		switch token.Type {
+		case lex.ErrorToken:
+			return nil, errors.New(token.Value)
+		case lex.EndToken:
+			return tokens, nil
+		default:
+			tokens = append(tokens, token)
+		}
+	}
+}
+
+// Escape the slot value. Wildcards may contain slashes, so each path segment is
+// escaped separately.
+func escape(token lex.Token, value string) string {
+	if token.Type != lex.StarToken {
+		return url.PathEscape(value)
+	}
+	segments := strings.Split(value, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// stripTrail removes the path leading up to a missing optional slot. This
+// mirrors how the radix tree inserts optional routes, so /users/:id.:format?
+// becomes /users/:id and /users/v:version? becomes /users.
+func stripTrail(tokens lex.Tokens) lex.Tokens {
+	i := len(tokens) - 1
+loop:
+	for ; i >= 0; i-- {
+		switch tokens[i].Type {
+		case lex.SlotToken:
+			i++ // Include the slot
+			break loop
+		case lex.SlashToken:
+			break loop
+		}
+	}
+	if i <= 0 {
+		return tokens[:1]
+	}
+	return tokens[:i]
+}
+
+// join the tokens into a URL, adding unused parameters to the query string
+func join(tokens lex.Tokens, params map[string]interface{}, used map[string]bool) string {
+	path := new(strings.Builder)
+	for _, token := range tokens {
+		path.WriteString(token.Value)
+	}
+	query := url.Values{}
+	for key, value := range params {
+		if used[key] {
+			continue
+		}
+		query.Set(key, fmt.Sprint(value))
+	}
+	if len(query) == 0 {
+		return path.String()
+	}
+	return path.String() + "?" + query.Encode()
+}
@@ -0,0 +1,169 @@
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// CookieName of the session cookie
+const CookieName = "bud_session"
+
+// SecretEnv is the environment variable that holds the secret of the default
+// store
+const SecretEnv = "BUD_SESSION_SECRET"
+
+// maxCookieSize is the size of the largest cookie that browsers will store,
+// including the cookie's name and attributes
+const maxCookieSize = 4096
+
+// defaultSecret reads the secret from SecretEnv, falling back to a random
+// secret. Sessions signed with a random secret don't survive restarts.
+func defaultSecret() []byte {
+	if secret := os.Getenv(SecretEnv); secret != "" {
+		return []byte(secret)
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic("session: unable to generate a secret. " + err.Error())
+	}
+	return secret
+}
+
+// Cookie stores the session values in a signed cookie. Values are readable by
+// the client, but can't be changed without the secret.
+func Cookie(secret []byte) Store {
+	return &cookieStore{secret}
+}
+
+type cookieStore struct {
+	secret []byte
+}
+
+var _ Store = (*cookieStore)(nil)
+
+// ErrInvalidSignature is returned when the cookie wasn't signed by the secret
+var ErrInvalidSignature = errors.New("session: invalid signature")
+
+// ErrCookieTooLarge is returned when the session values don't fit in a cookie.
+// Use the server store to keep larger sessions.
+var ErrCookieTooLarge = errors.New("session: cookie is larger than 4KB")
+
+func (c *cookieStore) Load(w http.ResponseWriter, r *http.Request) (Values, error) {
+	value, ok := cookieValue(w, r, CookieName)
+	if !ok {
+		return Values{}, nil
+	}
+	values, err := c.decode(value)
+	if err != nil {
+		// Start over when the cookie has been tampered with
+		if errors.Is(err, ErrInvalidSignature) {
+			return Values{}, nil
+		}
+		return nil, err
+	}
+	return values, nil
+}
+
+func (c *cookieStore) Save(w http.ResponseWriter, r *http.Request, values Values) error {
+	if len(values) == 0 {
+		setCookie(w, &http.Cookie{
+			Name:   CookieName,
+			Path:   "/",
+			MaxAge: -1,
+		})
+		return nil
+	}
+	value, err := c.encode(values)
+	if err != nil {
+		return err
+	}
+	cookie := &http.Cookie{
+		Name:     CookieName,
+		Value:    value,
+		Path:     "/",
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+	// Browsers silently drop cookies that are too large
+	if len(cookie.String()) > maxCookieSize {
+		return ErrCookieTooLarge
+	}
+	setCookie(w, cookie)
+	return nil
+}
+
+func (c *cookieStore) encode(values Values) (string, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	return payload + "." + c.sign(payload), nil
+}
+
+func (c *cookieStore) decode(value string) (Values, error) {
+	i := strings.LastIndex(value, ".")
+	if i < 0 {
+		return nil, ErrInvalidSignature
+	}
+	payload, signature := value[:i], value[i+1:]
+	if !hmac.Equal([]byte(signature), []byte(c.sign(payload))) {
+		return nil, ErrInvalidSignature
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, err
+	}
+	values := Values{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func (c *cookieStore) sign(payload string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// cookieValue returns the cookie's value, preferring a cookie that has already
+// been set on the response during this request
+func cookieValue(w http.ResponseWriter, r *http.Request, name string) (string, bool) {
+	response := http.Response{Header: w.Header()}
+	for _, cookie := range response.Cookies() {
+		if cookie.Name == name {
+			return cookie.Value, cookie.MaxAge >= 0
+		}
+	}
+	cookie, err := r.Cookie(name)
+	if err != nil {
+		return "", false
+	}
+	return cookie.Value, true
+}
+
+// setCookie replaces any cookie with the same name that has already been set
+// on the response
+func setCookie(w http.ResponseWriter, cookie *http.Cookie) {
+	header := w.Header()
+	prefix := cookie.Name + "="
+	var cookies []string
+	for _, value := range header.Values("Set-Cookie") {
+		if !strings.HasPrefix(value, prefix) {
+			cookies = append(cookies, value)
+		}
+	}
+	header.Del("Set-Cookie")
+	for _, value := range cookies {
+		header.Add("Set-Cookie", value)
+	}
+	http.SetCookie(w, cookie)
+}
@@ -0,0 +1,82 @@
+package session
+
+import (
+	"net/http"
+)
+
+// flashKey stores the flash values within the session
+const flashKey = "_flash"
+
+// LoadFlash loads the flash from the session
+func LoadFlash(session *Session) *Flash {
+	return &Flash{session}
+}
+
+// Flash stores values until they're read by the next request. Use it to show
+// messages after a redirect.
+type Flash struct {
+	session *Session
+}
+
+// Set a flash value
+func (f *Flash) Set(key string, value interface{}) error {
+	values := f.values()
+	values[key] = value
+	return f.session.Set(flashKey, values)
+}
+
+// Values returns the flash values and clears them from the session
+func (f *Flash) Values() (map[string]interface{}, error) {
+	values := f.values()
+	if len(values) == 0 {
+		return values, nil
+	}
+	if err := f.session.Delete(flashKey); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func (f *Flash) values() map[string]interface{} {
+	value, ok := f.session.Get(flashKey)
+	if !ok {
+		return map[string]interface{}{}
+	}
+	switch values := value.(type) {
+	case map[string]interface{}:
+		return values
+	case Values:
+		return values
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// Props adds the flash values to the view props under the "flash" key
+func Props(w http.ResponseWriter, r *http.Request, props map[string]interface{}) map[string]interface{} {
+	session, err := Load(w, r)
+	if err != nil {
+		return props
+	}
+	values, err := LoadFlash(session).Values()
+	if err != nil || len(values) == 0 {
+		return props
+	}
+	props["flash"] = values
+	return props
+}
+
+// RedirectBack redirects to the previous page with the error message in the
+// "error" flash. If the referrer isn't set, it uses the fallback path.
+func RedirectBack(err error, fallback string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if session, loadErr := Load(w, r); loadErr == nil {
+			LoadFlash(session).Set("error", err.Error())
+		}
+		path := r.Referer()
+		if path == "" {
+			path = fallback
+		}
+		http.Redirect(w, r, path, http.StatusSeeOther)
+	})
+}
@@ -0,0 +1,127 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"sync"
+)
+
+// ServerStore stores session values on the server by session ID. Implement
+// this interface to keep sessions in a database or cache.
+type ServerStore interface {
+	Get(ctx context.Context, id string) (Values, error)
+	Set(ctx context.Context, id string, values Values) error
+	Delete(ctx context.Context, id string) error
+}
+
+// Server stores the session values in the server store. Only a random session
+// ID is stored in the cookie.
+func Server(store ServerStore) Store {
+	return &serverStore{store}
+}
+
+type serverStore struct {
+	store ServerStore
+}
+
+var _ Store = (*serverStore)(nil)
+
+func (s *serverStore) Load(w http.ResponseWriter, r *http.Request) (Values, error) {
+	id, ok := cookieValue(w, r, CookieName)
+	if !ok {
+		return Values{}, nil
+	}
+	values, err := s.store.Get(r.Context(), id)
+	if err != nil {
+		return nil, err
+	}
+	if values == nil {
+		return Values{}, nil
+	}
+	return values, nil
+}
+
+func (s *serverStore) Save(w http.ResponseWriter, r *http.Request, values Values) error {
+	id, ok := cookieValue(w, r, CookieName)
+	if len(values) == 0 {
+		if !ok {
+			return nil
+		}
+		setCookie(w, &http.Cookie{
+			Name:   CookieName,
+			Path:   "/",
+			MaxAge: -1,
+		})
+		return s.store.Delete(r.Context(), id)
+	}
+	if !ok {
+		newID, err := generateID()
+		if err != nil {
+			return err
+		}
+		id = newID
+		setCookie(w, &http.Cookie{
+			Name:     CookieName,
+			Value:    id,
+			Path:     "/",
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+	}
+	return s.store.Set(r.Context(), id, values)
+}
+
+func generateID() (string, error) {
+	id := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(id), nil
+}
+
+// Memory stores sessions in memory. Sessions are lost when the server
+// restarts, so this is mostly useful for development and testing.
+func Memory() *MemoryStore {
+	return &MemoryStore{sessions: map[string]Values{}}
+}
+
+// MemoryStore is an in-memory server store
+type MemoryStore struct {
+	mu       sync.RWMutex
+	sessions map[string]Values
+}
+
+var _ ServerStore = (*MemoryStore)(nil)
+
+func (m *MemoryStore) Get(ctx context.Context, id string) (Values, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return copyValues(m.sessions[id]), nil
+}
+
+func (m *MemoryStore) Set(ctx context.Context, id string, values Values) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[id] = copyValues(values)
+	return nil
+}
+
+func (m *MemoryStore) Delete(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+	return nil
+}
+
+func copyValues(values Values) Values {
+	if values == nil {
+		return nil
+	}
+	copy := make(Values, len(values))
+	for key, value := range values {
+		copy[key] = value
+	}
+	return copy
+}
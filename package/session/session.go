@@ -0,0 +1,101 @@
+package session
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/livebud/bud/package/middleware"
+)
+
+// Values stored in the session. Values are JSON encoded by the built-in
+// stores, so numbers are decoded as float64.
+type Values map[string]interface{}
+
+// Store loads and saves session values
+type Store interface {
+	Load(w http.ResponseWriter, r *http.Request) (Values, error)
+	Save(w http.ResponseWriter, r *http.Request, values Values) error
+}
+
+// DefaultStore is used by the session middleware and Load. Replace it to store
+// sessions elsewhere (e.g. session.DefaultStore = session.Server(redisStore)).
+var DefaultStore Store = Cookie(defaultSecret())
+
+type contextKey struct{}
+
+// state is shared by everything that loads the session during a request
+type state struct {
+	session *Session
+}
+
+// Middleware shares a single session across the request
+func Middleware() middleware.Middleware {
+	return middleware.Function(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), contextKey{}, &state{})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+}
+
+// Load the session. Requests that went through the middleware share the same
+// session.
+func Load(w http.ResponseWriter, r *http.Request) (*Session, error) {
+	state, ok := r.Context().Value(contextKey{}).(*state)
+	if ok && state.session != nil {
+		return state.session, nil
+	}
+	store := DefaultStore
+	values, err := store.Load(w, r)
+	if err != nil {
+		return nil, err
+	}
+	if values == nil {
+		values = Values{}
+	}
+	session := &Session{w, r, store, values}
+	if ok {
+		state.session = session
+	}
+	return session, nil
+}
+
+// Session is saved as soon as it changes, so changes must be made before the
+// response is written.
+type Session struct {
+	w      http.ResponseWriter
+	r      *http.Request
+	store  Store
+	values Values
+}
+
+// Get a value from the session
+func (s *Session) Get(key string) (value interface{}, ok bool) {
+	value, ok = s.values[key]
+	return value, ok
+}
+
+// Set a value in the session
+func (s *Session) Set(key string, value interface{}) error {
+	s.values[key] = value
+	return s.save()
+}
+
+// Delete a value from the session
+func (s *Session) Delete(key string) error {
+	if _, ok := s.values[key]; !ok {
+		return nil
+	}
+	delete(s.values, key)
+	return s.save()
+}
+
+// Clear all the values from the session
+func (s *Session) Clear() error {
+	s.values = Values{}
+	return s.save()
+}
+
+func (s *Session) save() error {
+	return s.store.Save(s.w, s.r, s.values)
+}
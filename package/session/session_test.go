@@ -0,0 +1,186 @@
+package session_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/livebud/bud/internal/is"
+	"github.com/livebud/bud/package/session"
+)
+
+// browser keeps the cookies between requests
+type browser struct {
+	cookies map[string]*http.Cookie
+}
+
+// request sends the cookies from the previous responses
+func (b *browser) request(prev *httptest.ResponseRecorder) *http.Request {
+	if b.cookies == nil {
+		b.cookies = map[string]*http.Cookie{}
+	}
+	if prev != nil {
+		for _, cookie := range prev.Result().Cookies() {
+			if cookie.MaxAge < 0 {
+				delete(b.cookies, cookie.Name)
+				continue
+			}
+			b.cookies[cookie.Name] = cookie
+		}
+	}
+	r := httptest.NewRequest("GET", "/", nil)
+	for _, cookie := range b.cookies {
+		r.AddCookie(cookie)
+	}
+	return r
+}
+
+func testStore(t *testing.T, store session.Store) {
+	is := is.New(t)
+	browser := new(browser)
+	defaultStore := session.DefaultStore
+	defer func() { session.DefaultStore = defaultStore }()
+	session.DefaultStore = store
+	// Empty session
+	w := httptest.NewRecorder()
+	sess, err := session.Load(w, browser.request(nil))
+	is.NoErr(err)
+	_, ok := sess.Get("name")
+	is.Equal(ok, false)
+	is.NoErr(sess.Set("name", "alice"))
+	is.NoErr(sess.Set("role", "admin"))
+	is.Equal(len(w.Result().Cookies()), 1)
+	// Next request
+	prev := w
+	w = httptest.NewRecorder()
+	sess, err = session.Load(w, browser.request(prev))
+	is.NoErr(err)
+	name, ok := sess.Get("name")
+	is.True(ok)
+	is.Equal(name, "alice")
+	is.NoErr(sess.Delete("role"))
+	// Next request
+	prev = w
+	w = httptest.NewRecorder()
+	sess, err = session.Load(w, browser.request(prev))
+	is.NoErr(err)
+	_, ok = sess.Get("role")
+	is.Equal(ok, false)
+	is.NoErr(sess.Clear())
+	cookies := w.Result().Cookies()
+	is.Equal(len(cookies), 1)
+	is.Equal(cookies[0].MaxAge, -1)
+}
+
+func TestCookieStore(t *testing.T) {
+	testStore(t, session.Cookie([]byte("secret")))
+}
+
+func TestServerStore(t *testing.T) {
+	testStore(t, session.Server(session.Memory()))
+}
+
+func TestCookieTampered(t *testing.T) {
+	is := is.New(t)
+	browser := new(browser)
+	store := session.Cookie([]byte("secret"))
+	w := httptest.NewRecorder()
+	is.NoErr(store.Save(w, browser.request(nil), session.Values{"admin": false}))
+	cookie := w.Result().Cookies()[0]
+	// Signed by a different secret
+	other := session.Cookie([]byte("other"))
+	r := httptest.NewRequest("GET", "/", nil)
+	r.AddCookie(cookie)
+	values, err := other.Load(httptest.NewRecorder(), r)
+	is.NoErr(err)
+	is.Equal(len(values), 0)
+	// Changed payload
+	r = httptest.NewRequest("GET", "/", nil)
+	signature := cookie.Value[strings.LastIndex(cookie.Value, "."):]
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"admin":true}`))
+	r.AddCookie(&http.Cookie{Name: cookie.Name, Value: payload + signature})
+	values, err = store.Load(httptest.NewRecorder(), r)
+	is.NoErr(err)
+	is.Equal(len(values), 0)
+}
+
+func TestCookieTooLarge(t *testing.T) {
+	is := is.New(t)
+	browser := new(browser)
+	store := session.Cookie([]byte("secret"))
+	w := httptest.NewRecorder()
+	err := store.Save(w, browser.request(nil), session.Values{"notes": strings.Repeat("a", 4096)})
+	is.True(errors.Is(err, session.ErrCookieTooLarge))
+	is.Equal(len(w.Result().Cookies()), 0)
+	// The server store only keeps the ID in the cookie
+	store = session.Server(session.Memory())
+	w = httptest.NewRecorder()
+	is.NoErr(store.Save(w, browser.request(nil), session.Values{"notes": strings.Repeat("a", 4096)}))
+	is.Equal(len(w.Result().Cookies()), 1)
+}
+
+func TestMiddlewareShares(t *testing.T) {
+	is := is.New(t)
+	browser := new(browser)
+	handler := session.Middleware().Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		a, err := session.Load(w, r)
+		is.NoErr(err)
+		is.NoErr(a.Set("a", "1"))
+		b, err := session.Load(w, r)
+		is.NoErr(err)
+		is.NoErr(b.Set("b", "2"))
+		is.True(a == b)
+	}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, browser.request(nil))
+	// Only the latest cookie is sent
+	is.Equal(len(w.Header().Values("Set-Cookie")), 1)
+	sess, err := session.Load(httptest.NewRecorder(), browser.request(w))
+	is.NoErr(err)
+	a, _ := sess.Get("a")
+	b, _ := sess.Get("b")
+	is.Equal(a, "1")
+	is.Equal(b, "2")
+}
+
+func TestFlash(t *testing.T) {
+	is := is.New(t)
+	browser := new(browser)
+	w := httptest.NewRecorder()
+	sess, err := session.Load(w, browser.request(nil))
+	is.NoErr(err)
+	flash := session.LoadFlash(sess)
+	is.NoErr(flash.Set("notice", "Post created"))
+	is.NoErr(sess.Set("user", "alice"))
+	// Next request reads the flash into the props
+	prev := w
+	w = httptest.NewRecorder()
+	props := session.Props(w, browser.request(prev), map[string]interface{}{"post": "p"})
+	is.Equal(props["post"], "p")
+	is.Equal(props["flash"], map[string]interface{}{"notice": "Post created"})
+	// The flash is gone, but the session remains
+	prev = w
+	w = httptest.NewRecorder()
+	props = session.Props(w, browser.request(prev), map[string]interface{}{})
+	is.Equal(len(props), 0)
+	sess, err = session.Load(w, browser.request(prev))
+	is.NoErr(err)
+	user, _ := sess.Get("user")
+	is.Equal(user, "alice")
+}
+
+func TestRedirectBack(t *testing.T) {
+	is := is.New(t)
+	browser := new(browser)
+	r := httptest.NewRequest("POST", "/posts", nil)
+	r.Header.Set("Referer", "/posts/new")
+	w := httptest.NewRecorder()
+	session.RedirectBack(http.ErrBodyNotAllowed, "/posts").ServeHTTP(w, r)
+	is.Equal(w.Code, http.StatusSeeOther)
+	is.Equal(w.Header().Get("Location"), "/posts/new")
+	props := session.Props(httptest.NewRecorder(), browser.request(w), map[string]interface{}{})
+	is.Equal(props["flash"], map[string]interface{}{"error": http.ErrBodyNotAllowed.Error()})
+}
@@ -0,0 +1,95 @@
+package vue
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	_ "embed"
+
+	esbuild "github.com/evanw/esbuild/pkg/api"
+	"github.com/livebud/bud/package/gomod"
+	"github.com/livebud/bud/package/js"
+)
+
+// compiler.ts is used to compile .vue files into JS & CSS
+//
+//go:embed compiler.ts
+var compiler string
+
+// Load the compiler into the VM. Unlike Svelte, the compiler is bundled from
+// the application's node_modules, because the compiler needs to match the
+// version of Vue that renders the components.
+func Load(vm js.VM, module *gomod.Module) (*Compiler, error) {
+	result := esbuild.Build(esbuild.BuildOptions{
+		Stdin: &esbuild.StdinOptions{
+			Contents:   compiler,
+			ResolveDir: module.Directory(),
+			Sourcefile: "vue/compiler.ts",
+			Loader:     esbuild.LoaderTS,
+		},
+		Format:     esbuild.FormatIIFE,
+		GlobalName: "__vue__",
+		Platform:   esbuild.PlatformBrowser,
+		Define: map[string]string{
+			"process.env.NODE_ENV": `"production"`,
+		},
+		Bundle: true,
+	})
+	if len(result.Errors) > 0 {
+		msgs := esbuild.FormatMessages(result.Errors, esbuild.FormatMessagesOptions{
+			Color:         true,
+			Kind:          esbuild.ErrorMessage,
+			TerminalWidth: 80,
+		})
+		return nil, fmt.Errorf("vue: unable to load the compiler. %s", strings.Join(msgs, "\n"))
+	}
+	if err := vm.Script("vue/compiler.js", string(result.OutputFiles[0].Contents)); err != nil {
+		return nil, err
+	}
+	// TODO make dev configurable
+	return &Compiler{vm, true}, nil
+}
+
+type Compiler struct {
+	VM  js.VM
+	Dev bool
+}
+
+type SSR struct {
+	JS  string
+	CSS string
+}
+
+// Compile server-rendered code
+func (c *Compiler) SSR(path string, code []byte) (*SSR, error) {
+	expr := fmt.Sprintf(`;__vue__.compile({ "path": %q, "code": %q, "target": "ssr", "dev": %t })`, path, code, c.Dev)
+	result, err := c.VM.Eval(path, expr)
+	if err != nil {
+		return nil, err
+	}
+	out := new(SSR)
+	if err := json.Unmarshal([]byte(result), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type DOM struct {
+	JS  string
+	CSS string
+}
+
+// Compile DOM code
+func (c *Compiler) DOM(path string, code []byte) (*DOM, error) {
+	expr := fmt.Sprintf(`;__vue__.compile({ "path": %q, "code": %q, "target": "dom", "dev": %t })`, path, code, c.Dev)
+	result, err := c.VM.Eval(path, expr)
+	if err != nil {
+		return nil, err
+	}
+	out := new(DOM)
+	if err := json.Unmarshal([]byte(result), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,126 @@
+import {
+  parse,
+  compileScript,
+  compileTemplate,
+  compileStyle,
+  rewriteDefault,
+} from "vue/compiler-sfc"
+
+type Input = {
+  code: string
+  path: string
+  target: "ssr" | "dom"
+  dev: boolean
+}
+
+// Capitalized for Go
+type Output = {
+  JS: string
+  CSS: string
+}
+
+// Compile a Vue single-file component into an ES module
+export function compile(input: Input): string {
+  const { code, path, target, dev } = input
+  const { descriptor, errors } = parse(code, { filename: path, sourceMap: false })
+  if (errors.length > 0) {
+    throw errors[0]
+  }
+  const id = hash(path)
+  const scopeId = `data-v-${id}`
+  const scoped = descriptor.styles.some((style) => style.scoped)
+  const ssr = target === "ssr"
+  let js = ""
+  let bindings: Record<string, any> | undefined
+  if (descriptor.script || descriptor.scriptSetup) {
+    const script = compileScript(descriptor, { id, isProd: !dev })
+    bindings = script.bindings
+    js += rewriteDefault(script.content, "__component__") + "\n"
+  } else {
+    js += "const __component__ = {}\n"
+  }
+  if (descriptor.template) {
+    const template = compileTemplate({
+      id,
+      filename: path,
+      source: descriptor.template.content,
+      scoped,
+      ssr,
+      isProd: !dev,
+      compilerOptions: {
+        bindingMetadata: bindings,
+        scopeId: scoped ? scopeId : undefined,
+      },
+    })
+    if (template.errors.length > 0) {
+      throw toError(template.errors[0])
+    }
+    // Attach the render function to the component rather than exporting it
+    js += template.code.replace(/\bexport (function|const) (ssrRender|render)\b/, "$1 $2") + "\n"
+    js += ssr ? "__component__.ssrRender = ssrRender\n" : "__component__.render = render\n"
+  }
+  if (scoped) {
+    js += `__component__.__scopeId = ${JSON.stringify(scopeId)}\n`
+  }
+  let css = ""
+  for (let style of descriptor.styles) {
+    const compiled = compileStyle({
+      id: scopeId,
+      filename: path,
+      source: style.content,
+      scoped: style.scoped,
+      isProd: !dev,
+    })
+    if (compiled.errors.length > 0) {
+      throw toError(compiled.errors[0])
+    }
+    css += compiled.code
+  }
+  if (css) {
+    js += ssr ? ssrStyle(css) : domStyle(id, css)
+  }
+  js += "export default __component__\n"
+  return JSON.stringify({ JS: js, CSS: css } as Output)
+}
+
+// Collect the styles of the components rendered on the server, so they can be
+// written into the page
+function ssrStyle(css: string): string {
+  return `
+import { useSSRContext as __useSSRContext__ } from "vue"
+const __setup__ = __component__.setup
+__component__.setup = (props, ctx) => {
+  const ssrContext = __useSSRContext__()
+  if (ssrContext) (ssrContext.css || (ssrContext.css = new Set())).add(${JSON.stringify(css)})
+  return __setup__ ? __setup__(props, ctx) : undefined
+}
+`
+}
+
+// Add the styles to the document, replacing the previous styles on live reload
+function domStyle(id: string, css: string): string {
+  return `
+if (typeof document !== "undefined") {
+  let style = document.querySelector('style[data-vue="${id}"]')
+  if (!style) {
+    style = document.createElement("style")
+    style.setAttribute("data-vue", "${id}")
+    document.head.appendChild(style)
+  }
+  style.textContent = ${JSON.stringify(css)}
+}
+`
+}
+
+// hash the path into an ID that's the same for the server and the browser
+function hash(path: string): string {
+  let h = 5381
+  for (let i = 0; i < path.length; i++) {
+    h = ((h << 5) + h + path.charCodeAt(i)) | 0
+  }
+  return (h >>> 0).toString(16)
+}
+
+function toError(err: string | Error): Error {
+  return typeof err === "string" ? new Error(err) : err
+}
@@ -0,0 +1,69 @@
+package vue_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/livebud/bud/internal/is"
+	"github.com/livebud/bud/internal/testdir"
+	"github.com/livebud/bud/internal/versions"
+	"github.com/livebud/bud/package/gomod"
+	v8 "github.com/livebud/bud/package/js/v8"
+	"github.com/livebud/bud/package/vue"
+)
+
+func load(t testing.TB) *vue.Compiler {
+	t.Helper()
+	is := is.New(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	td := testdir.New(dir)
+	td.NodeModules["vue"] = versions.Vue
+	is.NoErr(td.Write(ctx))
+	module, err := gomod.Find(dir)
+	is.NoErr(err)
+	vm, err := v8.Load()
+	is.NoErr(err)
+	compiler, err := vue.Load(vm, module)
+	is.NoErr(err)
+	return compiler
+}
+
+func TestSSR(t *testing.T) {
+	is := is.New(t)
+	compiler := load(t)
+	ssr, err := compiler.SSR("test.vue", []byte(`<template><h1>hi world!</h1></template>`))
+	is.NoErr(err)
+	is.True(strings.Contains(ssr.JS, `from "vue/server-renderer"`))
+	is.True(strings.Contains(ssr.JS, `<h1>hi world!</h1>`))
+}
+
+func TestDOM(t *testing.T) {
+	is := is.New(t)
+	compiler := load(t)
+	dom, err := compiler.DOM("test.vue", []byte(`<template><h1>hi world!</h1></template>`))
+	is.NoErr(err)
+	is.True(strings.Contains(dom.JS, `from "vue"`))
+	is.True(strings.Contains(dom.JS, `"hi world!"`))
+}
+
+func TestScopedStyle(t *testing.T) {
+	is := is.New(t)
+	compiler := load(t)
+	ssr, err := compiler.SSR("test.vue", []byte(`
+		<template><h1>hi world!</h1></template>
+		<style scoped>h1 { color: red }</style>
+	`))
+	is.NoErr(err)
+	is.True(strings.Contains(ssr.CSS, `h1[data-v-`))
+	is.True(strings.Contains(ssr.CSS, `color: red`))
+}
+
+func TestSSRError(t *testing.T) {
+	is := is.New(t)
+	compiler := load(t)
+	ssr, err := compiler.SSR("test.vue", []byte(`<template><h1>hi world!</h2></template>`))
+	is.True(err != nil)
+	is.Equal(ssr, nil)
+}